@@ -0,0 +1,8 @@
+package v1
+
+import _ "embed"
+
+// OpenAPISpec is the OpenAPI v2 document generated from ticketscoring.proto by protoc-gen-openapiv2.
+//
+//go:embed ticketscoring.swagger.json
+var OpenAPISpec []byte
@@ -7,6 +7,7 @@
 package v1
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
@@ -22,6 +23,108 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ReportType selects which report ExportScores writes.
+type ReportType int32
+
+const (
+	ReportType_REPORT_TYPE_UNSPECIFIED ReportType = 0
+	// One row per category and period: category, total_ratings, overall_category_score, period, period_score.
+	ReportType_REPORT_TYPE_AGGREGATED_CATEGORY_SCORES ReportType = 1
+	// One row per ticket and category: ticket_id, category, score.
+	ReportType_REPORT_TYPE_SCORES_BY_TICKET ReportType = 2
+)
+
+// Enum value maps for ReportType.
+var (
+	ReportType_name = map[int32]string{
+		0: "REPORT_TYPE_UNSPECIFIED",
+		1: "REPORT_TYPE_AGGREGATED_CATEGORY_SCORES",
+		2: "REPORT_TYPE_SCORES_BY_TICKET",
+	}
+	ReportType_value = map[string]int32{
+		"REPORT_TYPE_UNSPECIFIED":                0,
+		"REPORT_TYPE_AGGREGATED_CATEGORY_SCORES": 1,
+		"REPORT_TYPE_SCORES_BY_TICKET":           2,
+	}
+)
+
+func (x ReportType) Enum() *ReportType {
+	p := new(ReportType)
+	*p = x
+	return p
+}
+
+func (x ReportType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReportType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_ticketscoring_proto_enumTypes[0].Descriptor()
+}
+
+func (ReportType) Type() protoreflect.EnumType {
+	return &file_api_v1_ticketscoring_proto_enumTypes[0]
+}
+
+func (x ReportType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReportType.Descriptor instead.
+func (ReportType) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_ticketscoring_proto_rawDescGZIP(), []int{0}
+}
+
+type ExportFormat int32
+
+const (
+	// Defaults to CSV.
+	ExportFormat_EXPORT_FORMAT_UNSPECIFIED ExportFormat = 0
+	ExportFormat_EXPORT_FORMAT_CSV         ExportFormat = 1
+	ExportFormat_EXPORT_FORMAT_PARQUET     ExportFormat = 2
+)
+
+// Enum value maps for ExportFormat.
+var (
+	ExportFormat_name = map[int32]string{
+		0: "EXPORT_FORMAT_UNSPECIFIED",
+		1: "EXPORT_FORMAT_CSV",
+		2: "EXPORT_FORMAT_PARQUET",
+	}
+	ExportFormat_value = map[string]int32{
+		"EXPORT_FORMAT_UNSPECIFIED": 0,
+		"EXPORT_FORMAT_CSV":         1,
+		"EXPORT_FORMAT_PARQUET":     2,
+	}
+)
+
+func (x ExportFormat) Enum() *ExportFormat {
+	p := new(ExportFormat)
+	*p = x
+	return p
+}
+
+func (x ExportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_ticketscoring_proto_enumTypes[1].Descriptor()
+}
+
+func (ExportFormat) Type() protoreflect.EnumType {
+	return &file_api_v1_ticketscoring_proto_enumTypes[1]
+}
+
+func (x ExportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExportFormat.Descriptor instead.
+func (ExportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_ticketscoring_proto_rawDescGZIP(), []int{1}
+}
+
 type TimePeriodRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	StartDate     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
@@ -438,11 +541,125 @@ func (x *AggregatedCategoryScoresResponse) GetCategoryScores() []*CategoryScore
 	return nil
 }
 
+type ExportScoresRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	ReportType    ReportType             `protobuf:"varint,3,opt,name=report_type,json=reportType,proto3,enum=ticketscoring.v1.ReportType" json:"report_type,omitempty"`
+	Format        ExportFormat           `protobuf:"varint,4,opt,name=format,proto3,enum=ticketscoring.v1.ExportFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportScoresRequest) Reset() {
+	*x = ExportScoresRequest{}
+	mi := &file_api_v1_ticketscoring_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportScoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportScoresRequest) ProtoMessage() {}
+
+func (x *ExportScoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ticketscoring_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportScoresRequest.ProtoReflect.Descriptor instead.
+func (*ExportScoresRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_ticketscoring_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ExportScoresRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *ExportScoresRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *ExportScoresRequest) GetReportType() ReportType {
+	if x != nil {
+		return x.ReportType
+	}
+	return ReportType_REPORT_TYPE_UNSPECIFIED
+}
+
+func (x *ExportScoresRequest) GetFormat() ExportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ExportFormat_EXPORT_FORMAT_UNSPECIFIED
+}
+
+// ExportChunk is a slice of the encoded file; concatenating the data of all chunks
+// in order yields the complete CSV or Parquet document.
+type ExportChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChunk) Reset() {
+	*x = ExportChunk{}
+	mi := &file_api_v1_ticketscoring_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChunk) ProtoMessage() {}
+
+func (x *ExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_ticketscoring_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChunk.ProtoReflect.Descriptor instead.
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return file_api_v1_ticketscoring_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 var File_api_v1_ticketscoring_proto protoreflect.FileDescriptor
 
 const file_api_v1_ticketscoring_proto_rawDesc = "" +
 	"\n" +
-	"\x1aapi/v1/ticketscoring.proto\x12\x10ticketscoring.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x85\x01\n" +
+	"\x1aapi/v1/ticketscoring.proto\x12\x10ticketscoring.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x85\x01\n" +
 	"\x11TimePeriodRequest\x129\n" +
 	"\n" +
 	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
@@ -470,12 +687,31 @@ const file_api_v1_ticketscoring_proto_rawDesc = "" +
 	"\x16overall_category_score\x18\x03 \x01(\x01R\x14overallCategoryScore\x12B\n" +
 	"\rperiod_scores\x18\x04 \x03(\v2\x1d.ticketscoring.v1.PeriodScoreR\fperiodScores\"l\n" +
 	" AggregatedCategoryScoresResponse\x12H\n" +
-	"\x0fcategory_scores\x18\x01 \x03(\v2\x1f.ticketscoring.v1.CategoryScoreR\x0ecategoryScores2\xd7\x03\n" +
-	"\rTicketScoring\x12l\n" +
-	"\x16GetOverallQualityScore\x12#.ticketscoring.v1.TimePeriodRequest\x1a-.ticketscoring.v1.OverallQualityScoreResponse\x12v\n" +
-	"\x1bGetAggregatedCategoryScores\x12#.ticketscoring.v1.TimePeriodRequest\x1a2.ticketscoring.v1.AggregatedCategoryScoresResponse\x12b\n" +
-	"\x11GetScoresByTicket\x12#.ticketscoring.v1.TimePeriodRequest\x1a(.ticketscoring.v1.ScoresByTicketResponse\x12|\n" +
-	"\x1eGetPeriodOverPeriodScoreChange\x12#.ticketscoring.v1.TimePeriodRequest\x1a5.ticketscoring.v1.PeriodOverPeriodScoreChangeResponseB&Z$github.com/godilite/qa-server/api/v1b\x06proto3"
+	"\x0fcategory_scores\x18\x01 \x03(\v2\x1f.ticketscoring.v1.CategoryScoreR\x0ecategoryScores\"\xfe\x01\n" +
+	"\x13ExportScoresRequest\x129\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12=\n" +
+	"\vreport_type\x18\x03 \x01(\x0e2\x1c.ticketscoring.v1.ReportTypeR\n" +
+	"reportType\x126\n" +
+	"\x06format\x18\x04 \x01(\x0e2\x1e.ticketscoring.v1.ExportFormatR\x06format\"!\n" +
+	"\vExportChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data*w\n" +
+	"\n" +
+	"ReportType\x12\x1b\n" +
+	"\x17REPORT_TYPE_UNSPECIFIED\x10\x00\x12*\n" +
+	"&REPORT_TYPE_AGGREGATED_CATEGORY_SCORES\x10\x01\x12 \n" +
+	"\x1cREPORT_TYPE_SCORES_BY_TICKET\x10\x02*_\n" +
+	"\fExportFormat\x12\x1d\n" +
+	"\x19EXPORT_FORMAT_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11EXPORT_FORMAT_CSV\x10\x01\x12\x19\n" +
+	"\x15EXPORT_FORMAT_PARQUET\x10\x022\xa4\x05\n" +
+	"\rTicketScoring\x12\x88\x01\n" +
+	"\x16GetOverallQualityScore\x12#.ticketscoring.v1.TimePeriodRequest\x1a-.ticketscoring.v1.OverallQualityScoreResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/scores/overall\x12\x95\x01\n" +
+	"\x1bGetAggregatedCategoryScores\x12#.ticketscoring.v1.TimePeriodRequest\x1a2.ticketscoring.v1.AggregatedCategoryScoresResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/scores/categories\x12~\n" +
+	"\x11GetScoresByTicket\x12#.ticketscoring.v1.TimePeriodRequest\x1a(.ticketscoring.v1.ScoresByTicketResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/scores/tickets\x12\x97\x01\n" +
+	"\x1eGetPeriodOverPeriodScoreChange\x12#.ticketscoring.v1.TimePeriodRequest\x1a5.ticketscoring.v1.PeriodOverPeriodScoreChangeResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/scores/change\x12V\n" +
+	"\fExportScores\x12%.ticketscoring.v1.ExportScoresRequest\x1a\x1d.ticketscoring.v1.ExportChunk0\x01B&Z$github.com/godilite/qa-server/api/v1b\x06proto3"
 
 var (
 	file_api_v1_ticketscoring_proto_rawDescOnce sync.Once
@@ -489,39 +725,50 @@ func file_api_v1_ticketscoring_proto_rawDescGZIP() []byte {
 	return file_api_v1_ticketscoring_proto_rawDescData
 }
 
-var file_api_v1_ticketscoring_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_api_v1_ticketscoring_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_v1_ticketscoring_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_api_v1_ticketscoring_proto_goTypes = []any{
-	(*TimePeriodRequest)(nil),                   // 0: ticketscoring.v1.TimePeriodRequest
-	(*OverallQualityScoreResponse)(nil),         // 1: ticketscoring.v1.OverallQualityScoreResponse
-	(*PeriodScore)(nil),                         // 2: ticketscoring.v1.PeriodScore
-	(*TicketScore)(nil),                         // 3: ticketscoring.v1.TicketScore
-	(*ScoresByTicketResponse)(nil),              // 4: ticketscoring.v1.ScoresByTicketResponse
-	(*PeriodOverPeriodScoreChangeResponse)(nil), // 5: ticketscoring.v1.PeriodOverPeriodScoreChangeResponse
-	(*CategoryScore)(nil),                       // 6: ticketscoring.v1.CategoryScore
-	(*AggregatedCategoryScoresResponse)(nil),    // 7: ticketscoring.v1.AggregatedCategoryScoresResponse
-	nil,                                         // 8: ticketscoring.v1.TicketScore.CategoryScoresEntry
-	(*timestamppb.Timestamp)(nil),               // 9: google.protobuf.Timestamp
+	(ReportType)(0),                             // 0: ticketscoring.v1.ReportType
+	(ExportFormat)(0),                           // 1: ticketscoring.v1.ExportFormat
+	(*TimePeriodRequest)(nil),                   // 2: ticketscoring.v1.TimePeriodRequest
+	(*OverallQualityScoreResponse)(nil),         // 3: ticketscoring.v1.OverallQualityScoreResponse
+	(*PeriodScore)(nil),                         // 4: ticketscoring.v1.PeriodScore
+	(*TicketScore)(nil),                         // 5: ticketscoring.v1.TicketScore
+	(*ScoresByTicketResponse)(nil),              // 6: ticketscoring.v1.ScoresByTicketResponse
+	(*PeriodOverPeriodScoreChangeResponse)(nil), // 7: ticketscoring.v1.PeriodOverPeriodScoreChangeResponse
+	(*CategoryScore)(nil),                       // 8: ticketscoring.v1.CategoryScore
+	(*AggregatedCategoryScoresResponse)(nil),    // 9: ticketscoring.v1.AggregatedCategoryScoresResponse
+	(*ExportScoresRequest)(nil),                 // 10: ticketscoring.v1.ExportScoresRequest
+	(*ExportChunk)(nil),                         // 11: ticketscoring.v1.ExportChunk
+	nil,                                         // 12: ticketscoring.v1.TicketScore.CategoryScoresEntry
+	(*timestamppb.Timestamp)(nil),               // 13: google.protobuf.Timestamp
 }
 var file_api_v1_ticketscoring_proto_depIdxs = []int32{
-	9,  // 0: ticketscoring.v1.TimePeriodRequest.start_date:type_name -> google.protobuf.Timestamp
-	9,  // 1: ticketscoring.v1.TimePeriodRequest.end_date:type_name -> google.protobuf.Timestamp
-	8,  // 2: ticketscoring.v1.TicketScore.category_scores:type_name -> ticketscoring.v1.TicketScore.CategoryScoresEntry
-	3,  // 3: ticketscoring.v1.ScoresByTicketResponse.ticket_scores:type_name -> ticketscoring.v1.TicketScore
-	2,  // 4: ticketscoring.v1.CategoryScore.period_scores:type_name -> ticketscoring.v1.PeriodScore
-	6,  // 5: ticketscoring.v1.AggregatedCategoryScoresResponse.category_scores:type_name -> ticketscoring.v1.CategoryScore
-	0,  // 6: ticketscoring.v1.TicketScoring.GetOverallQualityScore:input_type -> ticketscoring.v1.TimePeriodRequest
-	0,  // 7: ticketscoring.v1.TicketScoring.GetAggregatedCategoryScores:input_type -> ticketscoring.v1.TimePeriodRequest
-	0,  // 8: ticketscoring.v1.TicketScoring.GetScoresByTicket:input_type -> ticketscoring.v1.TimePeriodRequest
-	0,  // 9: ticketscoring.v1.TicketScoring.GetPeriodOverPeriodScoreChange:input_type -> ticketscoring.v1.TimePeriodRequest
-	1,  // 10: ticketscoring.v1.TicketScoring.GetOverallQualityScore:output_type -> ticketscoring.v1.OverallQualityScoreResponse
-	7,  // 11: ticketscoring.v1.TicketScoring.GetAggregatedCategoryScores:output_type -> ticketscoring.v1.AggregatedCategoryScoresResponse
-	4,  // 12: ticketscoring.v1.TicketScoring.GetScoresByTicket:output_type -> ticketscoring.v1.ScoresByTicketResponse
-	5,  // 13: ticketscoring.v1.TicketScoring.GetPeriodOverPeriodScoreChange:output_type -> ticketscoring.v1.PeriodOverPeriodScoreChangeResponse
-	10, // [10:14] is the sub-list for method output_type
-	6,  // [6:10] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	13, // 0: ticketscoring.v1.TimePeriodRequest.start_date:type_name -> google.protobuf.Timestamp
+	13, // 1: ticketscoring.v1.TimePeriodRequest.end_date:type_name -> google.protobuf.Timestamp
+	12, // 2: ticketscoring.v1.TicketScore.category_scores:type_name -> ticketscoring.v1.TicketScore.CategoryScoresEntry
+	5,  // 3: ticketscoring.v1.ScoresByTicketResponse.ticket_scores:type_name -> ticketscoring.v1.TicketScore
+	4,  // 4: ticketscoring.v1.CategoryScore.period_scores:type_name -> ticketscoring.v1.PeriodScore
+	8,  // 5: ticketscoring.v1.AggregatedCategoryScoresResponse.category_scores:type_name -> ticketscoring.v1.CategoryScore
+	13, // 6: ticketscoring.v1.ExportScoresRequest.start_date:type_name -> google.protobuf.Timestamp
+	13, // 7: ticketscoring.v1.ExportScoresRequest.end_date:type_name -> google.protobuf.Timestamp
+	0,  // 8: ticketscoring.v1.ExportScoresRequest.report_type:type_name -> ticketscoring.v1.ReportType
+	1,  // 9: ticketscoring.v1.ExportScoresRequest.format:type_name -> ticketscoring.v1.ExportFormat
+	2,  // 10: ticketscoring.v1.TicketScoring.GetOverallQualityScore:input_type -> ticketscoring.v1.TimePeriodRequest
+	2,  // 11: ticketscoring.v1.TicketScoring.GetAggregatedCategoryScores:input_type -> ticketscoring.v1.TimePeriodRequest
+	2,  // 12: ticketscoring.v1.TicketScoring.GetScoresByTicket:input_type -> ticketscoring.v1.TimePeriodRequest
+	2,  // 13: ticketscoring.v1.TicketScoring.GetPeriodOverPeriodScoreChange:input_type -> ticketscoring.v1.TimePeriodRequest
+	10, // 14: ticketscoring.v1.TicketScoring.ExportScores:input_type -> ticketscoring.v1.ExportScoresRequest
+	3,  // 15: ticketscoring.v1.TicketScoring.GetOverallQualityScore:output_type -> ticketscoring.v1.OverallQualityScoreResponse
+	9,  // 16: ticketscoring.v1.TicketScoring.GetAggregatedCategoryScores:output_type -> ticketscoring.v1.AggregatedCategoryScoresResponse
+	6,  // 17: ticketscoring.v1.TicketScoring.GetScoresByTicket:output_type -> ticketscoring.v1.ScoresByTicketResponse
+	7,  // 18: ticketscoring.v1.TicketScoring.GetPeriodOverPeriodScoreChange:output_type -> ticketscoring.v1.PeriodOverPeriodScoreChangeResponse
+	11, // 19: ticketscoring.v1.TicketScoring.ExportScores:output_type -> ticketscoring.v1.ExportChunk
+	15, // [15:20] is the sub-list for method output_type
+	10, // [10:15] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_ticketscoring_proto_init() }
@@ -534,13 +781,14 @@ func file_api_v1_ticketscoring_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_ticketscoring_proto_rawDesc), len(file_api_v1_ticketscoring_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      2,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_v1_ticketscoring_proto_goTypes,
 		DependencyIndexes: file_api_v1_ticketscoring_proto_depIdxs,
+		EnumInfos:         file_api_v1_ticketscoring_proto_enumTypes,
 		MessageInfos:      file_api_v1_ticketscoring_proto_msgTypes,
 	}.Build()
 	File_api_v1_ticketscoring_proto = out.File
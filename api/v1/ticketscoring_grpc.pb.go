@@ -23,6 +23,7 @@ const (
 	TicketScoring_GetAggregatedCategoryScores_FullMethodName    = "/ticketscoring.v1.TicketScoring/GetAggregatedCategoryScores"
 	TicketScoring_GetScoresByTicket_FullMethodName              = "/ticketscoring.v1.TicketScoring/GetScoresByTicket"
 	TicketScoring_GetPeriodOverPeriodScoreChange_FullMethodName = "/ticketscoring.v1.TicketScoring/GetPeriodOverPeriodScoreChange"
+	TicketScoring_ExportScores_FullMethodName                   = "/ticketscoring.v1.TicketScoring/ExportScores"
 )
 
 // TicketScoringClient is the client API for TicketScoring service.
@@ -33,6 +34,9 @@ type TicketScoringClient interface {
 	GetAggregatedCategoryScores(ctx context.Context, in *TimePeriodRequest, opts ...grpc.CallOption) (*AggregatedCategoryScoresResponse, error)
 	GetScoresByTicket(ctx context.Context, in *TimePeriodRequest, opts ...grpc.CallOption) (*ScoresByTicketResponse, error)
 	GetPeriodOverPeriodScoreChange(ctx context.Context, in *TimePeriodRequest, opts ...grpc.CallOption) (*PeriodOverPeriodScoreChangeResponse, error)
+	// ExportScores streams a report for the window as CSV or Parquet. Over HTTP it is
+	// served as a file download at GET /v1/export.
+	ExportScores(ctx context.Context, in *ExportScoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
 }
 
 type ticketScoringClient struct {
@@ -83,6 +87,25 @@ func (c *ticketScoringClient) GetPeriodOverPeriodScoreChange(ctx context.Context
 	return out, nil
 }
 
+func (c *ticketScoringClient) ExportScores(ctx context.Context, in *ExportScoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TicketScoring_ServiceDesc.Streams[0], TicketScoring_ExportScores_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportScoresRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TicketScoring_ExportScoresClient = grpc.ServerStreamingClient[ExportChunk]
+
 // TicketScoringServer is the server API for TicketScoring service.
 // All implementations must embed UnimplementedTicketScoringServer
 // for forward compatibility.
@@ -91,6 +114,9 @@ type TicketScoringServer interface {
 	GetAggregatedCategoryScores(context.Context, *TimePeriodRequest) (*AggregatedCategoryScoresResponse, error)
 	GetScoresByTicket(context.Context, *TimePeriodRequest) (*ScoresByTicketResponse, error)
 	GetPeriodOverPeriodScoreChange(context.Context, *TimePeriodRequest) (*PeriodOverPeriodScoreChangeResponse, error)
+	// ExportScores streams a report for the window as CSV or Parquet. Over HTTP it is
+	// served as a file download at GET /v1/export.
+	ExportScores(*ExportScoresRequest, grpc.ServerStreamingServer[ExportChunk]) error
 	mustEmbedUnimplementedTicketScoringServer()
 }
 
@@ -113,6 +139,9 @@ func (UnimplementedTicketScoringServer) GetScoresByTicket(context.Context, *Time
 func (UnimplementedTicketScoringServer) GetPeriodOverPeriodScoreChange(context.Context, *TimePeriodRequest) (*PeriodOverPeriodScoreChangeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPeriodOverPeriodScoreChange not implemented")
 }
+func (UnimplementedTicketScoringServer) ExportScores(*ExportScoresRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportScores not implemented")
+}
 func (UnimplementedTicketScoringServer) mustEmbedUnimplementedTicketScoringServer() {}
 func (UnimplementedTicketScoringServer) testEmbeddedByValue()                       {}
 
@@ -206,6 +235,17 @@ func _TicketScoring_GetPeriodOverPeriodScoreChange_Handler(srv interface{}, ctx
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TicketScoring_ExportScores_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportScoresRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TicketScoringServer).ExportScores(m, &grpc.GenericServerStream[ExportScoresRequest, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TicketScoring_ExportScoresServer = grpc.ServerStreamingServer[ExportChunk]
+
 // TicketScoring_ServiceDesc is the grpc.ServiceDesc for TicketScoring service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -230,6 +270,12 @@ var TicketScoring_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _TicketScoring_GetPeriodOverPeriodScoreChange_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportScores",
+			Handler:       _TicketScoring_ExportScores_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "api/v1/ticketscoring.proto",
 }
@@ -0,0 +1,278 @@
+// Command qactl calls the TicketScoring gRPC service from the command line.
+//
+//	qactl overall    --from 2025-01-01 --to today
+//	qactl categories --from start-of-month --to today --category Tone -o csv
+//	qactl tickets    --from -7d --to today -o json
+//	qactl change     --from 2025-02-01 --to 2025-02-28
+//	qactl export     --from 2025-01-01 --to today --report tickets --format parquet --out tickets.parquet
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/internal/report"
+	"github.com/godilite/qa-server/internal/timeexpr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const defaultAddr = "localhost:50051"
+
+const usage = `Usage: qactl <command> [flags]
+
+Commands:
+  overall     overall quality score for the period
+  categories  category scores per day or week
+  tickets     category scores per ticket
+  change      score change against the previous period of the same length
+  export      stream a CSV or Parquet export to a file or stdout
+
+Dates (--from, --to) accept YYYY-MM-DD, RFC 3339, now, today, yesterday,
+last-week, last-month, last-quarter, last-year, start-of-week, start-of-month,
+start-of-quarter, start-of-year, and offsets such as -7d, -2w, -3m, 1y-ago.
+
+Run 'qactl <command> -h' for the flags of a command.
+`
+
+// queries maps each read command to its RPC.
+var queries = map[string]func(context.Context, pb.TicketScoringClient, *pb.TimePeriodRequest) (proto.Message, error){
+	"overall": func(ctx context.Context, c pb.TicketScoringClient, req *pb.TimePeriodRequest) (proto.Message, error) {
+		return c.GetOverallQualityScore(ctx, req)
+	},
+	"categories": func(ctx context.Context, c pb.TicketScoringClient, req *pb.TimePeriodRequest) (proto.Message, error) {
+		return c.GetAggregatedCategoryScores(ctx, req)
+	},
+	"tickets": func(ctx context.Context, c pb.TicketScoringClient, req *pb.TimePeriodRequest) (proto.Message, error) {
+		return c.GetScoresByTicket(ctx, req)
+	},
+	"change": func(ctx context.Context, c pb.TicketScoringClient, req *pb.TimePeriodRequest) (proto.Message, error) {
+		return c.GetPeriodOverPeriodScoreChange(ctx, req)
+	},
+}
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout, os.Stderr, time.Now()); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "qactl: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// options are the flags shared by every command.
+type options struct {
+	addr       string
+	from       string
+	to         string
+	output     string
+	categories stringList
+	timeout    time.Duration
+	maxMsgSize int
+
+	// export only
+	reportType string
+	format     string
+	out        string
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, now time.Time) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		if len(args) == 0 {
+			return errors.New("no command given")
+		}
+		return flag.ErrHelp
+	}
+
+	cmd := args[0]
+	query, isQuery := queries[cmd]
+	if !isQuery && cmd != "export" {
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+
+	opts, err := parseFlags(cmd, args[1:], stderr)
+	if err != nil {
+		return err
+	}
+
+	start, err := timeexpr.Parse(opts.from, now)
+	if err != nil {
+		return fmt.Errorf("--from: %w", err)
+	}
+	end, err := timeexpr.Parse(opts.to, now)
+	if err != nil {
+		return fmt.Errorf("--to: %w", err)
+	}
+
+	conn, err := grpc.NewClient(opts.addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(opts.maxMsgSize), grpc.UseCompressor(gzip.Name)),
+	)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", opts.addr, err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	client := pb.NewTicketScoringClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	if cmd == "export" {
+		return runExport(ctx, client, opts, start, end, stdout)
+	}
+
+	format, err := report.ParseFormat(opts.output)
+	if err != nil {
+		return err
+	}
+	if len(opts.categories) > 0 && (cmd == "overall" || cmd == "change") {
+		return fmt.Errorf("--category is not supported by %s", cmd)
+	}
+
+	resp, err := query(ctx, client, &pb.TimePeriodRequest{
+		StartDate: timestamppb.New(start),
+		EndDate:   timestamppb.New(end),
+	})
+	if err != nil {
+		return rpcError(err)
+	}
+
+	return report.Write(stdout, format, report.FilterCategories(resp, opts.categories))
+}
+
+func parseFlags(cmd string, args []string, stderr io.Writer) (*options, error) {
+	opts := &options{}
+
+	fs := flag.NewFlagSet("qactl "+cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	addr := os.Getenv("QACTL_ADDR")
+	if addr == "" {
+		addr = defaultAddr
+	}
+	fs.StringVar(&opts.addr, "addr", addr, "gRPC server address (env QACTL_ADDR)")
+	fs.StringVar(&opts.from, "from", "-30d", "start of the period")
+	fs.StringVar(&opts.to, "to", "today", "end of the period")
+	fs.DurationVar(&opts.timeout, "timeout", 30*time.Second, "request timeout")
+	fs.IntVar(&opts.maxMsgSize, "max-msg-size", 64<<20, "largest response accepted, in bytes")
+
+	if cmd == "export" {
+		fs.StringVar(&opts.reportType, "report", "categories", "report to export: categories or tickets")
+		fs.StringVar(&opts.format, "format", "csv", "file format: csv or parquet")
+		fs.StringVar(&opts.out, "out", "-", "output file, - for stdout")
+	} else {
+		fs.StringVar(&opts.output, "o", string(report.FormatTable), "output format: table, json or csv")
+		fs.StringVar(&opts.output, "output", string(report.FormatTable), "output format: table, json or csv")
+		if cmd == "categories" || cmd == "tickets" {
+			fs.Var(&opts.categories, "category", "only show this category (repeatable or comma-separated)")
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return opts, nil
+}
+
+func runExport(ctx context.Context, client pb.TicketScoringClient, opts *options, start, end time.Time, stdout io.Writer) error {
+	req := &pb.ExportScoresRequest{
+		StartDate: timestamppb.New(start),
+		EndDate:   timestamppb.New(end),
+	}
+
+	switch opts.reportType {
+	case "categories":
+		req.ReportType = pb.ReportType_REPORT_TYPE_AGGREGATED_CATEGORY_SCORES
+	case "tickets":
+		req.ReportType = pb.ReportType_REPORT_TYPE_SCORES_BY_TICKET
+	default:
+		return fmt.Errorf("unknown report %q: must be categories or tickets", opts.reportType)
+	}
+
+	switch opts.format {
+	case "csv":
+		req.Format = pb.ExportFormat_EXPORT_FORMAT_CSV
+	case "parquet":
+		req.Format = pb.ExportFormat_EXPORT_FORMAT_PARQUET
+	default:
+		return fmt.Errorf("unknown format %q: must be csv or parquet", opts.format)
+	}
+
+	stream, err := client.ExportScores(ctx, req)
+	if err != nil {
+		return rpcError(err)
+	}
+
+	// Open the output file only once the first chunk arrives, so a rejected
+	// request does not leave an empty file behind.
+	var w io.Writer
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return rpcError(err)
+		}
+
+		if w == nil {
+			w = stdout
+			if opts.out != "-" {
+				f, err := os.Create(opts.out)
+				if err != nil {
+					return fmt.Errorf("create %s: %w", opts.out, err)
+				}
+				defer func() {
+					_ = f.Close()
+				}()
+				w = f
+			}
+		}
+
+		if _, err := w.Write(chunk.GetData()); err != nil {
+			return fmt.Errorf("write export: %w", err)
+		}
+	}
+}
+
+// rpcError turns a gRPC status into a short "Code: message" error.
+func rpcError(err error) error {
+	if st, ok := status.FromError(err); ok {
+		return fmt.Errorf("%s: %s", st.Code(), st.Message())
+	}
+	return err
+}
+
+// stringList is a repeatable flag that also splits comma-separated values.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(v string) error {
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*s = append(*s, part)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeScoringServer struct {
+	pb.UnimplementedTicketScoringServer
+	lastRequest *pb.TimePeriodRequest
+}
+
+func (f *fakeScoringServer) GetOverallQualityScore(_ context.Context, req *pb.TimePeriodRequest) (*pb.OverallQualityScoreResponse, error) {
+	f.lastRequest = req
+	return &pb.OverallQualityScoreResponse{Score: 87.5}, nil
+}
+
+func (f *fakeScoringServer) GetAggregatedCategoryScores(_ context.Context, req *pb.TimePeriodRequest) (*pb.AggregatedCategoryScoresResponse, error) {
+	f.lastRequest = req
+	return &pb.AggregatedCategoryScoresResponse{
+		CategoryScores: []*pb.CategoryScore{
+			{CategoryName: "Tone", TotalRatings: 1, OverallCategoryScore: 80, PeriodScores: []*pb.PeriodScore{{Period: "2025-05-01", Score: 80}}},
+			{CategoryName: "Grammar", TotalRatings: 1, OverallCategoryScore: 60, PeriodScores: []*pb.PeriodScore{{Period: "2025-05-01", Score: 60}}},
+		},
+	}, nil
+}
+
+// GetScoresByTicket answers with about 4.4 MiB, more than gRPC's default 4 MiB receive limit.
+func (f *fakeScoringServer) GetScoresByTicket(context.Context, *pb.TimePeriodRequest) (*pb.ScoresByTicketResponse, error) {
+	resp := &pb.ScoresByTicketResponse{TicketScores: make([]*pb.TicketScore, 200_000)}
+	for i := range resp.TicketScores {
+		resp.TicketScores[i] = &pb.TicketScore{TicketId: int64(i + 1), CategoryScores: map[string]float64{"Tone": 80}}
+	}
+	return resp, nil
+}
+
+func (f *fakeScoringServer) GetPeriodOverPeriodScoreChange(context.Context, *pb.TimePeriodRequest) (*pb.PeriodOverPeriodScoreChangeResponse, error) {
+	return nil, status.Error(codes.NotFound, "no ratings found")
+}
+
+func (f *fakeScoringServer) ExportScores(req *pb.ExportScoresRequest, stream grpc.ServerStreamingServer[pb.ExportChunk]) error {
+	if req.GetReportType() != pb.ReportType_REPORT_TYPE_SCORES_BY_TICKET {
+		return status.Error(codes.InvalidArgument, "report_type is required")
+	}
+	for _, part := range []string{"ticket_id,category,score\n", "1,Tone,80\n"} {
+		if err := stream.Send(&pb.ExportChunk{Data: []byte(part)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startFakeServer(t *testing.T) (*fakeScoringServer, string) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	fake := &fakeScoringServer{}
+	srv := grpc.NewServer()
+	pb.RegisterTicketScoringServer(srv, fake)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	return fake, lis.Addr().String()
+}
+
+var now = time.Date(2025, 5, 14, 12, 0, 0, 0, time.UTC)
+
+func TestRunOverall(t *testing.T) {
+	fake, addr := startFakeServer(t)
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), []string{"overall", "--addr", addr, "--from", "2025-01-01", "--to", "last-week", "-o", "csv"}, &stdout, &stderr, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, "score\n87.50\n", stdout.String())
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), fake.lastRequest.GetStartDate().AsTime())
+	assert.Equal(t, time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC), fake.lastRequest.GetEndDate().AsTime())
+}
+
+func TestRunCategoriesFiltered(t *testing.T) {
+	_, addr := startFakeServer(t)
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), []string{"categories", "--addr", addr, "--category", "Tone", "--output", "csv"}, &stdout, &stderr, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, "category,total_ratings,overall_category_score,period,period_score\nTone,1,80.00,2025-05-01,80.00\n", stdout.String())
+}
+
+func TestRunReportsStatusErrors(t *testing.T) {
+	_, addr := startFakeServer(t)
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), []string{"change", "--addr", addr}, &stdout, &stderr, now)
+
+	require.Error(t, err)
+	assert.Equal(t, "NotFound: no ratings found", err.Error())
+}
+
+func TestRunLargeResponse(t *testing.T) {
+	_, addr := startFakeServer(t)
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), []string{"tickets", "--addr", addr, "-o", "csv"}, &stdout, &stderr, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 200_001, bytes.Count(stdout.Bytes(), []byte("\n")))
+
+	err = run(context.Background(), []string{"tickets", "--addr", addr, "--max-msg-size", "1024"}, &stdout, &stderr, now)
+	assert.ErrorContains(t, err, "ResourceExhausted")
+}
+
+func TestRunExport(t *testing.T) {
+	_, addr := startFakeServer(t)
+	out := filepath.Join(t.TempDir(), "tickets.csv")
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), []string{"export", "--addr", addr, "--report", "tickets", "--out", out}, &stdout, &stderr, now)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "ticket_id,category,score\n1,Tone,80\n", string(data))
+}
+
+func TestRunExportErrorLeavesNoFile(t *testing.T) {
+	_, addr := startFakeServer(t)
+	out := filepath.Join(t.TempDir(), "categories.csv")
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), []string{"export", "--addr", addr, "--report", "categories", "--out", out}, &stdout, &stderr, now)
+
+	require.Error(t, err)
+	assert.NoFileExists(t, out)
+}
+
+func TestRunInvalidInput(t *testing.T) {
+	tests := map[string][]string{
+		"no command":          {},
+		"unknown command":     {"frobnicate"},
+		"bad date":            {"overall", "--from", "someday"},
+		"bad output":          {"overall", "-o", "xml"},
+		"category on overall": {"overall", "--category", "Tone"},
+		"stray argument":      {"tickets", "extra"},
+		"bad report":          {"export", "--report", "weekly"},
+	}
+
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Error(t, run(context.Background(), args, &stdout, &stderr, now))
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Command qareport produces the TicketScoring reports straight from the SQLite
+// database, for when the server or Redis is unavailable. Reports come from the same
+// scoring service and repository options the server uses, without a cache, and are
+// rendered from the RPC response messages, so the output matches what the RPCs return.
+//
+//	qareport overall    --db ./data/database.db --from 2025-01-01 --to today
+//	qareport categories --from start-of-month --category Tone -o csv
+//	qareport tickets    --from -7d -o json
+//	qareport change     --from 2025-02-01 --to 2025-02-28
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	handler "github.com/godilite/qa-server/internal/grpc"
+	"github.com/godilite/qa-server/internal/report"
+	"github.com/godilite/qa-server/internal/repository"
+	"github.com/godilite/qa-server/internal/service"
+	"github.com/godilite/qa-server/internal/timeexpr"
+	dbbuilder "github.com/godilite/qa-server/pkg/database"
+	_ "github.com/mattn/go-sqlite3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultDBPath = "./data/database.db"
+
+const usage = `Usage: qareport <command> [flags]
+
+Commands:
+  overall     overall quality score for the period
+  categories  category scores per day or week
+  tickets     category scores per ticket
+  change      score change against the previous period of the same length
+
+Dates (--from, --to) accept the same expressions as qactl: YYYY-MM-DD, RFC 3339,
+today, yesterday, last-week, start-of-month, -7d, 2w-ago, ...
+
+Run 'qareport <command> -h' for the flags of a command.
+`
+
+// reports maps each command to the service call behind the matching RPC and builds that
+// RPC's response.
+var reports = map[string]func(*service.ScoringService, context.Context, time.Time, time.Time) (proto.Message, error){
+	"overall": func(svc *service.ScoringService, ctx context.Context, start, end time.Time) (proto.Message, error) {
+		score, err := svc.GetOverallScore(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.OverallQualityScoreResponse{Score: score}, nil
+	},
+	"categories": func(svc *service.ScoringService, ctx context.Context, start, end time.Time) (proto.Message, error) {
+		scores, err := svc.GetAggregatedCategoryScores(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.AggregatedCategoryScoresResponse{CategoryScores: handler.ToProtoCategoryScores(scores)}, nil
+	},
+	"tickets": func(svc *service.ScoringService, ctx context.Context, start, end time.Time) (proto.Message, error) {
+		scores, err := svc.GetScoresByTicket(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.ScoresByTicketResponse{TicketScores: handler.ToProtoTicketScores(scores)}, nil
+	},
+	"change": func(svc *service.ScoringService, ctx context.Context, start, end time.Time) (proto.Message, error) {
+		change, err := svc.GetPeriodOverPeriodScoreChange(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.PeriodOverPeriodScoreChangeResponse{
+			CurrentPeriodScore:  change.CurrentPeriodScore,
+			PreviousPeriodScore: change.PreviousPeriodScore,
+			ChangePercentage:    change.ChangePercentage,
+		}, nil
+	},
+}
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout, os.Stderr, time.Now()); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "qareport: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+type options struct {
+	dbPath     string
+	from       string
+	to         string
+	output     string
+	rollups    bool
+	categories stringList
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, now time.Time) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		if len(args) == 0 {
+			return errors.New("no command given")
+		}
+		return flag.ErrHelp
+	}
+
+	cmd := args[0]
+	generate, ok := reports[cmd]
+	if !ok {
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+
+	opts, err := parseFlags(cmd, args[1:], stderr)
+	if err != nil {
+		return err
+	}
+
+	format, err := report.ParseFormat(opts.output)
+	if err != nil {
+		return err
+	}
+	start, err := timeexpr.Parse(opts.from, now)
+	if err != nil {
+		return fmt.Errorf("--from: %w", err)
+	}
+	end, err := timeexpr.Parse(opts.to, now)
+	if err != nil {
+		return fmt.Errorf("--to: %w", err)
+	}
+	if end.Before(start) {
+		return errors.New("--to must not be before --from")
+	}
+
+	// Opening a missing path would create an empty database rather than fail.
+	if _, err := os.Stat(opts.dbPath); err != nil {
+		return fmt.Errorf("database %s: %w", opts.dbPath, err)
+	}
+	db, err := dbbuilder.New(
+		dbbuilder.WithDriver("sqlite3"),
+		dbbuilder.WithDataSource(fmt.Sprintf("file:%s?mode=ro", opts.dbPath)),
+		dbbuilder.WithRetry(1, 0),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	// The server's repository options; the rollup tables are created by the server, as
+	// the database is opened read-only here.
+	var repoOpts []repository.Option
+	if opts.rollups {
+		repoOpts = append(repoOpts, repository.WithRollups())
+	}
+	scoringService := service.NewScoringService(repository.NewRatingScoreRepository(db, repoOpts...), zap.NewNop())
+
+	resp, err := generate(scoringService, ctx, start, end)
+	if err != nil {
+		return fmt.Errorf("%s report: %w", cmd, err)
+	}
+
+	return report.Write(stdout, format, report.FilterCategories(resp, opts.categories))
+}
+
+func parseFlags(cmd string, args []string, stderr io.Writer) (*options, error) {
+	opts := &options{}
+
+	fs := flag.NewFlagSet("qareport "+cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		dbPath = defaultDBPath
+	}
+	fs.StringVar(&opts.dbPath, "db", dbPath, "SQLite database file, opened read-only (env DB_PATH)")
+	rollups, _ := strconv.ParseBool(os.Getenv("ROLLUPS_ENABLED"))
+	fs.BoolVar(&opts.rollups, "rollups", rollups, "read the daily rollups maintained by the server (env ROLLUPS_ENABLED)")
+	fs.StringVar(&opts.from, "from", "-30d", "start of the period")
+	fs.StringVar(&opts.to, "to", "today", "end of the period")
+	fs.StringVar(&opts.output, "o", "text", "output format: text, json or csv")
+	fs.StringVar(&opts.output, "output", "text", "output format: text, json or csv")
+	if cmd == "categories" || cmd == "tickets" {
+		fs.Var(&opts.categories, "category", "only show this category (repeatable or comma-separated)")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return opts, nil
+}
+
+// stringList is a repeatable flag that also splits comma-separated values.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(v string) error {
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*s = append(*s, part)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/godilite/qa-server/internal/repository"
+	"github.com/godilite/qa-server/internal/service"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+func setupTestDB(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "database.db")
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+	CREATE TABLE rating_categories (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		weight REAL NOT NULL
+	);
+	CREATE TABLE ratings (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		ticket_id INTEGER NOT NULL,
+		rating INTEGER NOT NULL,
+		rating_category_id INTEGER NOT NULL,
+		created_at TEXT NOT NULL,
+		FOREIGN KEY (rating_category_id) REFERENCES rating_categories(id)
+	);
+	INSERT INTO rating_categories (name, weight) VALUES ('Spelling', 1.0), ('Grammar', 0.5);
+	INSERT INTO ratings (ticket_id, rating, rating_category_id, created_at) VALUES
+		(1, 5, 1, '2025-01-02T10:00:00Z'),
+		(1, 3, 2, '2025-01-02T10:00:00Z'),
+		(2, 4, 1, '2025-01-03T10:00:00Z');
+	`)
+	require.NoError(t, err)
+
+	return path
+}
+
+func TestRunReports(t *testing.T) {
+	dbPath := setupTestDB(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "tickets csv",
+			args:     []string{"tickets", "--from", "2025-01-01", "--to", "today", "-o", "csv"},
+			expected: "ticket_id,category,score\n1,Grammar,60.00\n1,Spelling,100.00\n2,Spelling,80.00\n",
+		},
+		{
+			name:     "categories filtered",
+			args:     []string{"categories", "--from", "2025-01-01", "--to", "today", "--category", "grammar", "-o", "csv"},
+			expected: "category,total_ratings,overall_category_score,period,period_score\nGrammar,1,60.00,2025-01-02,60.00\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			err := run(context.Background(), append(tt.args, "--db", dbPath), &stdout, &stderr, now)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stdout.String())
+		})
+	}
+}
+
+func TestRunOverallJSON(t *testing.T) {
+	dbPath := setupTestDB(t)
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), []string{"overall", "--db", dbPath, "--from", "-9d", "-o", "json"}, &stdout, &stderr, now)
+	require.NoError(t, err)
+
+	var resp map[string]float64
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &resp))
+	assert.Contains(t, resp, "score")
+	assert.Greater(t, resp["score"], 0.0)
+}
+
+func TestRunNoRatings(t *testing.T) {
+	dbPath := setupTestDB(t)
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), []string{"overall", "--db", dbPath, "--from", "2024-01-01", "--to", "2024-01-31"}, &stdout, &stderr, now)
+
+	assert.ErrorIs(t, err, service.ErrNoRatings)
+}
+
+func TestRunRollups(t *testing.T) {
+	dbPath := setupTestDB(t)
+	args := []string{"overall", "--db", dbPath, "--from", "2025-01-01", "--to", "today", "-o", "json"}
+
+	var raw, stderr bytes.Buffer
+	require.NoError(t, run(context.Background(), args, &raw, &stderr, now))
+
+	var missing bytes.Buffer
+	err := run(context.Background(), append(args, "--rollups"), &missing, &stderr, now)
+	require.Error(t, err, "rollups were not read")
+	assert.Contains(t, err.Error(), "no such table")
+
+	db, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err)
+	rollups := repository.NewRollupBuilder(db)
+	require.NoError(t, rollups.EnsureSchema(context.Background()))
+	_, err = rollups.Refresh(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	var rolledUp bytes.Buffer
+	require.NoError(t, run(context.Background(), append(args, "--rollups"), &rolledUp, &stderr, now))
+	assert.JSONEq(t, raw.String(), rolledUp.String())
+}
+
+func TestRunMissingDatabase(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.db")
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), []string{"overall", "--db", missing}, &stdout, &stderr, now)
+
+	require.Error(t, err)
+	assert.NoFileExists(t, missing)
+}
+
+func TestRunInvalidInput(t *testing.T) {
+	tests := map[string][]string{
+		"no command":          {},
+		"unknown command":     {"export"},
+		"bad date":            {"overall", "--to", "someday"},
+		"bad output":          {"overall", "-o", "xml"},
+		"category on overall": {"overall", "--category", "Tone"},
+		"reversed window":     {"overall", "--from", "today", "--to", "-7d"},
+	}
+
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Error(t, run(context.Background(), args, &stdout, &stderr, now))
+		})
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
+	"os"
 
 	"github.com/godilite/qa-server/internal/app"
 	"github.com/godilite/qa-server/internal/config"
@@ -14,9 +16,15 @@ import (
 func main() {
 	_ = godotenv.Load(".env")
 
-	cfg := config.LoadFromEnv()
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML config file, re-read on SIGHUP")
+	flag.Parse()
 
-	logger, err := config.NewLogger(cfg)
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	logger, logLevel, err := config.NewLogger(cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
@@ -25,7 +33,10 @@ func main() {
 	}()
 
 	ctx := context.Background()
-	application, err := app.NewApp(ctx, cfg, logger)
+	application, err := app.NewApp(ctx, cfg, logger,
+		app.WithConfigFile(*configFile),
+		app.WithLogLevel(logLevel),
+	)
 	if err != nil {
 		logger.Fatal("Failed to initialize application", zap.Error(err))
 	}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+	"io"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/internal/config"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// accessLog returns the gRPC server option enabling the access log, sampled by rates, or
+// nil when it is disabled. Entries go to the application log, or as JSON lines to
+// ACCESS_LOG_FILE, rotated by size, when that is set; the returned closer then closes the
+// file.
+func accessLog(cfg *config.Config, logger *zap.Logger, rates *grpcsrv.SampleRates) (grpcsrv.Option, io.Closer) {
+	if !cfg.AccessLogEnabled {
+		return nil, nil
+	}
+
+	opts := []grpcsrv.AccessLogOption{
+		grpcsrv.WithSampleRates(rates),
+		grpcsrv.WithSlowThreshold(cfg.AccessLogSlowAfter),
+	}
+	if cfg.AccessLogFile == "" {
+		return grpcsrv.WithAccessLog(logger.Named("access"), opts...), nil
+	}
+	file := &lumberjack.Logger{
+		Filename:   cfg.AccessLogFile,
+		MaxSize:    cfg.AccessLogMaxSizeMB,
+		MaxBackups: cfg.AccessLogMaxBackups,
+		MaxAge:     cfg.AccessLogMaxAgeDays,
+	}
+	encoder := zap.NewProductionEncoderConfig()
+	encoder.EncodeTime = zapcore.ISO8601TimeEncoder
+	fileLogger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(encoder), zapcore.AddSync(file), zapcore.InfoLevel))
+	logger.Info("Access log writing to file", zap.String("file", cfg.AccessLogFile))
+	return grpcsrv.WithAccessLog(fileLogger, opts...), file
+}
+
+// accessLogMethodRates returns the per-RPC access log sample rates of cfg, rejecting
+// unknown RPCs.
+func accessLogMethodRates(cfg *config.Config) (map[string]float64, error) {
+	rates, err := cfg.AccessLogSampleRates()
+	if err != nil {
+		return nil, err
+	}
+	known := methodNames(&pb.TicketScoring_ServiceDesc, &pb.Admin_ServiceDesc)
+	for method := range rates {
+		if !known[method] {
+			return nil, fmt.Errorf("ACCESS_LOG_METHOD_SAMPLE_RATES: unknown RPC %q", method)
+		}
+	}
+	return rates, nil
+}
+
+// methodNames returns the unqualified names of the RPCs of descs.
+func methodNames(descs ...*grpc.ServiceDesc) map[string]bool {
+	known := make(map[string]bool)
+	for _, desc := range descs {
+		for _, m := range desc.Methods {
+			known[m.MethodName] = true
+		}
+		for _, s := range desc.Streams {
+			known[s.StreamName] = true
+		}
+	}
+	return known
+}
@@ -3,95 +3,452 @@ package app
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	pb "github.com/godilite/qa-server/api/v1"
 	"github.com/godilite/qa-server/internal/config"
+	"github.com/godilite/qa-server/internal/gateway"
 	handler "github.com/godilite/qa-server/internal/grpc"
 	"github.com/godilite/qa-server/internal/repository"
 	"github.com/godilite/qa-server/internal/service"
-	"github.com/godilite/qa-server/pkg/cache"
 	dbbuilder "github.com/godilite/qa-server/pkg/database"
 	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+	"github.com/godilite/qa-server/pkg/health"
+	"github.com/godilite/qa-server/pkg/metrics"
+	"github.com/godilite/qa-server/pkg/tracing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
+// gatewayBufferSize is the buffer of the in-memory listener the HTTP gateway reaches the
+// gRPC server through.
+const gatewayBufferSize = 1 << 20
+
 type App struct {
-	logger     *zap.Logger
-	dbPool     *sql.DB
-	cache      *cache.Cache
-	grpcServer *grpcsrv.Server
+	logger *zap.Logger
+	// cfgMu guards cfg, which reload replaces while the Admin service may be reading it.
+	cfgMu         sync.RWMutex
+	cfg           *config.Config
+	configPath    string
+	logLevel      *zap.AtomicLevel
+	handlers      *handler.GRPCHandlers
+	dbPool        *sql.DB
+	cache         handler.Cacher
+	grpcServer    *grpcsrv.Server
+	limiter       *grpcsrv.ConcurrencyLimiter
+	sampleRates   *grpcsrv.SampleRates
+	httpGateway   *gateway.Server
+	gatewayConn   *grpc.ClientConn
+	metricsServer *metrics.Server
+	accessLog     io.Closer
+	health        *health.Checker
+	healthServer  *health.Server
+	tracing       *tracing.Provider
+
+	rollups        *repository.RollupBuilder
+	rollupInterval time.Duration
+	warmer         *warmer
+}
+
+// Option configures optional App behaviour.
+type Option func(*App)
+
+// WithConfigFile sets the config file re-read on SIGHUP.
+func WithConfigFile(path string) Option {
+	return func(a *App) {
+		a.configPath = path
+	}
+}
+
+// WithLogLevel sets the level that LOG_LEVEL changes on reload.
+func WithLogLevel(level zap.AtomicLevel) Option {
+	return func(a *App) {
+		a.logLevel = &level
+	}
 }
 
-func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App, error) {
+func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger, opts ...Option) (_ *App, err error) {
+	a := &App{logger: logger, cfg: cfg}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	// opened closes, in reverse order, what has been opened if NewApp fails part way.
+	var opened []func() error
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(opened) - 1; i >= 0; i-- {
+			_ = opened[i]()
+		}
+	}()
+	shutdown := func(s interface{ Shutdown(context.Context) error }) func() error {
+		return func() error { return s.Shutdown(context.Background()) }
+	}
+
+	tracingProvider, err := tracing.New(ctx,
+		tracing.WithExporter(cfg.TracingExporter),
+		tracing.WithOTLPEndpoint(cfg.TracingOTLPEndpoint, cfg.TracingOTLPInsecure),
+		tracing.WithFile(cfg.TracingFile),
+		tracing.WithSampleRatio(cfg.TracingSampleRatio),
+		tracing.WithLogger(logger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracing init failed: %w", err)
+	}
+	opened = append(opened, shutdown(tracingProvider))
+
 	dbPool, err := dbbuilder.New(
 		dbbuilder.WithDriver(cfg.DBDriver),
 		dbbuilder.WithDataSource(cfg.DBPath),
+		dbbuilder.WithMaxOpenConns(cfg.DBMaxOpenConns),
+		dbbuilder.WithMaxIdleConns(cfg.DBMaxIdleConns),
+		dbbuilder.WithConnMaxLifetime(cfg.DBConnMaxLifetime),
+		dbbuilder.WithConnMaxIdleTime(cfg.DBConnMaxIdleTime),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("database init failed: %w", err)
 	}
+	opened = append(opened, dbPool.Close)
 	logger.Info("Database pool initialized", zap.String("path", cfg.DBPath))
 
-	cacheClient, err := cache.New(ctx,
-		cache.WithAddress(cfg.RedisAddr),
-	)
+	// Collectors of this App's resources go on its own registry, so a second App in the same
+	// process does not clash with the first.
+	registry := prometheus.NewRegistry()
+	if cfg.MetricsEnabled {
+		if err := dbbuilder.RegisterMetrics(registry, dbPool, cfg.DBDriver); err != nil {
+			return nil, fmt.Errorf("database metrics registration failed: %w", err)
+		}
+	}
+
+	cacheClient, err := newCache(ctx, cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("cache init failed: %w", err)
 	}
-	logger.Info("Cache client initialized", zap.String("addr", cfg.RedisAddr))
+	opened = append(opened, cacheClient.Close)
 
-	scoringRepo := repository.NewRatingScoreRepository(dbPool)
+	var (
+		rollups  *repository.RollupBuilder
+		repoOpts []repository.Option
+	)
+	if cfg.RollupsEnabled {
+		rollups = repository.NewRollupBuilder(dbPool, repository.WithRollupLogger(logger))
+		if err := rollups.EnsureSchema(ctx); err != nil {
+			return nil, fmt.Errorf("rollup init failed: %w", err)
+		}
+		repoOpts = append(repoOpts, repository.WithRollups())
+		logger.Info("Daily rollups enabled", zap.Duration("refresh_interval", cfg.RollupRefreshInterval))
+	}
 
-	scoringService := service.NewScoringService(scoringRepo, logger)
+	scoringRepo := repository.NewRatingScoreRepository(dbPool, repoOpts...)
+
+	scoringService := service.NewScoringService(scoringRepo, logger,
+		service.WithDBBudget(service.DBBudget{
+			Base:   cfg.DBQueryTimeout,
+			PerDay: cfg.DBQueryTimeoutPerDay,
+			Max:    cfg.DBQueryTimeoutMax,
+		}),
+	)
+
+	refreshPolicy, err := handler.ParseRefreshPolicy(cfg.CacheRefreshPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cache config: %w", err)
+	}
+
+	handlerOpts, err := timeoutOptions(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout config: %w", err)
+	}
+	handlerOpts = append(handlerOpts,
+		handler.WithRefreshPolicy(refreshPolicy),
+		handler.WithRefreshWindow(cfg.CacheRefreshWindow),
+		handler.WithNegativeTTL(cfg.CacheNegativeTTL),
+		handler.WithStaleTTL(cfg.CacheStaleTTL),
+		handler.WithSaturation(dbSaturated(dbPool.Stats, dbSaturationInterval)),
+	)
+	limiter := concurrencyLimiter(cfg)
+	if limiter != nil {
+		handlerOpts = append(handlerOpts, handler.WithConcurrencyLimiter(limiter))
+	}
+	grpcHandlers := handler.NewGRPCHandlers(scoringService, cacheClient, logger, cfg.CacheTTL, handlerOpts...)
 
-	grpcHandlers := handler.NewGRPCHandlers(scoringService, cacheClient, logger, 10*time.Minute)
+	var warm *warmer
+	if cfg.CacheWarmEnabled {
+		windows, err := parseWarmWindows(cfg.CacheWarmWindows)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache config: %w", err)
+		}
+		warm = newWarmer(grpcHandlers, windows, cfg.CacheWarmInterval, logger)
+		logger.Info("Cache warmer enabled",
+			zap.Strings("windows", cfg.CacheWarmWindows),
+			zap.Duration("interval", cfg.CacheWarmInterval))
+	}
 
-	grpcServer, err := grpcsrv.New(
+	serverOpts := []grpcsrv.Option{
 		grpcsrv.WithPort(cfg.GRPCPort),
 		grpcsrv.WithLogger(logger),
 		grpcsrv.WithReflection(cfg.GRPCReflectionEnabled),
-	)
+		grpcsrv.WithMetrics(cfg.MetricsEnabled),
+		grpcsrv.WithTracing(cfg.TracingExporter != tracing.ExporterNone),
+		grpcsrv.WithMaxMessageSize(cfg.GRPCMaxRecvMsgBytes, cfg.GRPCMaxSendMsgBytes),
+		grpcsrv.WithCompression(cfg.GRPCGzipEnabled),
+		grpcsrv.WithMaxConcurrentStreams(uint32(cfg.GRPCMaxStreams)),
+		grpcsrv.WithKeepalive(cfg.GRPCKeepaliveTime, cfg.GRPCKeepaliveTimeout),
+		grpcsrv.WithKeepaliveEnforcement(cfg.GRPCKeepaliveMinTime, cfg.GRPCPingWithoutStream),
+		grpcsrv.WithMaxConnectionAge(cfg.GRPCMaxConnAge, cfg.GRPCMaxConnAgeGrace, cfg.GRPCMaxConnIdle),
+	}
+	if len(cfg.GRPCListenAddresses) > 0 {
+		serverOpts = append(serverOpts, grpcsrv.WithAddresses(cfg.GRPCListenAddresses...))
+	} else if cfg.HTTPEnabled {
+		// The gateway's in-memory listener would otherwise replace the default :port.
+		serverOpts = append(serverOpts, grpcsrv.WithAddresses(fmt.Sprintf(":%d", cfg.GRPCPort)))
+	}
+	if cfg.HTTPEnabled {
+		serverOpts = append(serverOpts, grpcsrv.WithBufconn(gatewayBufferSize))
+	}
+	if limiter != nil {
+		// Unary RPCs take a slot only to query the database on a cache miss; exports always
+		// query it, so they hold one for the whole stream.
+		serverOpts = append(serverOpts, grpcsrv.WithStreamInterceptors(limiter.StreamInterceptor()))
+	}
+	methodRates, err := accessLogMethodRates(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access log config: %w", err)
+	}
+	sampleRates := grpcsrv.NewSampleRates(cfg.AccessLogSampleRate, methodRates)
+	accessLogOpt, accessLogFile := accessLog(cfg, logger, sampleRates)
+	if accessLogFile != nil {
+		opened = append(opened, accessLogFile.Close)
+	}
+	if accessLogOpt != nil {
+		serverOpts = append(serverOpts, accessLogOpt)
+	}
+	if cfg.AdminEnabled {
+		serverOpts = append(serverOpts, grpcsrv.WithUnaryInterceptors(handler.AdminAuthInterceptor(cfg.AdminTokens)))
+	}
+	grpcServer, err := grpcsrv.New(serverOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC server: %w", err)
 	}
+	opened = append(opened, shutdown(grpcServer))
+
+	var (
+		httpGateway *gateway.Server
+		gatewayConn *grpc.ClientConn
+	)
+	if cfg.HTTPEnabled {
+		// The gateway calls the gRPC server over an in-memory connection rather than the
+		// handlers directly, so HTTP requests get the same interceptors as gRPC ones.
+		gatewayConn, err = grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(grpcServer.BufconnDialer()),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(cfg.GRPCMaxSendMsgBytes)),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect HTTP gateway: %w", err)
+		}
+		opened = append(opened, gatewayConn.Close)
+		httpGateway, err = gateway.New(ctx, gatewayConn,
+			gateway.WithPort(cfg.HTTPPort),
+			gateway.WithLogger(logger),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP gateway: %w", err)
+		}
+		opened = append(opened, shutdown(httpGateway))
+	}
+
+	var metricsServer *metrics.Server
+	if cfg.MetricsEnabled {
+		metricsServer, err = metrics.New(
+			metrics.WithPort(cfg.MetricsPort),
+			metrics.WithLogger(logger),
+			metrics.WithGatherer(prometheus.Gatherers{prometheus.DefaultGatherer, registry}),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metrics server: %w", err)
+		}
+		opened = append(opened, shutdown(metricsServer))
+	}
 
-	grpcServer.RegisterService(func(s *grpc.Server) {
+	grpcServer.RegisterServiceWithHealth(pb.TicketScoring_ServiceDesc.ServiceName, func(s *grpc.Server) {
 		pb.RegisterTicketScoringServer(s, grpcHandlers)
 	})
 
-	return &App{
-		logger:     logger,
-		dbPool:     dbPool,
-		cache:      cacheClient,
-		grpcServer: grpcServer,
-	}, nil
+	checker, err := newHealthChecker(cfg, dbPool, cacheClient, grpcServer, logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid health check config: %w", err)
+	}
+	var healthServer *health.Server
+	if cfg.HealthEnabled {
+		healthServer, err = health.NewServer(checker,
+			health.WithPort(cfg.HealthPort),
+			health.WithServerLogger(logger),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create health server: %w", err)
+		}
+	}
+
+	if cfg.AdminEnabled {
+		adminOpts := []handler.AdminOption{
+			handler.WithConfigSource(func() map[string]string { return a.config().Redacted() }),
+			handler.WithDBStats(dbPool.Stats),
+		}
+		if a.logLevel != nil {
+			adminOpts = append(adminOpts, handler.WithAdminLogLevel(*a.logLevel))
+		}
+		if rollups != nil {
+			adminOpts = append(adminOpts, handler.WithRollupRebuild(rollups.Rebuild))
+		}
+		adminHandlers := handler.NewAdminHandlers(cacheClient, logger, adminOpts...)
+		grpcServer.RegisterServiceWithHealth(pb.Admin_ServiceDesc.ServiceName, func(s *grpc.Server) {
+			pb.RegisterAdminServer(s, adminHandlers)
+		})
+		logger.Info("Admin service enabled")
+	}
+
+	a.handlers = grpcHandlers
+	a.dbPool = dbPool
+	a.cache = cacheClient
+	a.grpcServer = grpcServer
+	a.limiter = limiter
+	a.sampleRates = sampleRates
+	a.httpGateway = httpGateway
+	a.gatewayConn = gatewayConn
+	a.metricsServer = metricsServer
+	a.accessLog = accessLogFile
+	a.health = checker
+	a.healthServer = healthServer
+	a.tracing = tracingProvider
+	a.rollups = rollups
+	a.rollupInterval = cfg.RollupRefreshInterval
+	a.warmer = warm
+	return a, nil
+}
+
+// config returns the configuration currently in effect.
+func (a *App) config() *config.Config {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+	return a.cfg
 }
 
 // Run starts the application and blocks until a shutdown signal is received.
 func (a *App) Run() error {
 	a.logger.Info("application starting")
 
+	// Check dependencies before accepting traffic so the first probes see real results.
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	a.health.Refresh(healthCtx)
+	healthDone := make(chan struct{})
+	go func() {
+		defer close(healthDone)
+		a.health.Run(healthCtx)
+	}()
+
 	a.grpcServer.Start()
+	if a.healthServer != nil {
+		a.healthServer.Start()
+	}
+	if a.httpGateway != nil {
+		a.httpGateway.Start()
+	}
+	if a.metricsServer != nil {
+		a.metricsServer.Start()
+	}
+
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	var background sync.WaitGroup
+	if a.rollups != nil {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			a.rollups.Run(backgroundCtx, a.rollupInterval)
+		}()
+	}
+	if a.warmer != nil {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			a.warmer.Run(backgroundCtx)
+		}()
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for {
+		if sig := <-signals; sig != syscall.SIGHUP {
+			break
+		}
+		a.reload()
+	}
+
+	// Stop checking first so a passing check cannot report SERVING again while draining.
+	stopHealth()
+	<-healthDone
+
+	a.shutdown(signals, func() {
+		stopBackground()
+		background.Wait()
+	})
+	signal.Stop(signals)
+
+	_ = a.logger.Sync()
+	return nil
+}
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+// shutdown drains and stops the application: it reports NOT_SERVING, keeps serving for
+// SHUTDOWN_DRAIN_PERIOD while load balancers notice (cut short by another signal), stops
+// the servers, calls stopBackground, lets background cache refreshes and writes finish and
+// finally closes the cache, the database and tracing. Everything after the drain period
+// shares SHUTDOWN_TIMEOUT.
+func (a *App) shutdown(signals <-chan os.Signal, stopBackground func()) {
+	cfg := a.config()
+	a.logger.Info("application shutting down", zap.Duration("drain_period", cfg.ShutdownDrainPeriod))
 
-	a.logger.Info("application shutting down")
+	a.health.Drain()
+	a.waitDrain(signals, cfg.ShutdownDrainPeriod)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
+	// The gateway goes first: its requests are served by the gRPC server.
+	if a.httpGateway != nil {
+		if err := a.httpGateway.Shutdown(ctx); err != nil {
+			a.logger.Error("HTTP gateway shutdown error", zap.Error(err))
+		}
+	}
 	if err := a.grpcServer.Shutdown(ctx); err != nil {
 		a.logger.Error("gRPC server shutdown error", zap.Error(err))
 	}
+	if a.gatewayConn != nil {
+		if err := a.gatewayConn.Close(); err != nil {
+			a.logger.Error("HTTP gateway connection shutdown error", zap.Error(err))
+		}
+	}
+
+	if a.accessLog != nil {
+		if err := a.accessLog.Close(); err != nil {
+			a.logger.Error("access log shutdown error", zap.Error(err))
+		}
+	}
+
+	stopBackground()
+	if err := handler.WaitForBackground(ctx); err != nil {
+		a.logger.Warn("abandoning background cache work", zap.Error(err))
+	}
 
 	if err := a.cache.Close(); err != nil {
 		a.logger.Error("cache shutdown error", zap.Error(err))
@@ -99,16 +456,127 @@ func (a *App) Run() error {
 	if err := a.dbPool.Close(); err != nil {
 		a.logger.Error("database shutdown error", zap.Error(err))
 	}
-
-	select {
-	case <-ctx.Done():
-		if ctx.Err() == context.DeadlineExceeded {
-			a.logger.Warn("shutdown completed but deadline exceeded")
+	if a.healthServer != nil {
+		if err := a.healthServer.Shutdown(ctx); err != nil {
+			a.logger.Error("health server shutdown error", zap.Error(err))
+		}
+	}
+	if a.metricsServer != nil {
+		if err := a.metricsServer.Shutdown(ctx); err != nil {
+			a.logger.Error("metrics server shutdown error", zap.Error(err))
 		}
-	default:
+	}
+	if err := a.tracing.Shutdown(ctx); err != nil {
+		a.logger.Error("tracing shutdown error", zap.Error(err))
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		a.logger.Warn("shutdown completed but deadline exceeded")
+	} else {
 		a.logger.Info("graceful shutdown completed successfully")
 	}
+}
 
-	_ = a.logger.Sync()
+// waitDrain waits for period, or until a second SIGINT or SIGTERM asks to stop at once.
+func (a *App) waitDrain(signals <-chan os.Signal, period time.Duration) {
+	if period <= 0 {
+		return
+	}
+	drain := time.NewTimer(period)
+	defer drain.Stop()
+	for {
+		select {
+		case <-drain.C:
+			return
+		case sig := <-signals:
+			if sig != syscall.SIGHUP {
+				a.logger.Warn("drain period cut short", zap.Stringer("signal", sig))
+				return
+			}
+		}
+	}
+}
+
+// timeoutOptions returns the handler options for the configured RPC and cache timeouts,
+// rejecting per-RPC overrides that name no TicketScoring method.
+func timeoutOptions(cfg *config.Config) ([]handler.HandlerOption, error) {
+	methodTimeouts, err := cfg.MethodTimeouts()
+	if err != nil {
+		return nil, err
+	}
+
+	known := methodNames(&pb.TicketScoring_ServiceDesc)
+
+	opts := []handler.HandlerOption{
+		handler.WithTimeout(cfg.GRPCTimeout),
+		handler.WithCacheTimeouts(cfg.CacheFetchTimeout, cfg.CacheSetTimeout),
+	}
+	for method, timeout := range methodTimeouts {
+		if !known[method] {
+			return nil, fmt.Errorf("GRPC_METHOD_TIMEOUTS: unknown RPC %q", method)
+		}
+		opts = append(opts, handler.WithMethodTimeout(method, timeout))
+	}
+	return opts, nil
+}
+
+// reload re-reads the configuration and applies the settings that can change at runtime:
+// the log level, the cache TTLs and refresh policy, the concurrency limits and the access
+// log sample rates. Other changed settings are logged and take effect on the next
+// restart. An invalid configuration is rejected as a whole.
+func (a *App) reload() {
+	next, err := config.Load(a.configPath)
+	if err != nil {
+		a.logger.Error("config reload failed, keeping current settings", zap.Error(err))
+		return
+	}
+
+	updated, restart := a.config().Reload(next)
+	if len(restart) > 0 {
+		a.logger.Warn("changed settings need a restart to take effect", zap.Strings("settings", restart))
+	}
+	if err := a.applyReloadable(updated); err != nil {
+		a.logger.Error("config reload failed, keeping current settings", zap.Error(err))
+		return
+	}
+	a.cfgMu.Lock()
+	a.cfg = updated
+	a.cfgMu.Unlock()
+	a.logger.Info("configuration reloaded", zap.String("file", a.configPath))
+}
+
+// applyReloadable pushes the reloadable settings of cfg to the running components. The log
+// level is only set when LOG_LEVEL changed, so a level changed through the Admin service
+// survives unrelated reloads; removing LOG_LEVEL restores the environment's default.
+func (a *App) applyReloadable(cfg *config.Config) error {
+	refreshPolicy, err := handler.ParseRefreshPolicy(cfg.CacheRefreshPolicy)
+	if err != nil {
+		return err
+	}
+	methodRates, err := accessLogMethodRates(cfg)
+	if err != nil {
+		return err
+	}
+	level, err := cfg.Level()
+	if err != nil {
+		return err
+	}
+
+	if a.logLevel != nil && cfg.LogLevel != a.config().LogLevel {
+		a.logLevel.SetLevel(level)
+		a.logger.Info("log level changed", zap.Stringer("level", level), zap.Bool("default", cfg.LogLevel == ""))
+	}
+
+	a.handlers.SetCachePolicy(handler.CachePolicy{
+		TTL:         cfg.CacheTTL,
+		NegativeTTL: cfg.CacheNegativeTTL,
+		Refresh:     handler.RefreshAhead{Policy: refreshPolicy, Window: cfg.CacheRefreshWindow},
+	})
+	if a.limiter != nil {
+		a.limiter.SetOptions(limiterOptions(cfg)...)
+	}
+	if a.sampleRates != nil {
+		a.sampleRates.Set(cfg.AccessLogSampleRate, methodRates)
+	}
 	return nil
 }
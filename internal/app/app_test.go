@@ -0,0 +1,224 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/godilite/qa-server/internal/config"
+	handler "github.com/godilite/qa-server/internal/grpc"
+	"github.com/godilite/qa-server/internal/grpc/mocks"
+	"github.com/godilite/qa-server/pkg/cache"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+)
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("log_level: info\n"), 0o600))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	a := &App{
+		logger:      zap.NewNop(),
+		cfg:         cfg,
+		configPath:  path,
+		logLevel:    &level,
+		handlers:    handler.NewGRPCHandlers(&mocks.MockScoringService{}, cache.NewNoop(), zap.NewNop(), cfg.CacheTTL),
+		limiter:     concurrencyLimiter(cfg),
+		sampleRates: grpcsrv.NewSampleRates(cfg.AccessLogSampleRate, nil),
+	}
+	const method = "/ticketscoring.v1.TicketScoring/GetOverallQualityScore"
+	_, err = a.limiter.UnaryInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(context.Context, any) (any, error) { return nil, nil })
+	require.NoError(t, err)
+
+	t.Run("applies reloadable settings", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("log_level: warn\ncache_ttl: 1m\ngrpc_port: 6000\n"+
+			"concurrency_limit: 5\nconcurrency_limit_max: 10\naccess_log_sample_rate: 0.5\n"), 0o600))
+
+		a.reload()
+
+		assert.Equal(t, zapcore.WarnLevel, level.Level())
+		assert.Equal(t, time.Minute, a.cfg.CacheTTL)
+		assert.Equal(t, 50051, a.cfg.GRPCPort, "the port needs a restart")
+		assert.Equal(t, 10, a.limiter.Limits()[method], "the limit moves within the new maximum")
+		assert.Equal(t, 0.5, a.cfg.AccessLogSampleRate)
+	})
+
+	t.Run("keeps settings when the file is invalid", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("log_level: debug\ncache_ttl: soon\n"), 0o600))
+
+		a.reload()
+
+		assert.Equal(t, zapcore.WarnLevel, level.Level())
+		assert.Equal(t, time.Minute, a.cfg.CacheTTL)
+	})
+
+	t.Run("keeps a level set at runtime when LOG_LEVEL is unchanged", func(t *testing.T) {
+		level.SetLevel(zapcore.ErrorLevel)
+		require.NoError(t, os.WriteFile(path, []byte("log_level: warn\ncache_ttl: 2m\n"), 0o600))
+
+		a.reload()
+
+		assert.Equal(t, zapcore.ErrorLevel, level.Level())
+		assert.Equal(t, 2*time.Minute, a.cfg.CacheTTL)
+	})
+
+	t.Run("restores the default level when LOG_LEVEL is removed", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("cache_ttl: 2m\n"), 0o600))
+
+		a.reload()
+
+		want, err := a.cfg.Level()
+		require.NoError(t, err)
+		assert.Empty(t, a.cfg.LogLevel)
+		assert.Equal(t, want, level.Level())
+		assert.NotEqual(t, zapcore.ErrorLevel, level.Level())
+	})
+}
+
+// testAppConfig returns a config for a complete App on a temporary database, listening on
+// free ports and a gRPC Unix socket.
+func testAppConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg := config.Default()
+	cfg.DBPath = filepath.Join(t.TempDir(), "app.db")
+	cfg.CacheBackend = "memory"
+	cfg.RollupsEnabled = false
+	cfg.GRPCListenAddresses = []string{"unix:" + filepath.Join(t.TempDir(), "grpc.sock")}
+	cfg.HTTPPort = 0
+	cfg.MetricsPort = 0
+	cfg.HealthPort = 0
+	cfg.ShutdownDrainPeriod = 0
+	return cfg
+}
+
+func TestNewApp(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("apps in one process keep their metrics apart", func(t *testing.T) {
+		for range 2 {
+			a, err := NewApp(ctx, testAppConfig(t), zap.NewNop())
+			require.NoError(t, err)
+			a.shutdown(nil, func() {})
+		}
+	})
+
+	t.Run("closes what it opened when it fails", func(t *testing.T) {
+		busy, err := net.Listen("tcp", ":0")
+		require.NoError(t, err)
+		defer busy.Close()
+
+		cfg := testAppConfig(t)
+		cfg.HealthPort = busy.Addr().(*net.TCPAddr).Port
+		socket := strings.TrimPrefix(cfg.GRPCListenAddresses[0], "unix:")
+
+		_, err = NewApp(ctx, cfg, zap.NewNop())
+		require.ErrorContains(t, err, "failed to create health server")
+		assert.NoFileExists(t, socket, "the gRPC listener is closed")
+	})
+}
+
+func TestTimeoutOptions(t *testing.T) {
+	cfg := config.Default()
+	cfg.GRPCMethodTimeouts = []string{"ExportScores=2m", "GetOverallQualityScore=5s"}
+
+	opts, err := timeoutOptions(cfg)
+	require.NoError(t, err)
+	assert.Len(t, opts, 4)
+
+	cfg.GRPCMethodTimeouts = []string{"GetOverallScore=5s"}
+	_, err = timeoutOptions(cfg)
+	assert.ErrorContains(t, err, `unknown RPC "GetOverallScore"`)
+}
+
+type unhealthyTier struct{ cache.Backend }
+
+func (unhealthyTier) Status(context.Context) []cache.TierStatus {
+	return []cache.TierStatus{
+		{Tier: "lru", Healthy: true},
+		{Tier: "redis", Detail: "standalone: connection refused"},
+	}
+}
+
+func TestCacheCheck(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, cacheCheck(cache.NewNoop())(ctx))
+	assert.EqualError(t, cacheCheck(unhealthyTier{})(ctx), "redis: standalone: connection refused")
+}
+
+func TestWaitDrain(t *testing.T) {
+	a := &App{logger: zap.NewNop()}
+	signals := make(chan os.Signal, 2)
+
+	start := time.Now()
+	a.waitDrain(signals, 30*time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	signals <- syscall.SIGHUP
+	signals <- syscall.SIGTERM
+	start = time.Now()
+	a.waitDrain(signals, time.Minute)
+	assert.Less(t, time.Since(start), time.Second, "a second SIGTERM ends the drain, SIGHUP does not")
+}
+
+func TestAccessLog(t *testing.T) {
+	cfg := config.Default()
+	cfg.AccessLogMethodRates = []string{"GetOverallQualityScore=0.1", "InvalidateCache=1"}
+
+	rates, err := accessLogMethodRates(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"GetOverallQualityScore": 0.1, "InvalidateCache": 1}, rates)
+	sampleRates := grpcsrv.NewSampleRates(cfg.AccessLogSampleRate, rates)
+
+	opt, closer := accessLog(cfg, zap.NewNop(), sampleRates)
+	assert.NotNil(t, opt)
+	assert.Nil(t, closer, "entries go to the application log without ACCESS_LOG_FILE")
+
+	cfg.AccessLogMethodRates = []string{"GetOverallScore=0.1"}
+	_, err = accessLogMethodRates(cfg)
+	assert.ErrorContains(t, err, `unknown RPC "GetOverallScore"`)
+
+	cfg.AccessLogFile = filepath.Join(t.TempDir(), "access.log")
+	_, closer = accessLog(cfg, zap.NewNop(), sampleRates)
+	require.NotNil(t, closer)
+	assert.NoError(t, closer.Close())
+
+	cfg.AccessLogEnabled = false
+	opt, _ = accessLog(cfg, zap.NewNop(), sampleRates)
+	assert.Nil(t, opt)
+}
+
+func TestDBSaturated(t *testing.T) {
+	stats := sql.DBStats{MaxOpenConnections: 25, InUse: 24, WaitCount: 3}
+	saturated := dbSaturated(func() sql.DBStats { return stats }, 0)
+
+	assert.False(t, saturated())
+	stats.InUse = 25
+	assert.False(t, saturated(), "a fully used pool without new waiters is not saturated")
+	stats.WaitCount = 5
+	assert.True(t, saturated())
+	assert.False(t, saturated(), "the waits were counted by the previous sample")
+	stats = sql.DBStats{InUse: 100, WaitCount: 10}
+	assert.False(t, saturated(), "an unbounded pool never saturates")
+
+	stats = sql.DBStats{MaxOpenConnections: 25, InUse: 25, WaitCount: 1}
+	sampled := dbSaturated(func() sql.DBStats { return stats }, time.Hour)
+	assert.True(t, sampled())
+	stats.InUse = 0
+	assert.True(t, sampled(), "the result is kept until the next sample")
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/godilite/qa-server/internal/config"
+	handler "github.com/godilite/qa-server/internal/grpc"
+	"github.com/godilite/qa-server/pkg/cache"
+	"go.uber.org/zap"
+)
+
+// Supported REDIS_MODE values.
+const (
+	redisModeStandalone = "standalone"
+	redisModeCluster    = "cluster"
+	redisModeSentinel   = "sentinel"
+)
+
+// Supported CACHE_BACKEND values.
+const (
+	cacheBackendRedis  = "redis"
+	cacheBackendMemory = "memory"
+	cacheBackendNone   = "none"
+)
+
+// newCache builds the cache stack selected by the config: the redis, memory or none backend,
+// an in-memory fallback behind Redis, and the in-process LRU tier in front.
+func newCache(ctx context.Context, cfg *config.Config, logger *zap.Logger) (handler.Cacher, error) {
+	var (
+		backend cache.Backend
+		bus     cache.InvalidationBus
+	)
+
+	switch cfg.CacheBackend {
+	case cacheBackendNone:
+		logger.Info("Caching disabled")
+		return cache.NewNoop(), nil
+
+	case cacheBackendMemory:
+		memory, err := cache.NewMemory(cache.WithMaxBytes(cfg.CacheMemoryMaxBytes))
+		if err != nil {
+			return nil, err
+		}
+		backend = memory
+		logger.Info("In-memory cache initialized", zap.Int64("max_bytes", cfg.CacheMemoryMaxBytes))
+
+	case cacheBackendRedis, "":
+		codec, err := cache.ParseCodec(cfg.CacheCodec)
+		if err != nil {
+			return nil, err
+		}
+		redisOpts, err := redisOptions(cfg)
+		if err != nil {
+			return nil, err
+		}
+		redisOpts = append(redisOpts,
+			cache.WithCodec(codec),
+			cache.WithCompressionThreshold(cfg.CacheCompressionBytes),
+		)
+		if cfg.CacheFallbackEnabled {
+			// An unreachable Redis degrades to the fallback below instead of failing startup.
+			redisOpts = append(redisOpts, cache.WithoutPing())
+		}
+		redisCache, err := cache.New(ctx, redisOpts...)
+		if err != nil {
+			return nil, err
+		}
+		backend = redisCache
+		logger.Info("Cache client initialized",
+			zap.String("mode", cfg.RedisMode),
+			zap.String("addr", cfg.RedisAddr),
+			zap.Strings("addrs", cfg.RedisAddrs),
+			zap.String("codec", codec.Name()),
+			zap.Int("compression_threshold", cfg.CacheCompressionBytes))
+
+		if cfg.CacheFallbackEnabled {
+			memory, err := cache.NewMemory(cache.WithMaxBytes(cfg.CacheMemoryMaxBytes))
+			if err != nil {
+				_ = redisCache.Close()
+				return nil, err
+			}
+			fallback := cache.NewFallback(redisCache, memory, cache.WithFallbackLogger(logger))
+			backend = fallback
+			if err := redisCache.Ping(ctx); err != nil {
+				logger.Warn("Redis unreachable at startup, serving from the in-memory fallback", zap.Error(err))
+				fallback.MarkUnavailable(err)
+				if cfg.CacheInvalidationChan != "" {
+					// Subscribing needs Redis; local entries expire after CACHE_LOCAL_TTL instead.
+					logger.Warn("Cache invalidation bus disabled until restart")
+				}
+				break
+			}
+		}
+		if cfg.CacheInvalidationChan != "" {
+			bus = cache.NewRedisBus(redisCache, cfg.CacheInvalidationChan)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q: must be redis, memory or none", cfg.CacheBackend)
+	}
+
+	if !cfg.CacheLocalEnabled {
+		return backend, nil
+	}
+
+	lruOpts := []cache.LRUOption{
+		cache.WithMaxEntries(cfg.CacheLocalMaxEntries),
+		cache.WithLocalTTL(cfg.CacheLocalTTL),
+		cache.WithLRULogger(logger),
+	}
+	if bus != nil {
+		lruOpts = append(lruOpts, cache.WithInvalidationBus(bus))
+	}
+
+	localCache, err := cache.NewLRU(ctx, backend, lruOpts...)
+	if err != nil {
+		_ = backend.Close()
+		return nil, fmt.Errorf("local cache tier: %w", err)
+	}
+	logger.Info("Local cache tier enabled",
+		zap.Int("max_entries", cfg.CacheLocalMaxEntries),
+		zap.Duration("ttl", cfg.CacheLocalTTL),
+		zap.Bool("invalidation_bus", bus != nil))
+
+	return localCache, nil
+}
+
+// redisOptions translates the REDIS_* settings into cache options. REDIS_ADDRS lists the
+// cluster seed nodes or Sentinels; in standalone mode REDIS_ADDR is used.
+func redisOptions(cfg *config.Config) ([]cache.Option, error) {
+	opts := []cache.Option{
+		cache.WithUsername(cfg.RedisUsername),
+		cache.WithPassword(cfg.RedisPassword),
+		cache.WithPool(cfg.RedisPoolSize, cfg.RedisMinIdleConns, cfg.RedisPoolTimeout),
+	}
+
+	switch cfg.RedisMode {
+	case redisModeStandalone, "":
+		opts = append(opts, cache.WithAddress(cfg.RedisAddr))
+	case redisModeCluster:
+		opts = append(opts, cache.WithCluster(cfg.RedisAddrs...))
+	case redisModeSentinel:
+		if cfg.RedisSentinelMaster == "" {
+			return nil, fmt.Errorf("REDIS_SENTINEL_MASTER is required in sentinel mode")
+		}
+		opts = append(opts,
+			cache.WithSentinel(cfg.RedisSentinelMaster, cfg.RedisAddrs...),
+			cache.WithSentinelAuth(cfg.RedisSentinelUsername, cfg.RedisSentinelPassword))
+	default:
+		return nil, fmt.Errorf("unknown redis mode %q: must be standalone, cluster or sentinel", cfg.RedisMode)
+	}
+
+	if cfg.RedisTLSEnabled {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if cfg.RedisTLSCAFile != "" {
+			pem, err := os.ReadFile(cfg.RedisTLSCAFile)
+			if err != nil {
+				return nil, fmt.Errorf("read redis CA file: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in redis CA file %s", cfg.RedisTLSCAFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		opts = append(opts, cache.WithTLS(tlsConfig))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/godilite/qa-server/internal/config"
+	"github.com/godilite/qa-server/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testCacheConfig(backend string) *config.Config {
+	return &config.Config{
+		CacheBackend:         backend,
+		CacheMemoryMaxBytes:  1 << 20,
+		CacheFallbackEnabled: true,
+		CacheLocalEnabled:    false,
+		CacheLocalMaxEntries: 100,
+		CacheLocalTTL:        time.Second,
+		CacheNegativeTTL:     time.Second,
+		CacheCodec:           "protobuf",
+	}
+}
+
+func TestNewCacheBackends(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	tests := []struct {
+		name     string
+		cfg      func() *config.Config
+		expected any
+	}{
+		{"none", func() *config.Config { return testCacheConfig("none") }, &cache.Noop{}},
+		{"memory", func() *config.Config { return testCacheConfig("memory") }, &cache.Memory{}},
+		{"redis with fallback", func() *config.Config {
+			cfg := testCacheConfig("redis")
+			cfg.RedisAddr = mr.Addr()
+			return cfg
+		}, &cache.Fallback{}},
+		{"redis without fallback", func() *config.Config {
+			cfg := testCacheConfig("redis")
+			cfg.RedisAddr = mr.Addr()
+			cfg.CacheFallbackEnabled = false
+			return cfg
+		}, &cache.Cache{}},
+		{"redis cluster", func() *config.Config {
+			cfg := testCacheConfig("redis")
+			cfg.RedisMode = "cluster"
+			cfg.RedisAddrs = []string{mr.Addr()}
+			cfg.CacheFallbackEnabled = false
+			return cfg
+		}, &cache.Cache{}},
+		{"local tier", func() *config.Config {
+			cfg := testCacheConfig("memory")
+			cfg.CacheLocalEnabled = true
+			return cfg
+		}, &cache.LRU{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newCache(ctx, tt.cfg(), zap.NewNop())
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = c.Close()
+			})
+
+			assert.IsType(t, tt.expected, c)
+		})
+	}
+}
+
+func TestNewCacheErrors(t *testing.T) {
+	_, err := newCache(context.Background(), testCacheConfig("memcached"), zap.NewNop())
+	assert.Error(t, err)
+
+	cfg := testCacheConfig("redis")
+	cfg.RedisAddr = miniredis.RunT(t).Addr()
+	cfg.CacheCodec = "gob"
+	_, err = newCache(context.Background(), cfg, zap.NewNop())
+	assert.Error(t, err)
+
+	cfg = testCacheConfig("redis")
+	cfg.RedisMode = "sharded"
+	_, err = newCache(context.Background(), cfg, zap.NewNop())
+	assert.Error(t, err)
+
+	cfg = testCacheConfig("redis")
+	cfg.RedisMode = "sentinel"
+	cfg.RedisAddrs = []string{"127.0.0.1:1"}
+	_, err = newCache(context.Background(), cfg, zap.NewNop())
+	assert.ErrorContains(t, err, "REDIS_SENTINEL_MASTER")
+
+	cfg = testCacheConfig("redis")
+	cfg.RedisTLSEnabled = true
+	cfg.RedisTLSCAFile = "/nonexistent/ca.pem"
+	_, err = newCache(context.Background(), cfg, zap.NewNop())
+	assert.ErrorContains(t, err, "CA file")
+
+	cfg = testCacheConfig("redis")
+	cfg.RedisAddr = "127.0.0.1:1"
+	cfg.CacheFallbackEnabled = false
+	_, err = newCache(context.Background(), cfg, zap.NewNop())
+	assert.Error(t, err, "without the fallback Redis must be reachable at startup")
+}
+
+func TestNewCacheStartsDegradedWithoutRedis(t *testing.T) {
+	cfg := testCacheConfig("redis")
+	cfg.RedisAddr = "127.0.0.1:1"
+	cfg.CacheLocalEnabled = true
+	cfg.CacheInvalidationChan = cache.DefaultInvalidationChannel
+
+	c, err := newCache(context.Background(), cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+
+	require.NoError(t, c.Set(context.Background(), "k", 1, time.Minute))
+	var v int
+	require.NoError(t, c.Get(context.Background(), "k", &v))
+	assert.Equal(t, 1, v, "the in-memory fallback serves while Redis is down")
+
+	tiers := cache.Status(context.Background(), c)
+	require.Len(t, tiers, 4)
+	assert.Equal(t, cache.TierStatus{Tier: "fallback", Detail: "degraded: serving from secondary"}, tiers[1])
+}
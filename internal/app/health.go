@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/internal/config"
+	"github.com/godilite/qa-server/pkg/cache"
+	"github.com/godilite/qa-server/pkg/health"
+	"go.uber.org/zap"
+)
+
+// newHealthChecker checks the database, without which no RPC can be answered, and the
+// cache, whose failures only cost latency because lookups fall through to the database.
+// Readiness is published as the overall gRPC health status and that of TicketScoring. The
+// Admin service keeps reporting SERVING, as it is most useful while dependencies are down.
+func newHealthChecker(cfg *config.Config, db *sql.DB, backend cache.Backend, setter health.StatusSetter, logger *zap.Logger) (*health.Checker, error) {
+	return health.New(
+		health.WithInterval(cfg.HealthCheckInterval),
+		health.WithTimeout(cfg.HealthCheckTimeout),
+		health.WithLogger(logger),
+		health.WithCheck("database", db.PingContext),
+		health.WithOptionalCheck("cache", cacheCheck(backend)),
+		health.WithServingStatus(setter, "", pb.TicketScoring_ServiceDesc.ServiceName),
+	)
+}
+
+// cacheCheck fails when any tier of the cache stack reports itself unhealthy.
+func cacheCheck(backend cache.Backend) health.CheckFunc {
+	return func(ctx context.Context) error {
+		var errs []error
+		for _, t := range cache.Status(ctx, backend) {
+			if !t.Healthy {
+				errs = append(errs, fmt.Errorf("%s: %s", t.Tier, t.Detail))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
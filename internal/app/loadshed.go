@@ -0,0 +1,63 @@
+package app
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/internal/config"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+)
+
+// concurrencyLimiter returns the per-method limiter shedding TicketScoring load, or nil when
+// it is disabled. The Admin service and reflection stay exempt so operators can still look
+// inside an overloaded server.
+func concurrencyLimiter(cfg *config.Config) *grpcsrv.ConcurrencyLimiter {
+	if !cfg.ConcurrencyEnabled {
+		return nil
+	}
+	return grpcsrv.NewConcurrencyLimiter(append(limiterOptions(cfg),
+		grpcsrv.WithExemptServices(
+			pb.Admin_ServiceDesc.ServiceName,
+			"grpc.reflection.v1.ServerReflection",
+			"grpc.reflection.v1alpha.ServerReflection",
+		),
+	)...)
+}
+
+// limiterOptions returns the limiter settings of cfg that can change on reload.
+func limiterOptions(cfg *config.Config) []grpcsrv.LimiterOption {
+	return []grpcsrv.LimiterOption{
+		grpcsrv.WithLimit(cfg.ConcurrencyLimit, cfg.ConcurrencyLimitMin, cfg.ConcurrencyLimitMax),
+		grpcsrv.WithLatencyThreshold(cfg.ConcurrencySlowAfter),
+		grpcsrv.WithQueue(cfg.ConcurrencyQueueSize, cfg.ConcurrencyQueueWait),
+	}
+}
+
+// dbSaturationInterval is how often dbSaturated samples the pool statistics.
+const dbSaturationInterval = 100 * time.Millisecond
+
+// dbSaturated reports the database as saturated while every connection the pool may open
+// is in use and queries have had to wait for one since the previous sample, taken at most
+// every interval. A pool that is merely fully used by queries finishing in time is not
+// saturated.
+func dbSaturated(stats func() sql.DBStats, interval time.Duration) func() bool {
+	var (
+		mu        sync.Mutex
+		sampled   time.Time
+		waits     int64
+		saturated bool
+	)
+	return func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if time.Since(sampled) < interval {
+			return saturated
+		}
+		s := stats()
+		saturated = s.MaxOpenConnections > 0 && s.InUse >= s.MaxOpenConnections && s.WaitCount > waits
+		sampled, waits = time.Now(), s.WaitCount
+		return saturated
+	}
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/godilite/qa-server/internal/timeexpr"
+	"go.uber.org/zap"
+)
+
+// warmTimeout bounds one window's warm-up, so a slow query cannot stall the schedule.
+const warmTimeout = time.Minute
+
+// cacheWarmer loads a window into the cache. It is implemented by the gRPC handlers.
+type cacheWarmer interface {
+	Warm(ctx context.Context, start, end time.Time) error
+}
+
+// warmWindow is a relative window such as -7d..today, in timeexpr syntax.
+type warmWindow struct {
+	from, to string
+}
+
+func (w warmWindow) String() string {
+	return w.from + ".." + w.to
+}
+
+// parseWarmWindows parses FROM..TO specs and checks that both ends resolve.
+func parseWarmWindows(specs []string) ([]warmWindow, error) {
+	windows := make([]warmWindow, 0, len(specs))
+	for _, spec := range specs {
+		from, to, ok := strings.Cut(spec, "..")
+		if !ok {
+			return nil, fmt.Errorf("cache warm window %q: want FROM..TO", spec)
+		}
+		w := warmWindow{from: strings.TrimSpace(from), to: strings.TrimSpace(to)}
+		if _, _, err := w.resolve(time.Now()); err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+func (w warmWindow) resolve(now time.Time) (start, end time.Time, err error) {
+	if start, err = timeexpr.Parse(w.from, now); err != nil {
+		return start, end, fmt.Errorf("cache warm window %s: %w", w, err)
+	}
+	if end, err = timeexpr.Parse(w.to, now); err != nil {
+		return start, end, fmt.Errorf("cache warm window %s: %w", w, err)
+	}
+	if end.Before(start) {
+		return start, end, fmt.Errorf("cache warm window %s: end is before start", w)
+	}
+	return start, end, nil
+}
+
+// warmer keeps the cache populated for common dashboard windows. It runs at startup and
+// then at every multiple of interval since midnight UTC, so for intervals that divide a
+// day a run always falls on midnight, when the day-truncated cache keys roll over.
+type warmer struct {
+	target   cacheWarmer
+	windows  []warmWindow
+	interval time.Duration
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+func newWarmer(target cacheWarmer, windows []warmWindow, interval time.Duration, logger *zap.Logger) *warmer {
+	return &warmer{
+		target:   target,
+		windows:  windows,
+		interval: interval,
+		logger:   logger.Named("cache-warmer"),
+		now:      time.Now,
+	}
+}
+
+// Run warms every window now and on each scheduled tick until ctx is cancelled.
+func (w *warmer) Run(ctx context.Context) {
+	for {
+		w.warmAll(ctx)
+
+		timer := time.NewTimer(w.untilNext(w.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// untilNext returns the time from now until the next multiple of interval.
+func (w *warmer) untilNext(now time.Time) time.Duration {
+	return now.Truncate(w.interval).Add(w.interval).Sub(now)
+}
+
+func (w *warmer) warmAll(ctx context.Context) {
+	now := w.now()
+	for _, window := range w.windows {
+		if ctx.Err() != nil {
+			return
+		}
+		start, end, err := window.resolve(now)
+		if err != nil {
+			w.logger.Warn("cache warm skipped", zap.Error(err))
+			continue
+		}
+
+		began := time.Now()
+		warmCtx, cancel := context.WithTimeout(ctx, warmTimeout)
+		err = w.target.Warm(warmCtx, start, end)
+		cancel()
+		if err != nil {
+			w.logger.Warn("cache warm failed", zap.Stringer("window", window), zap.Error(err))
+			continue
+		}
+		w.logger.Debug("cache warmed",
+			zap.Stringer("window", window),
+			zap.Time("start", start),
+			zap.Time("end", end),
+			zap.Duration("took", time.Since(began)))
+	}
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type recordingWarmer struct {
+	mu      sync.Mutex
+	windows [][2]time.Time
+	err     error
+}
+
+func (r *recordingWarmer) Warm(ctx context.Context, start, end time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.windows = append(r.windows, [2]time.Time{start, end})
+	return r.err
+}
+
+func (r *recordingWarmer) calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.windows)
+}
+
+func TestParseWarmWindows(t *testing.T) {
+	windows, err := parseWarmWindows([]string{"-7d..today", " start-of-quarter .. now "})
+	require.NoError(t, err)
+	assert.Equal(t, []warmWindow{{"-7d", "today"}, {"start-of-quarter", "now"}}, windows)
+
+	for _, spec := range []string{"-7d", "-7d..someday", "today..-7d"} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := parseWarmWindows([]string{spec})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWarmerWarmsResolvedWindows(t *testing.T) {
+	target := &recordingWarmer{}
+	windows, err := parseWarmWindows([]string{"-7d..today", "start-of-month..today"})
+	require.NoError(t, err)
+
+	w := newWarmer(target, windows, time.Hour, zap.NewNop())
+	w.now = func() time.Time { return time.Date(2025, 5, 14, 15, 30, 0, 0, time.UTC) }
+	w.warmAll(context.Background())
+
+	today := time.Date(2025, 5, 14, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, [][2]time.Time{
+		{time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC), today},
+		{time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), today},
+	}, target.windows)
+}
+
+func TestWarmerContinuesAfterFailure(t *testing.T) {
+	target := &recordingWarmer{err: errors.New("db down")}
+	windows, err := parseWarmWindows([]string{"-7d..today", "-30d..today"})
+	require.NoError(t, err)
+
+	newWarmer(target, windows, time.Hour, zap.NewNop()).warmAll(context.Background())
+
+	assert.Equal(t, 2, target.calls())
+}
+
+func TestWarmerScheduleAlignsToMidnight(t *testing.T) {
+	w := newWarmer(&recordingWarmer{}, nil, 5*time.Minute, zap.NewNop())
+
+	assert.Equal(t, 3*time.Minute, w.untilNext(time.Date(2025, 5, 14, 23, 57, 0, 0, time.UTC)))
+	assert.Equal(t, 5*time.Minute, w.untilNext(time.Date(2025, 5, 14, 0, 0, 0, 0, time.UTC)))
+
+	w.interval = 24 * time.Hour
+	assert.Equal(t, 30*time.Minute, w.untilNext(time.Date(2025, 5, 14, 23, 30, 0, 0, time.UTC)))
+}
+
+func TestWarmerRunStopsOnCancel(t *testing.T) {
+	target := &recordingWarmer{}
+	windows, err := parseWarmWindows([]string{"-7d..today"})
+	require.NoError(t, err)
+	w := newWarmer(target, windows, time.Hour, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return target.calls() == 1 }, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("warmer did not stop")
+	}
+}
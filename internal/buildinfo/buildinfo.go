@@ -0,0 +1,40 @@
+// Package buildinfo identifies the running binary.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Version is set at build time with
+// -ldflags "-X github.com/godilite/qa-server/internal/buildinfo.Version=v1.2.3".
+var Version = "dev"
+
+var startTime = time.Now()
+
+// Info describes the running binary.
+type Info struct {
+	Version   string
+	Revision  string
+	GoVersion string
+	StartTime time.Time
+}
+
+// Get returns the build information, with the VCS revision recorded by the Go toolchain
+// when the binary was built from a checkout.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		GoVersion: runtime.Version(),
+		StartTime: startTime,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.revision" {
+				info.Revision = s.Value
+			}
+		}
+	}
+	return info
+}
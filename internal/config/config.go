@@ -1,57 +1,588 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"os"
+	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds all configuration for the application.
+//
+// Each field is named by its env tag. Settings tagged reload can be changed on a running
+// server by editing the config file and sending SIGHUP; the rest need a restart. Settings
+// tagged secret are hidden by Redacted.
 type Config struct {
-	AppEnv                string
-	DBPath                string
-	DBDriver              string
-	RedisAddr             string
-	GRPCPort              int
-	GRPCReflectionEnabled bool
-}
-
-// LoadFromEnv loads configuration from environment variables.
-func LoadFromEnv() *Config {
-	portStr := getEnv("GRPC_PORT", "50051")
-	port, err := strconv.Atoi(portStr)
+	AppEnv                string        `env:"APP_ENV"`
+	LogLevel              string        `env:"LOG_LEVEL" reload:"true"`
+	DBPath                string        `env:"DB_PATH"`
+	DBDriver              string        `env:"DB_DRIVER"`
+	DBMaxOpenConns        int           `env:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns        int           `env:"DB_MAX_IDLE_CONNS"`
+	DBConnMaxLifetime     time.Duration `env:"DB_CONN_MAX_LIFETIME"`
+	DBConnMaxIdleTime     time.Duration `env:"DB_CONN_MAX_IDLE_TIME"`
+	DBQueryTimeout        time.Duration `env:"DB_QUERY_TIMEOUT"`
+	DBQueryTimeoutPerDay  time.Duration `env:"DB_QUERY_TIMEOUT_PER_DAY"`
+	DBQueryTimeoutMax     time.Duration `env:"DB_QUERY_TIMEOUT_MAX"`
+	RedisAddr             string        `env:"REDIS_ADDR"`
+	RedisMode             string        `env:"REDIS_MODE"`
+	RedisAddrs            []string      `env:"REDIS_ADDRS"`
+	RedisSentinelMaster   string        `env:"REDIS_SENTINEL_MASTER"`
+	RedisUsername         string        `env:"REDIS_USERNAME"`
+	RedisPassword         string        `env:"REDIS_PASSWORD" secret:"true"`
+	RedisSentinelUsername string        `env:"REDIS_SENTINEL_USERNAME"`
+	RedisSentinelPassword string        `env:"REDIS_SENTINEL_PASSWORD" secret:"true"`
+	RedisTLSEnabled       bool          `env:"REDIS_TLS_ENABLED"`
+	RedisTLSCAFile        string        `env:"REDIS_TLS_CA_FILE"`
+	RedisPoolSize         int           `env:"REDIS_POOL_SIZE"`
+	RedisMinIdleConns     int           `env:"REDIS_MIN_IDLE_CONNS"`
+	RedisPoolTimeout      time.Duration `env:"REDIS_POOL_TIMEOUT"`
+	CacheBackend          string        `env:"CACHE_BACKEND"`
+	CacheTTL              time.Duration `env:"CACHE_TTL" reload:"true"`
+	CacheMemoryMaxBytes   int64         `env:"CACHE_MEMORY_MAX_BYTES"`
+	CacheFallbackEnabled  bool          `env:"CACHE_FALLBACK_ENABLED"`
+	CacheRefreshPolicy    string        `env:"CACHE_REFRESH_POLICY" reload:"true"`
+	CacheRefreshWindow    float64       `env:"CACHE_REFRESH_WINDOW" reload:"true"`
+	CacheLocalEnabled     bool          `env:"CACHE_LOCAL_ENABLED"`
+	CacheLocalMaxEntries  int           `env:"CACHE_LOCAL_MAX_ENTRIES"`
+	CacheLocalTTL         time.Duration `env:"CACHE_LOCAL_TTL"`
+	CacheNegativeTTL      time.Duration `env:"CACHE_NEGATIVE_TTL" reload:"true"`
+	CacheStaleTTL         time.Duration `env:"CACHE_STALE_TTL"`
+	CacheInvalidationChan string        `env:"CACHE_INVALIDATION_CHANNEL"`
+	CacheCodec            string        `env:"CACHE_CODEC"`
+	CacheCompressionBytes int           `env:"CACHE_COMPRESSION_THRESHOLD"`
+	CacheFetchTimeout     time.Duration `env:"CACHE_FETCH_TIMEOUT"`
+	CacheSetTimeout       time.Duration `env:"CACHE_SET_TIMEOUT"`
+	CacheWarmEnabled      bool          `env:"CACHE_WARM_ENABLED"`
+	CacheWarmWindows      []string      `env:"CACHE_WARM_WINDOWS"`
+	CacheWarmInterval     time.Duration `env:"CACHE_WARM_INTERVAL"`
+	GRPCPort              int           `env:"GRPC_PORT"`
+	GRPCListenAddresses   []string      `env:"GRPC_LISTEN_ADDRESSES"`
+	GRPCReflectionEnabled bool          `env:"GRPC_REFLECTION_ENABLED"`
+	GRPCTimeout           time.Duration `env:"GRPC_TIMEOUT"`
+	GRPCMethodTimeouts    []string      `env:"GRPC_METHOD_TIMEOUTS"`
+	GRPCMaxRecvMsgBytes   int           `env:"GRPC_MAX_RECV_MSG_BYTES"`
+	GRPCMaxSendMsgBytes   int           `env:"GRPC_MAX_SEND_MSG_BYTES"`
+	GRPCGzipEnabled       bool          `env:"GRPC_GZIP_ENABLED"`
+	GRPCMaxStreams        int           `env:"GRPC_MAX_CONCURRENT_STREAMS"`
+	GRPCKeepaliveTime     time.Duration `env:"GRPC_KEEPALIVE_TIME"`
+	GRPCKeepaliveTimeout  time.Duration `env:"GRPC_KEEPALIVE_TIMEOUT"`
+	GRPCKeepaliveMinTime  time.Duration `env:"GRPC_KEEPALIVE_MIN_TIME"`
+	GRPCPingWithoutStream bool          `env:"GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM"`
+	GRPCMaxConnAge        time.Duration `env:"GRPC_MAX_CONNECTION_AGE"`
+	GRPCMaxConnAgeGrace   time.Duration `env:"GRPC_MAX_CONNECTION_AGE_GRACE"`
+	GRPCMaxConnIdle       time.Duration `env:"GRPC_MAX_CONNECTION_IDLE"`
+	ConcurrencyEnabled    bool          `env:"CONCURRENCY_LIMIT_ENABLED"`
+	ConcurrencyLimit      int           `env:"CONCURRENCY_LIMIT" reload:"true"`
+	ConcurrencyLimitMin   int           `env:"CONCURRENCY_LIMIT_MIN" reload:"true"`
+	ConcurrencyLimitMax   int           `env:"CONCURRENCY_LIMIT_MAX" reload:"true"`
+	ConcurrencySlowAfter  time.Duration `env:"CONCURRENCY_SLOW_AFTER" reload:"true"`
+	ConcurrencyQueueSize  int           `env:"CONCURRENCY_QUEUE_SIZE" reload:"true"`
+	ConcurrencyQueueWait  time.Duration `env:"CONCURRENCY_QUEUE_WAIT" reload:"true"`
+	AccessLogEnabled      bool          `env:"ACCESS_LOG_ENABLED"`
+	AccessLogFile         string        `env:"ACCESS_LOG_FILE"`
+	AccessLogMaxSizeMB    int           `env:"ACCESS_LOG_MAX_SIZE_MB"`
+	AccessLogMaxBackups   int           `env:"ACCESS_LOG_MAX_BACKUPS"`
+	AccessLogMaxAgeDays   int           `env:"ACCESS_LOG_MAX_AGE_DAYS"`
+	AccessLogSampleRate   float64       `env:"ACCESS_LOG_SAMPLE_RATE" reload:"true"`
+	AccessLogMethodRates  []string      `env:"ACCESS_LOG_METHOD_SAMPLE_RATES" reload:"true"`
+	AccessLogSlowAfter    time.Duration `env:"ACCESS_LOG_SLOW_AFTER"`
+	AdminEnabled          bool          `env:"ADMIN_ENABLED"`
+	AdminTokens           []string      `env:"ADMIN_TOKENS" secret:"true"`
+	RollupsEnabled        bool          `env:"ROLLUPS_ENABLED"`
+	RollupRefreshInterval time.Duration `env:"ROLLUP_REFRESH_INTERVAL"`
+	HTTPEnabled           bool          `env:"HTTP_ENABLED"`
+	HTTPPort              int           `env:"HTTP_PORT"`
+	ShutdownDrainPeriod   time.Duration `env:"SHUTDOWN_DRAIN_PERIOD"`
+	ShutdownTimeout       time.Duration `env:"SHUTDOWN_TIMEOUT"`
+	HealthEnabled         bool          `env:"HEALTH_ENABLED"`
+	HealthPort            int           `env:"HEALTH_PORT"`
+	HealthCheckInterval   time.Duration `env:"HEALTH_CHECK_INTERVAL"`
+	HealthCheckTimeout    time.Duration `env:"HEALTH_CHECK_TIMEOUT"`
+	MetricsEnabled        bool          `env:"METRICS_ENABLED"`
+	MetricsPort           int           `env:"METRICS_PORT"`
+	TracingExporter       string        `env:"TRACING_EXPORTER"`
+	TracingOTLPEndpoint   string        `env:"TRACING_OTLP_ENDPOINT"`
+	TracingOTLPInsecure   bool          `env:"TRACING_OTLP_INSECURE"`
+	TracingFile           string        `env:"TRACING_FILE"`
+	TracingSampleRatio    float64       `env:"TRACING_SAMPLE_RATIO"`
+}
+
+// Default returns the configuration used when neither the config file nor the environment
+// sets a value.
+func Default() *Config {
+	return &Config{
+		AppEnv:                "development",
+		DBPath:                "./data/database.db",
+		DBDriver:              "sqlite3",
+		DBMaxOpenConns:        25,
+		DBMaxIdleConns:        5,
+		DBConnMaxLifetime:     5 * time.Minute,
+		DBConnMaxIdleTime:     2 * time.Minute,
+		DBQueryTimeout:        time.Second,
+		DBQueryTimeoutPerDay:  20 * time.Millisecond,
+		DBQueryTimeoutMax:     15 * time.Second,
+		RedisAddr:             "localhost:6379",
+		RedisMode:             "standalone",
+		CacheBackend:          "redis",
+		CacheTTL:              10 * time.Minute,
+		CacheMemoryMaxBytes:   64 << 20,
+		CacheFallbackEnabled:  true,
+		CacheRefreshPolicy:    "near-expiry",
+		CacheRefreshWindow:    0.2,
+		CacheLocalEnabled:     true,
+		CacheLocalMaxEntries:  10000,
+		CacheLocalTTL:         30 * time.Second,
+		CacheNegativeTTL:      30 * time.Second,
+		CacheStaleTTL:         time.Hour,
+		CacheInvalidationChan: "qa:cache:invalidate",
+		CacheCodec:            "protobuf",
+		CacheCompressionBytes: 1024,
+		CacheFetchTimeout:     15 * time.Second,
+		CacheSetTimeout:       5 * time.Second,
+		CacheWarmWindows:      []string{"-7d..today", "-30d..today", "start-of-quarter..today"},
+		CacheWarmInterval:     5 * time.Minute,
+		GRPCPort:              50051,
+		GRPCTimeout:           10 * time.Second,
+		GRPCMaxRecvMsgBytes:   4 << 20,
+		GRPCMaxSendMsgBytes:   64 << 20,
+		GRPCGzipEnabled:       true,
+		GRPCMaxStreams:        1000,
+		GRPCKeepaliveTime:     2 * time.Minute,
+		GRPCKeepaliveTimeout:  20 * time.Second,
+		GRPCKeepaliveMinTime:  15 * time.Second,
+		GRPCPingWithoutStream: true,
+		GRPCMaxConnAge:        30 * time.Minute,
+		GRPCMaxConnAgeGrace:   5 * time.Minute,
+		RollupRefreshInterval: time.Minute,
+		ConcurrencyEnabled:    true,
+		ConcurrencyLimit:      25,
+		ConcurrencyLimitMin:   2,
+		ConcurrencyLimitMax:   100,
+		ConcurrencySlowAfter:  time.Second,
+		ConcurrencyQueueSize:  50,
+		ConcurrencyQueueWait:  200 * time.Millisecond,
+		AccessLogEnabled:      true,
+		AccessLogMaxSizeMB:    100,
+		AccessLogMaxBackups:   5,
+		AccessLogMaxAgeDays:   14,
+		AccessLogSampleRate:   1.0,
+		AccessLogSlowAfter:    time.Second,
+		HTTPEnabled:           true,
+		HTTPPort:              8080,
+		ShutdownDrainPeriod:   5 * time.Second,
+		ShutdownTimeout:       10 * time.Second,
+		HealthEnabled:         true,
+		HealthPort:            8081,
+		HealthCheckInterval:   10 * time.Second,
+		HealthCheckTimeout:    2 * time.Second,
+		MetricsEnabled:        true,
+		MetricsPort:           9090,
+		TracingExporter:       "none",
+		TracingFile:           "./data/traces.jsonl",
+		TracingSampleRatio:    1.0,
+	}
+}
+
+// Load builds the configuration from the defaults, overridden by the YAML file at path (if
+// path is not empty), overridden in turn by environment variables. The file uses the
+// lower-case variable names as keys, e.g. grpc_port: 50051. Every malformed or invalid
+// setting is reported in the returned error, not just the first.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	var errs []error
+
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, cfg.apply(values, path)...)
+	}
+	errs = append(errs, cfg.apply(envValues(), "environment")...)
+	errs = append(errs, cfg.validate()...)
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return cfg, nil
+}
+
+// Reload returns a copy of c with the reloadable settings taken from next, and the names of
+// the other settings that differ in next and so only take effect after a restart.
+func (c *Config) Reload(next *Config) (*Config, []string) {
+	updated := *c
+	var restart []string
+
+	cur := reflect.ValueOf(&updated).Elem()
+	nxt := reflect.ValueOf(next).Elem()
+	for i := 0; i < cur.NumField(); i++ {
+		field := cur.Type().Field(i)
+		if reflect.DeepEqual(cur.Field(i).Interface(), nxt.Field(i).Interface()) {
+			continue
+		}
+		if field.Tag.Get("reload") == "true" {
+			cur.Field(i).Set(nxt.Field(i))
+			continue
+		}
+		restart = append(restart, field.Tag.Get("env"))
+	}
+	return &updated, restart
+}
+
+// Redacted returns every setting as it would be written in the environment, keyed by
+// variable name, with the values of secrets replaced by "[redacted]".
+func (c *Config) Redacted() map[string]string {
+	out := make(map[string]string)
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		value := v.Field(i).Interface()
+
+		var s string
+		switch value := value.(type) {
+		case []string:
+			s = strings.Join(value, ",")
+		default:
+			s = fmt.Sprint(value)
+		}
+		if field.Tag.Get("secret") == "true" && s != "" {
+			s = "[redacted]"
+		}
+		out[field.Tag.Get("env")] = s
+	}
+	return out
+}
+
+// AccessLogSampleRates parses ACCESS_LOG_METHOD_SAMPLE_RATES, a list of Method=rate
+// overrides of ACCESS_LOG_SAMPLE_RATE such as GetOverallQualityScore=0.1.
+func (c *Config) AccessLogSampleRates() (map[string]float64, error) {
+	rates := make(map[string]float64, len(c.AccessLogMethodRates))
+	for _, item := range c.AccessLogMethodRates {
+		method, raw, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("ACCESS_LOG_METHOD_SAMPLE_RATES: %q: want Method=rate", item)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("ACCESS_LOG_METHOD_SAMPLE_RATES: %q: rate must be in [0, 1]", item)
+		}
+		rates[strings.TrimSpace(method)] = rate
+	}
+	return rates, nil
+}
+
+// MethodTimeouts parses GRPC_METHOD_TIMEOUTS, a list of Method=duration overrides of
+// GRPC_TIMEOUT such as ExportScores=2m.
+func (c *Config) MethodTimeouts() (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(c.GRPCMethodTimeouts))
+	for _, item := range c.GRPCMethodTimeouts {
+		method, raw, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("GRPC_METHOD_TIMEOUTS: %q: want Method=duration", item)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("GRPC_METHOD_TIMEOUTS: %q: not a positive duration", item)
+		}
+		timeouts[strings.TrimSpace(method)] = timeout
+	}
+	return timeouts, nil
+}
+
+// NewLogger creates a new Zap logger based on the config. The returned level controls the
+// logger at runtime and starts at cfg.Level.
+func NewLogger(cfg *Config) (*zap.Logger, zap.AtomicLevel, error) {
+	zcfg := zap.NewDevelopmentConfig()
+	if cfg.AppEnv == "production" {
+		zcfg = zap.NewProductionConfig()
+	}
+	level, err := cfg.Level()
 	if err != nil {
-		port = 50051
+		return nil, zcfg.Level, err
 	}
+	zcfg.Level.SetLevel(level)
 
-	reflectionStr := getEnv("GRPC_REFLECTION_ENABLED", "false")
-	reflection, err := strconv.ParseBool(reflectionStr)
+	logger, err := zcfg.Build()
+	return logger, zcfg.Level, err
+}
+
+// Level returns the log level set by LOG_LEVEL or, when it is unset, the environment's
+// default: info in production and debug otherwise.
+func (c *Config) Level() (zapcore.Level, error) {
+	if c.LogLevel != "" {
+		return zapcore.ParseLevel(c.LogLevel)
+	}
+	if c.AppEnv == "production" {
+		return zapcore.InfoLevel, nil
+	}
+	return zapcore.DebugLevel, nil
+}
+
+// readFile reads a flat YAML mapping of setting names to scalars or lists.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		reflection = false
+		return nil, fmt.Errorf("read config file: %w", err)
 	}
 
-	return &Config{
-		AppEnv:                getEnv("APP_ENV", "development"),
-		DBPath:                getEnv("DB_PATH", "./data/database.db"),
-		RedisAddr:             getEnv("REDIS_ADDR", "localhost:6379"),
-		DBDriver:              getEnv("DB_DRIVER", "sqlite3"),
-		GRPCPort:              port,
-		GRPCReflectionEnabled: reflection,
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, v := range raw {
+		name := strings.ToUpper(key)
+		switch v := v.(type) {
+		case nil:
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[name] = strings.Join(items, ",")
+		case map[string]any:
+			return nil, fmt.Errorf("config file %s: %s: nested settings are not supported", path, key)
+		default:
+			values[name] = fmt.Sprint(v)
+		}
 	}
+	return values, nil
 }
 
-// NewLogger creates a new Zap logger based on the config.
-func NewLogger(cfg *Config) (*zap.Logger, error) {
-	if cfg.AppEnv == "production" {
-		return zap.NewProduction()
+// envValues returns the set, non-empty environment variables that name a setting.
+func envValues() map[string]string {
+	values := make(map[string]string)
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("env")
+		if val := os.Getenv(name); val != "" {
+			values[name] = val
+		}
+	}
+	return values
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// apply parses values into the matching fields of c, keyed by env tag, and returns an error
+// for each value that does not parse or names no setting.
+func (c *Config) apply(values map[string]string, source string) []error {
+	var errs []error
+	known := make(map[string]bool, len(values))
+
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("env")
+		raw, ok := values[name]
+		if !ok {
+			continue
+		}
+		known[name] = true
+		if err := setField(v.Field(i), raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q (%s): %w", name, raw, source, err))
+		}
+	}
+
+	for name := range values {
+		if !known[name] {
+			errs = append(errs, fmt.Errorf("%s (%s): unknown setting", strings.ToLower(name), source))
+		}
 	}
-	return zap.NewDevelopment()
+	return errs
+}
+
+func setField(field reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.New("not a duration")
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("not a boolean")
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return errors.New("not an integer")
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return errors.New("not a number")
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		field.Set(reflect.ValueOf(splitList(raw)))
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
+
+// validate checks values and combinations that parse but cannot work.
+func (c *Config) validate() []error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(name, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s: %q must be one of %s", name, value, strings.Join(allowed, ", ")))
+	}
+
+	if c.LogLevel != "" {
+		_, err := zapcore.ParseLevel(c.LogLevel)
+		check(err == nil, "LOG_LEVEL: %q must be one of debug, info, warn, error", c.LogLevel)
+	}
+
+	check(c.DBPath != "", "DB_PATH: must not be empty")
+	check(c.DBDriver != "", "DB_DRIVER: must not be empty")
+	check(c.DBMaxOpenConns >= 0, "DB_MAX_OPEN_CONNS: must not be negative")
+	check(c.DBMaxIdleConns >= 0, "DB_MAX_IDLE_CONNS: must not be negative")
+	check(c.DBConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME: must not be negative")
+	check(c.DBConnMaxIdleTime >= 0, "DB_CONN_MAX_IDLE_TIME: must not be negative")
+	check(c.DBQueryTimeout > 0, "DB_QUERY_TIMEOUT: must be positive")
+	check(c.DBQueryTimeoutPerDay >= 0, "DB_QUERY_TIMEOUT_PER_DAY: must not be negative")
+	check(c.DBQueryTimeoutMax == 0 || c.DBQueryTimeoutMax >= c.DBQueryTimeout,
+		"DB_QUERY_TIMEOUT_MAX: must be 0 (uncapped) or at least DB_QUERY_TIMEOUT")
+
+	for _, addr := range c.GRPCListenAddresses {
+		check(strings.HasPrefix(addr, "unix:") || strings.Contains(addr, ":"),
+			"GRPC_LISTEN_ADDRESSES: %q: want host:port or unix:/path", addr)
+	}
+	check(c.GRPCTimeout > 0, "GRPC_TIMEOUT: must be positive")
+	check(c.GRPCMaxRecvMsgBytes > 0, "GRPC_MAX_RECV_MSG_BYTES: must be positive")
+	check(c.GRPCMaxSendMsgBytes > 0, "GRPC_MAX_SEND_MSG_BYTES: must be positive")
+	check(c.GRPCMaxStreams >= 0 && int64(c.GRPCMaxStreams) <= math.MaxUint32,
+		"GRPC_MAX_CONCURRENT_STREAMS: must be between 0 (unlimited) and %d", uint32(math.MaxUint32))
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"GRPC_KEEPALIVE_TIME", c.GRPCKeepaliveTime},
+		{"GRPC_KEEPALIVE_TIMEOUT", c.GRPCKeepaliveTimeout},
+		{"GRPC_KEEPALIVE_MIN_TIME", c.GRPCKeepaliveMinTime},
+		{"GRPC_MAX_CONNECTION_AGE", c.GRPCMaxConnAge},
+		{"GRPC_MAX_CONNECTION_AGE_GRACE", c.GRPCMaxConnAgeGrace},
+		{"GRPC_MAX_CONNECTION_IDLE", c.GRPCMaxConnIdle},
+	} {
+		check(d.value >= 0, "%s: must not be negative", d.name)
+	}
+	check(!c.AdminEnabled || len(c.AdminTokens) > 0, "ADMIN_TOKENS: required when ADMIN_ENABLED is set")
+	for i, t := range c.AdminTokens {
+		// The token itself is a secret, so entries are named by position.
+		name, token, named := strings.Cut(t, ":")
+		check(!named || (name != "" && token != ""), "ADMIN_TOKENS: entry %d: want name:token with neither empty", i+1)
+	}
+	if _, err := c.MethodTimeouts(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.ConcurrencyEnabled {
+		check(c.ConcurrencyLimitMin > 0, "CONCURRENCY_LIMIT_MIN: must be positive")
+		check(c.ConcurrencyLimit >= c.ConcurrencyLimitMin && c.ConcurrencyLimit <= c.ConcurrencyLimitMax,
+			"CONCURRENCY_LIMIT: must be between CONCURRENCY_LIMIT_MIN and CONCURRENCY_LIMIT_MAX")
+		check(c.ConcurrencySlowAfter > 0, "CONCURRENCY_SLOW_AFTER: must be positive")
+		check(c.ConcurrencyQueueSize >= 0, "CONCURRENCY_QUEUE_SIZE: must not be negative")
+		check(c.ConcurrencyQueueWait >= 0, "CONCURRENCY_QUEUE_WAIT: must not be negative")
+	}
+	check(c.AccessLogSampleRate >= 0 && c.AccessLogSampleRate <= 1, "ACCESS_LOG_SAMPLE_RATE: must be in [0, 1]")
+	check(c.AccessLogSlowAfter >= 0, "ACCESS_LOG_SLOW_AFTER: must not be negative")
+	check(c.AccessLogMaxSizeMB > 0, "ACCESS_LOG_MAX_SIZE_MB: must be positive")
+	check(c.AccessLogMaxBackups >= 0, "ACCESS_LOG_MAX_BACKUPS: must not be negative")
+	check(c.AccessLogMaxAgeDays >= 0, "ACCESS_LOG_MAX_AGE_DAYS: must not be negative")
+	if _, err := c.AccessLogSampleRates(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, p := range []struct {
+		name string
+		port int
+	}{{"GRPC_PORT", c.GRPCPort}, {"HTTP_PORT", c.HTTPPort}, {"HEALTH_PORT", c.HealthPort}, {"METRICS_PORT", c.MetricsPort}} {
+		check(p.port > 0 && p.port <= 65535, "%s: %d is not a valid port", p.name, p.port)
+	}
+
+	oneOf("CACHE_BACKEND", c.CacheBackend, "redis", "memory", "none")
+	oneOf("CACHE_CODEC", c.CacheCodec, "protobuf", "msgpack", "json")
+	oneOf("CACHE_REFRESH_POLICY", c.CacheRefreshPolicy, "near-expiry", "always", "never")
+	check(c.CacheTTL > 0, "CACHE_TTL: must be positive")
+	check(c.CacheRefreshWindow > 0 && c.CacheRefreshWindow <= 1, "CACHE_REFRESH_WINDOW: must be in (0, 1]")
+	check(c.CacheNegativeTTL >= 0, "CACHE_NEGATIVE_TTL: must not be negative")
+	check(c.CacheStaleTTL >= 0, "CACHE_STALE_TTL: must not be negative")
+	check(c.CacheMemoryMaxBytes > 0 || !c.usesMemoryCache(), "CACHE_MEMORY_MAX_BYTES: must be positive")
+	check(c.CacheLocalMaxEntries > 0 || !c.CacheLocalEnabled, "CACHE_LOCAL_MAX_ENTRIES: must be positive")
+	check(c.CacheLocalTTL > 0 || !c.CacheLocalEnabled, "CACHE_LOCAL_TTL: must be positive")
+	check(c.CacheCompressionBytes >= 0, "CACHE_COMPRESSION_THRESHOLD: must not be negative")
+	check(c.CacheFetchTimeout > 0, "CACHE_FETCH_TIMEOUT: must be positive")
+	check(c.CacheSetTimeout > 0, "CACHE_SET_TIMEOUT: must be positive")
+	check(c.CacheWarmInterval > 0, "CACHE_WARM_INTERVAL: must be positive")
+	check(c.RollupRefreshInterval > 0, "ROLLUP_REFRESH_INTERVAL: must be positive")
+	check(c.ShutdownDrainPeriod >= 0, "SHUTDOWN_DRAIN_PERIOD: must not be negative")
+	check(c.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT: must be positive")
+	check(c.HealthCheckInterval > 0, "HEALTH_CHECK_INTERVAL: must be positive")
+	check(c.HealthCheckTimeout > 0, "HEALTH_CHECK_TIMEOUT: must be positive")
+
+	if c.CacheBackend == "redis" {
+		oneOf("REDIS_MODE", c.RedisMode, "standalone", "cluster", "sentinel")
+		switch c.RedisMode {
+		case "cluster":
+			check(len(c.RedisAddrs) > 0, "REDIS_ADDRS: required in cluster mode")
+		case "sentinel":
+			check(len(c.RedisAddrs) > 0, "REDIS_ADDRS: required in sentinel mode")
+			check(c.RedisSentinelMaster != "", "REDIS_SENTINEL_MASTER: required in sentinel mode")
+		}
+		check(c.RedisPoolSize >= 0, "REDIS_POOL_SIZE: must not be negative")
+		check(c.RedisMinIdleConns >= 0, "REDIS_MIN_IDLE_CONNS: must not be negative")
+		check(c.RedisPoolTimeout >= 0, "REDIS_POOL_TIMEOUT: must not be negative")
+	}
+
+	oneOf("TRACING_EXPORTER", c.TracingExporter, "none", "stdout", "file", "otlp")
+	check(c.TracingSampleRatio >= 0 && c.TracingSampleRatio <= 1, "TRACING_SAMPLE_RATIO: must be in [0, 1]")
+
+	return errs
+}
+
+// usesMemoryCache reports whether the in-memory cache is used, as the backend or as the
+// fallback for an unreachable Redis.
+func (c *Config) usesMemoryCache() bool {
+	return c.CacheBackend == "memory" || (c.CacheBackend == "redis" && c.CacheFallbackEnabled)
 }
 
-func getEnv(key, fallback string) string {
-	if val := os.Getenv(key); val != "" {
-		return val
+// splitList splits a comma-separated value, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
 	}
-	return fallback
+	return out
 }
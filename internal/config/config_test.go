@@ -0,0 +1,265 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load("")
+
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoadFileUnderEnv(t *testing.T) {
+	path := writeConfigFile(t, `
+grpc_port: 6000
+http_port: 8081
+cache_ttl: 2m
+cache_refresh_window: 0.5
+cache_warm_windows: [-7d..today, -1y..today]
+redis_tls_enabled: true
+`)
+	t.Setenv("HTTP_PORT", "9000")
+
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, 6000, cfg.GRPCPort)
+	assert.Equal(t, 9000, cfg.HTTPPort, "environment overrides the file")
+	assert.Equal(t, 2*time.Minute, cfg.CacheTTL)
+	assert.Equal(t, 0.5, cfg.CacheRefreshWindow)
+	assert.Equal(t, []string{"-7d..today", "-1y..today"}, cfg.CacheWarmWindows)
+	assert.True(t, cfg.RedisTLSEnabled)
+	assert.Equal(t, "sqlite3", cfg.DBDriver, "unset settings keep their defaults")
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	path := writeConfigFile(t, `
+cache_ttl: 600
+cache_backend: memcached
+grpc_prot: 6000
+`)
+	t.Setenv("GRPC_PORT", "5o051")
+	t.Setenv("METRICS_ENABLED", "yes please")
+	t.Setenv("TRACING_SAMPLE_RATIO", "2")
+
+	_, err := Load(path)
+
+	require.Error(t, err)
+	for _, want := range []string{
+		`GRPC_PORT="5o051" (environment): not an integer`,
+		`METRICS_ENABLED="yes please" (environment): not a boolean`,
+		`CACHE_TTL="600"`,
+		`grpc_prot (` + path + `): unknown setting`,
+		`CACHE_BACKEND: "memcached" must be one of redis, memory, none`,
+		`TRACING_SAMPLE_RATIO: must be in [0, 1]`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadValidatesCombinations(t *testing.T) {
+	t.Setenv("REDIS_MODE", "sentinel")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "REDIS_ADDRS: required in sentinel mode")
+	assert.Contains(t, err.Error(), "REDIS_SENTINEL_MASTER: required in sentinel mode")
+
+	t.Setenv("CACHE_BACKEND", "memory")
+	_, err = Load("")
+	assert.NoError(t, err, "redis settings are ignored without the redis backend")
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReload(t *testing.T) {
+	current := Default()
+	next := Default()
+	next.CacheTTL = time.Minute
+	next.LogLevel = "warn"
+	next.GRPCPort = 6000
+	next.CacheWarmWindows = []string{"-1d..today"}
+
+	updated, restart := current.Reload(next)
+
+	assert.Equal(t, time.Minute, updated.CacheTTL)
+	assert.Equal(t, "warn", updated.LogLevel)
+	assert.Equal(t, 50051, updated.GRPCPort, "non-reloadable settings keep their value")
+	assert.ElementsMatch(t, []string{"GRPC_PORT", "CACHE_WARM_WINDOWS"}, restart)
+	assert.Equal(t, 10*time.Minute, current.CacheTTL, "the receiver is not modified")
+}
+
+func TestNewLoggerLevel(t *testing.T) {
+	cfg := Default()
+	cfg.AppEnv = "production"
+
+	_, level, err := NewLogger(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.InfoLevel, level.Level())
+
+	cfg.LogLevel = "error"
+	_, level, err = NewLogger(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.ErrorLevel, level.Level())
+
+	cfg.AppEnv = "development"
+	cfg.LogLevel = ""
+	_, level, err = NewLogger(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.DebugLevel, level.Level())
+}
+
+func TestLoadExampleFile(t *testing.T) {
+	cfg, err := Load(filepath.Join("..", "..", "config.example.yaml"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "production", cfg.AppEnv)
+	assert.Len(t, cfg.CacheWarmWindows, 3)
+}
+
+func TestMethodTimeouts(t *testing.T) {
+	cfg := Default()
+	cfg.GRPCMethodTimeouts = []string{"ExportScores=2m", " GetScoresByTicket = 30s "}
+
+	timeouts, err := cfg.MethodTimeouts()
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"ExportScores":      2 * time.Minute,
+		"GetScoresByTicket": 30 * time.Second,
+	}, timeouts)
+
+	for _, item := range []string{"ExportScores", "ExportScores=soon", "ExportScores=-1s"} {
+		cfg.GRPCMethodTimeouts = []string{item}
+		_, err := cfg.MethodTimeouts()
+		assert.Error(t, err, item)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.RedisPassword = "hunter2"
+	cfg.AdminTokens = []string{"a", "b"}
+
+	settings := cfg.Redacted()
+
+	assert.Equal(t, "[redacted]", settings["REDIS_PASSWORD"])
+	assert.Equal(t, "[redacted]", settings["ADMIN_TOKENS"])
+	assert.Equal(t, "", settings["REDIS_SENTINEL_PASSWORD"], "empty secrets show they are unset")
+	assert.Equal(t, "50051", settings["GRPC_PORT"])
+	assert.Equal(t, "10m0s", settings["CACHE_TTL"])
+}
+
+func TestLoadRequiresAdminTokens(t *testing.T) {
+	t.Setenv("ADMIN_ENABLED", "true")
+
+	_, err := Load("")
+	assert.ErrorContains(t, err, "ADMIN_TOKENS: required when ADMIN_ENABLED is set")
+
+	t.Setenv("ADMIN_TOKENS", "secret")
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"secret"}, cfg.AdminTokens)
+}
+
+func TestLoadListenAddresses(t *testing.T) {
+	t.Setenv("GRPC_LISTEN_ADDRESSES", "127.0.0.1:50051,unix:/tmp/grpc.sock,50052")
+
+	_, err := Load("")
+	assert.ErrorContains(t, err, `GRPC_LISTEN_ADDRESSES: "50052": want host:port or unix:/path`)
+
+	t.Setenv("GRPC_LISTEN_ADDRESSES", "127.0.0.1:50051, unix:/tmp/grpc.sock")
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.1:50051", "unix:/tmp/grpc.sock"}, cfg.GRPCListenAddresses)
+}
+
+func TestLoadValidatesGRPCTuning(t *testing.T) {
+	t.Setenv("GRPC_MAX_SEND_MSG_BYTES", "0")
+	t.Setenv("GRPC_MAX_CONCURRENT_STREAMS", "-1")
+	t.Setenv("GRPC_MAX_CONNECTION_AGE", "-1m")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GRPC_MAX_SEND_MSG_BYTES: must be positive")
+	assert.Contains(t, err.Error(), "GRPC_MAX_CONCURRENT_STREAMS: must be between 0 (unlimited) and 4294967295")
+	assert.Contains(t, err.Error(), "GRPC_MAX_CONNECTION_AGE: must not be negative")
+}
+
+func TestAccessLogSampleRates(t *testing.T) {
+	cfg := Default()
+	cfg.AccessLogMethodRates = []string{"GetOverallQualityScore=0.1", " ExportScores = 1 "}
+
+	rates, err := cfg.AccessLogSampleRates()
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"GetOverallQualityScore": 0.1, "ExportScores": 1}, rates)
+
+	for _, item := range []string{"ExportScores", "ExportScores=often", "ExportScores=1.5"} {
+		cfg.AccessLogMethodRates = []string{item}
+		_, err := cfg.AccessLogSampleRates()
+		assert.Error(t, err, item)
+	}
+}
+
+func TestLoadValidatesConcurrencyLimit(t *testing.T) {
+	t.Setenv("CONCURRENCY_LIMIT", "500")
+
+	_, err := Load("")
+	assert.ErrorContains(t, err, "CONCURRENCY_LIMIT: must be between CONCURRENCY_LIMIT_MIN and CONCURRENCY_LIMIT_MAX")
+
+	t.Setenv("CONCURRENCY_LIMIT_ENABLED", "false")
+	_, err = Load("")
+	assert.NoError(t, err, "limiter settings are ignored while it is disabled")
+}
+
+func TestLoadValidatesMemoryCacheSize(t *testing.T) {
+	t.Setenv("CACHE_MEMORY_MAX_BYTES", "0")
+
+	_, err := Load("")
+	assert.ErrorContains(t, err, "CACHE_MEMORY_MAX_BYTES: must be positive", "the fallback needs a size")
+
+	t.Setenv("CACHE_BACKEND", "memory")
+	_, err = Load("")
+	assert.ErrorContains(t, err, "CACHE_MEMORY_MAX_BYTES: must be positive")
+
+	t.Setenv("CACHE_BACKEND", "redis")
+	t.Setenv("CACHE_FALLBACK_ENABLED", "false")
+	_, err = Load("")
+	assert.NoError(t, err, "the size is ignored when the in-memory cache is not used")
+}
+
+func TestLoadValidatesAdminTokens(t *testing.T) {
+	t.Setenv("ADMIN_ENABLED", "true")
+	t.Setenv("ADMIN_TOKENS", "ops:secret,:anonymous,nobody:")
+
+	_, err := Load("")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ADMIN_TOKENS: entry 2: want name:token with neither empty")
+	assert.Contains(t, err.Error(), "ADMIN_TOKENS: entry 3: want name:token with neither empty")
+	assert.NotContains(t, err.Error(), "entry 1")
+	assert.NotContains(t, err.Error(), "anonymous", "tokens are not echoed")
+}
@@ -0,0 +1,243 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/godilite/qa-server/internal/service"
+	"github.com/parquet-go/parquet-go"
+)
+
+// Format is the file encoding of an export.
+type Format int
+
+const (
+	FormatCSV Format = iota
+	FormatParquet
+)
+
+// parquetRowGroupSize bounds how many rows are buffered before a Parquet row group
+// is flushed to the underlying writer.
+const parquetRowGroupSize = 10_000
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	if f == FormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension returns the file extension of the format, without the dot.
+func (f Format) Extension() string {
+	if f == FormatParquet {
+		return "parquet"
+	}
+	return "csv"
+}
+
+// CategoryScoreRow is one row of the aggregated category scores export: a single
+// category in a single daily (YYYY-MM-DD) or weekly (YYYY-Www) period. The category
+// totals are repeated on every period row of that category.
+type CategoryScoreRow struct {
+	Category             string  `parquet:"category"`
+	TotalRatings         int64   `parquet:"total_ratings"`
+	OverallCategoryScore float64 `parquet:"overall_category_score"`
+	Period               string  `parquet:"period"`
+	PeriodScore          float64 `parquet:"period_score"`
+}
+
+// TicketScoreRow is one row of the scores by ticket export: a single category score of a single ticket.
+type TicketScoreRow struct {
+	TicketID int64   `parquet:"ticket_id"`
+	Category string  `parquet:"category"`
+	Score    float64 `parquet:"score"`
+}
+
+var (
+	categoryScoreHeader = []string{"category", "total_ratings", "overall_category_score", "period", "period_score"}
+	ticketScoreHeader   = []string{"ticket_id", "category", "score"}
+)
+
+// WriteCategoryScores writes one row per category and period, ordered by category then period.
+func WriteCategoryScores(w io.Writer, format Format, scores []service.AggregatedCategoryScores) error {
+	rw, err := newRowWriter(w, format, categoryScoreHeader, func(r CategoryScoreRow) []string {
+		return []string{
+			r.Category,
+			strconv.FormatInt(r.TotalRatings, 10),
+			formatFloat(r.OverallCategoryScore),
+			r.Period,
+			formatFloat(r.PeriodScore),
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sort an index rather than the input, which may be shared with the cache.
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return scores[order[i]].CategoryName < scores[order[j]].CategoryName
+	})
+
+	for _, i := range order {
+		cat := scores[i]
+		for _, p := range cat.PeriodScores {
+			if err := rw.Write(CategoryScoreRow{
+				Category:             cat.CategoryName,
+				TotalRatings:         int64(cat.TotalRatings),
+				OverallCategoryScore: cat.OverallCategoryScore,
+				Period:               p.Period,
+				PeriodScore:          p.Score,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return rw.Close()
+}
+
+// TicketScoreWriter encodes scores by ticket rows one at a time, for callers that stream
+// them already ordered by ticket ID then category. Close must be called to complete the output.
+type TicketScoreWriter struct {
+	rw rowWriter[TicketScoreRow]
+}
+
+// NewTicketScoreWriter starts a scores by ticket export on w. For CSV the header is buffered
+// until the first Write or Close.
+func NewTicketScoreWriter(w io.Writer, format Format) (*TicketScoreWriter, error) {
+	rw, err := newRowWriter(w, format, ticketScoreHeader, func(r TicketScoreRow) []string {
+		return []string{
+			strconv.FormatInt(r.TicketID, 10),
+			r.Category,
+			formatFloat(r.Score),
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TicketScoreWriter{rw: rw}, nil
+}
+
+// Write encodes row.
+func (t *TicketScoreWriter) Write(row TicketScoreRow) error {
+	return t.rw.Write(row)
+}
+
+// Close flushes buffered rows and, for Parquet, writes the footer. It does not close the
+// underlying writer.
+func (t *TicketScoreWriter) Close() error {
+	return t.rw.Close()
+}
+
+// WriteTicketScores writes one row per ticket and category, ordered by ticket ID then category.
+func WriteTicketScores(w io.Writer, format Format, scores []service.TicketScores) error {
+	rw, err := NewTicketScoreWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return scores[order[i]].TicketID < scores[order[j]].TicketID
+	})
+
+	categories := make([]string, 0, 8)
+	for _, i := range order {
+		ticket := scores[i]
+
+		categories = categories[:0]
+		for c := range ticket.CategoryScores {
+			categories = append(categories, c)
+		}
+		sort.Strings(categories)
+
+		for _, c := range categories {
+			if err := rw.Write(TicketScoreRow{
+				TicketID: ticket.TicketID,
+				Category: c,
+				Score:    ticket.CategoryScores[c],
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return rw.Close()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+type rowWriter[T any] interface {
+	Write(row T) error
+	Close() error
+}
+
+func newRowWriter[T any](w io.Writer, format Format, header []string, record func(T) []string) (rowWriter[T], error) {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return nil, fmt.Errorf("write csv header: %w", err)
+		}
+		return &csvRowWriter[T]{w: cw, record: record}, nil
+	case FormatParquet:
+		return &parquetRowWriter[T]{w: parquet.NewGenericWriter[T](w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %d", format)
+	}
+}
+
+// csvRowWriter relies on csv.Writer's internal buffer, which is flushed to the
+// underlying writer as it fills, so rows are streamed rather than accumulated.
+type csvRowWriter[T any] struct {
+	w      *csv.Writer
+	record func(T) []string
+}
+
+func (c *csvRowWriter[T]) Write(row T) error {
+	return c.w.Write(c.record(row))
+}
+
+func (c *csvRowWriter[T]) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// parquetRowWriter flushes a row group every parquetRowGroupSize rows so that at most
+// one row group is held in memory.
+type parquetRowWriter[T any] struct {
+	w       *parquet.GenericWriter[T]
+	pending int
+}
+
+func (p *parquetRowWriter[T]) Write(row T) error {
+	if _, err := p.w.Write([]T{row}); err != nil {
+		return fmt.Errorf("write parquet row: %w", err)
+	}
+	p.pending++
+	if p.pending >= parquetRowGroupSize {
+		p.pending = 0
+		if err := p.w.Flush(); err != nil {
+			return fmt.Errorf("flush parquet row group: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *parquetRowWriter[T]) Close() error {
+	if err := p.w.Close(); err != nil {
+		return fmt.Errorf("close parquet writer: %w", err)
+	}
+	return nil
+}
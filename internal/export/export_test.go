@@ -0,0 +1,107 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/godilite/qa-server/internal/service"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testCategoryScores = []service.AggregatedCategoryScores{
+		{
+			CategoryName:         "Tone",
+			TotalRatings:         3,
+			OverallCategoryScore: 80,
+			PeriodScores: []service.PeriodScore{
+				{Period: "2025-01-01", Score: 70},
+				{Period: "2025-01-02", Score: 90},
+			},
+		},
+		{
+			CategoryName:         "Grammar",
+			TotalRatings:         1,
+			OverallCategoryScore: 62.5,
+			PeriodScores: []service.PeriodScore{
+				{Period: "2025-01-01", Score: 62.5},
+			},
+		},
+	}
+
+	testTicketScores = []service.TicketScores{
+		{TicketID: 102, CategoryScores: map[string]float64{"Tone": 60}},
+		{TicketID: 101, CategoryScores: map[string]float64{"Tone": 80, "Grammar": 100}},
+	}
+)
+
+func TestWriteCategoryScoresCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCategoryScores(&buf, FormatCSV, testCategoryScores))
+
+	expected := "category,total_ratings,overall_category_score,period,period_score\n" +
+		"Grammar,1,62.5,2025-01-01,62.5\n" +
+		"Tone,3,80,2025-01-01,70\n" +
+		"Tone,3,80,2025-01-02,90\n"
+	assert.Equal(t, expected, buf.String())
+
+	// The input order must not be changed, it may be shared with the cache.
+	assert.Equal(t, "Tone", testCategoryScores[0].CategoryName)
+}
+
+func TestWriteTicketScoresCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteTicketScores(&buf, FormatCSV, testTicketScores))
+
+	expected := "ticket_id,category,score\n" +
+		"101,Grammar,100\n" +
+		"101,Tone,80\n" +
+		"102,Tone,60\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteEmptyCSVHasHeader(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteTicketScores(&buf, FormatCSV, nil))
+
+	assert.Equal(t, "ticket_id,category,score\n", buf.String())
+}
+
+func TestWriteTicketScoresParquet(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteTicketScores(&buf, FormatParquet, testTicketScores))
+
+	rows, err := parquet.Read[TicketScoreRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	assert.Equal(t, []TicketScoreRow{
+		{TicketID: 101, Category: "Grammar", Score: 100},
+		{TicketID: 101, Category: "Tone", Score: 80},
+		{TicketID: 102, Category: "Tone", Score: 60},
+	}, rows)
+}
+
+func TestWriteCategoryScoresParquetAcrossRowGroups(t *testing.T) {
+	periods := make([]service.PeriodScore, parquetRowGroupSize+5)
+	for i := range periods {
+		periods[i] = service.PeriodScore{Period: "p", Score: float64(i)}
+	}
+	scores := []service.AggregatedCategoryScores{{CategoryName: "Tone", TotalRatings: 1, PeriodScores: periods}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCategoryScores(&buf, FormatParquet, scores))
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(periods)), f.NumRows())
+	assert.Len(t, f.RowGroups(), 2)
+}
+
+func TestFormatMetadata(t *testing.T) {
+	assert.Equal(t, "csv", FormatCSV.Extension())
+	assert.Equal(t, "parquet", FormatParquet.Extension())
+	assert.Equal(t, "text/csv; charset=utf-8", FormatCSV.ContentType())
+	assert.Equal(t, "application/vnd.apache.parquet", FormatParquet.ContentType())
+}
@@ -0,0 +1,176 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/internal/export"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const exportPath = "/v1/export"
+
+var reportTypes = map[string]pb.ReportType{
+	"categories":                 pb.ReportType_REPORT_TYPE_AGGREGATED_CATEGORY_SCORES,
+	"aggregated_category_scores": pb.ReportType_REPORT_TYPE_AGGREGATED_CATEGORY_SCORES,
+	"tickets":                    pb.ReportType_REPORT_TYPE_SCORES_BY_TICKET,
+	"scores_by_ticket":           pb.ReportType_REPORT_TYPE_SCORES_BY_TICKET,
+}
+
+var exportFormats = map[string]pb.ExportFormat{
+	"":        pb.ExportFormat_EXPORT_FORMAT_CSV,
+	"csv":     pb.ExportFormat_EXPORT_FORMAT_CSV,
+	"parquet": pb.ExportFormat_EXPORT_FORMAT_PARQUET,
+}
+
+// exportHandler serves GET /v1/export?start=...&end=...&report=categories|tickets&format=csv|parquet
+// as a file download of the ExportScores stream. It is registered by hand rather than
+// generated because grpc-gateway frames streamed responses as delimited JSON, which would
+// corrupt CSV and Parquet output.
+func exportHandler(mux *runtime.ServeMux, client pb.TicketScoringClient, logger *zap.Logger) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		req, err := parseExportRequest(r.URL.Query())
+		if err != nil {
+			writeError(mux, w, r, err)
+			return
+		}
+
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, pb.TicketScoring_ExportScores_FullMethodName)
+		if err != nil {
+			writeError(mux, w, r, err)
+			return
+		}
+
+		dw := &downloadWriter{w: w, req: req}
+		if err := copyExport(ctx, client, req, dw); err != nil {
+			if !dw.started {
+				writeError(mux, w, r, err)
+				return
+			}
+			// Headers and part of the body are already sent; abort the connection so the
+			// client sees a truncated transfer instead of a silently short file.
+			logger.Warn("export aborted mid-stream", zap.Error(err))
+			panic(http.ErrAbortHandler)
+		}
+		if !dw.started {
+			dw.writeHeader()
+		}
+	}
+}
+
+// copyExport writes the chunks of an ExportScores stream to w, returning the stream's
+// status error if it fails.
+func copyExport(ctx context.Context, client pb.TicketScoringClient, req *pb.ExportScoresRequest, w io.Writer) error {
+	stream, err := client.ExportScores(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.GetData()); err != nil {
+			return err
+		}
+	}
+}
+
+func parseExportRequest(q url.Values) (*pb.ExportScoresRequest, error) {
+	start, err := parseTime(q, "start", "start_date")
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseTime(q, "end", "end_date")
+	if err != nil {
+		return nil, err
+	}
+
+	report, ok := reportTypes[strings.ToLower(q.Get("report"))]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "report must be one of categories, tickets; got %q", q.Get("report"))
+	}
+	format, ok := exportFormats[strings.ToLower(q.Get("format"))]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "format must be one of csv, parquet; got %q", q.Get("format"))
+	}
+
+	return &pb.ExportScoresRequest{
+		StartDate:  start,
+		EndDate:    end,
+		ReportType: report,
+		Format:     format,
+	}, nil
+}
+
+func parseTime(q url.Values, names ...string) (*timestamppb.Timestamp, error) {
+	for _, name := range names {
+		v := q.Get(name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, normalizeDate(v))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s %q: expected RFC 3339 or YYYY-MM-DD", name, v)
+		}
+		return timestamppb.New(t), nil
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "%s is required", names[0])
+}
+
+func writeError(mux *runtime.ServeMux, w http.ResponseWriter, r *http.Request, err error) {
+	_, outbound := runtime.MarshalerForRequest(mux, r)
+	runtime.HTTPError(r.Context(), mux, outbound, w, r, err)
+}
+
+// downloadWriter defers the response headers until the first byte of the export, so
+// that validation and fetch errors can still be returned with a proper status code.
+type downloadWriter struct {
+	w       http.ResponseWriter
+	req     *pb.ExportScoresRequest
+	started bool
+}
+
+func (d *downloadWriter) writeHeader() {
+	d.started = true
+
+	format := export.FormatCSV
+	if d.req.GetFormat() == pb.ExportFormat_EXPORT_FORMAT_PARQUET {
+		format = export.FormatParquet
+	}
+
+	report := "scores_by_ticket"
+	if d.req.GetReportType() == pb.ReportType_REPORT_TYPE_AGGREGATED_CATEGORY_SCORES {
+		report = "aggregated_category_scores"
+	}
+
+	filename := fmt.Sprintf("%s_%s_%s.%s", report,
+		d.req.GetStartDate().AsTime().Format(time.DateOnly),
+		d.req.GetEndDate().AsTime().Format(time.DateOnly),
+		format.Extension())
+
+	d.w.Header().Set("Content-Type", format.ContentType())
+	d.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	d.w.WriteHeader(http.StatusOK)
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	if !d.started {
+		d.writeHeader()
+	}
+	return d.w.Write(p)
+}
@@ -0,0 +1,218 @@
+package gateway
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	openAPIPath = "/v1/openapi.json"
+	// requestIDHeader is forwarded both ways, so HTTP callers can correlate a response
+	// with the gRPC access log and traces as gRPC callers do.
+	requestIDHeader = "X-Request-Id"
+)
+
+type Option func(*Options)
+
+type Options struct {
+	port   int
+	logger *zap.Logger
+}
+
+func WithPort(port int) Option {
+	return func(o *Options) {
+		o.port = port
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *Options) {
+		o.logger = logger
+	}
+}
+
+// Server serves the TicketScoring RPCs as an HTTP/JSON API.
+type Server struct {
+	httpServer *http.Server
+	lis        net.Listener
+	logger     *zap.Logger
+}
+
+// New creates an HTTP/JSON gateway that calls the TicketScoring service over conn, usually
+// an in-process connection to the application's own gRPC server, so HTTP requests pass
+// through the same interceptors (request IDs, recovery, access log, metrics and load
+// shedding) as gRPC ones. gRPC status codes are translated to HTTP statuses by
+// grpc-gateway (InvalidArgument→400, NotFound→404, Unavailable→503, ...).
+func New(ctx context.Context, conn *grpc.ClientConn, opts ...Option) (*Server, error) {
+	options := &Options{
+		port:   8080,
+		logger: zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if conn == nil {
+		return nil, fmt.Errorf("nil client connection provided to gateway")
+	}
+	if options.port < 0 || options.port > 65535 {
+		return nil, fmt.Errorf("invalid port %d: must be between 0 and 65535", options.port)
+	}
+
+	logger := options.logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	mux := runtime.NewServeMux(
+		runtime.SetQueryParameterParser(&queryParser{}),
+		runtime.WithIncomingHeaderMatcher(incomingHeader),
+		runtime.WithMetadata(remoteAddr),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeader),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+	)
+
+	if err := pb.RegisterTicketScoringHandler(ctx, mux, conn); err != nil {
+		return nil, fmt.Errorf("failed to register gateway handlers: %w", err)
+	}
+
+	spec, err := openAPISpec()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI document: %w", err)
+	}
+	if err := mux.HandlePath(http.MethodGet, openAPIPath, serveOpenAPI(spec)); err != nil {
+		return nil, fmt.Errorf("failed to register OpenAPI handler: %w", err)
+	}
+
+	if err := mux.HandlePath(http.MethodGet, exportPath, exportHandler(mux, pb.NewTicketScoringClient(conn), logger)); err != nil {
+		return nil, fmt.Errorf("failed to register export handler: %w", err)
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", options.port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on port %d: %w", options.port, err)
+	}
+
+	return &Server{
+		httpServer: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		lis:    lis,
+		logger: logger.Named("http-gateway"),
+	}, nil
+}
+
+// incomingHeader forwards X-Request-Id as gRPC metadata in addition to the headers
+// grpc-gateway forwards by default. A client cannot set the remote address metadata itself
+// through a Grpc-Metadata- header.
+func incomingHeader(key string) (string, bool) {
+	if strings.EqualFold(key, requestIDHeader) {
+		return strings.ToLower(requestIDHeader), true
+	}
+	h, ok := runtime.DefaultHeaderMatcher(key)
+	if ok && strings.EqualFold(h, grpcsrv.RemoteAddrHeader) {
+		return "", false
+	}
+	return h, ok
+}
+
+// remoteAddr passes the HTTP client's address to the gRPC server, whose peer is the bufconn
+// listener for gateway calls. grpc-gateway already forwards X-Forwarded-For with the
+// client's IP appended.
+func remoteAddr(_ context.Context, r *http.Request) metadata.MD {
+	if r.RemoteAddr == "" {
+		return nil
+	}
+	return metadata.Pairs(grpcsrv.RemoteAddrHeader, r.RemoteAddr)
+}
+
+// outgoingHeader returns the request ID response header as X-Request-Id, and other
+// response metadata with grpc-gateway's Grpc-Metadata- prefix.
+func outgoingHeader(key string) (string, bool) {
+	if strings.EqualFold(key, requestIDHeader) {
+		return requestIDHeader, true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// exportOpenAPI describes GET /v1/export, which protoc-gen-openapiv2 cannot generate because
+// the path is registered by hand.
+//
+//go:embed export_openapi.json
+var exportOpenAPI []byte
+
+// openAPISpec returns the generated OpenAPI document with the export path added.
+func openAPISpec() ([]byte, error) {
+	var spec map[string]any
+	if err := json.Unmarshal(pb.OpenAPISpec, &spec); err != nil {
+		return nil, err
+	}
+	paths, ok := spec["paths"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("generated document has no paths")
+	}
+	var export map[string]any
+	if err := json.Unmarshal(exportOpenAPI, &export); err != nil {
+		return nil, err
+	}
+	paths[exportPath] = export
+	return json.MarshalIndent(spec, "", "  ")
+}
+
+func serveOpenAPI(spec []byte) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(spec)
+	}
+}
+
+// Start runs the server in a goroutine and returns immediately.
+func (s *Server) Start() {
+	s.logger.Info("HTTP gateway starting", zap.String("addr", s.lis.Addr().String()))
+
+	go func() {
+		if err := s.httpServer.Serve(s.lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("HTTP gateway failed", zap.Error(err))
+		}
+	}()
+}
+
+// Shutdown gracefully shuts down the server with a timeout context.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("HTTP gateway shutting down")
+	// The listener of a server that was never started is still open.
+	defer func() {
+		_ = s.lis.Close()
+	}()
+	return s.httpServer.Shutdown(ctx)
+}
+
+// Addr returns the server's listening address.
+func (s *Server) Addr() net.Addr {
+	return s.lis.Addr()
+}
@@ -0,0 +1,258 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	handler "github.com/godilite/qa-server/internal/grpc"
+	"github.com/godilite/qa-server/internal/grpc/mocks"
+	"github.com/godilite/qa-server/internal/service"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// startGateway serves scoring through a gRPC server on an in-memory listener and a
+// gateway connected to it, as the application does.
+func startGateway(t *testing.T, scoring *mocks.MockScoringService, opts ...grpcsrv.Option) string {
+	t.Helper()
+
+	handlers := handler.NewGRPCHandlers(scoring, &mocks.MockCacher{}, zap.NewNop(), time.Minute)
+	grpcServer, err := grpcsrv.New(append([]grpcsrv.Option{grpcsrv.WithBufconn(1 << 20)}, opts...)...)
+	require.NoError(t, err)
+	grpcServer.RegisterService(func(s *grpc.Server) {
+		pb.RegisterTicketScoringServer(s, handlers)
+	})
+	grpcServer.Start()
+	t.Cleanup(func() {
+		_ = grpcServer.Shutdown(context.Background())
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(grpcServer.BufconnDialer()),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	server, err := New(context.Background(), conn, WithPort(0))
+	require.NoError(t, err)
+	server.Start()
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	return "http://" + server.Addr().String()
+}
+
+func get(t *testing.T, url string) (int, map[string]any) {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(body, &out), string(body))
+	return resp.StatusCode, out
+}
+
+func TestGatewayOverallScore(t *testing.T) {
+	var gotStart, gotEnd time.Time
+	base := startGateway(t, &mocks.MockScoringService{
+		GetOverallScoreFunc: func(ctx context.Context, start, end time.Time) (float64, error) {
+			gotStart, gotEnd = start, end
+			return 85.5, nil
+		},
+	})
+
+	t.Run("short aliases and plain dates", func(t *testing.T) {
+		code, body := get(t, base+"/v1/scores/overall?start=2019-01-01&end=2019-12-31")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 85.5, body["score"])
+		assert.Equal(t, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), gotStart.UTC())
+		assert.Equal(t, time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), gotEnd.UTC())
+	})
+
+	t.Run("field names and RFC 3339", func(t *testing.T) {
+		code, body := get(t, base+"/v1/scores/overall?start_date=2019-02-01T00:00:00Z&end_date=2019-02-28T00:00:00Z")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 85.5, body["score"])
+	})
+
+	t.Run("invalid range maps to 400", func(t *testing.T) {
+		code, body := get(t, base+"/v1/scores/overall?start=2019-12-31&end=2019-01-01")
+
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, body["message"], "end date must be after start date")
+	})
+
+	t.Run("malformed date maps to 400", func(t *testing.T) {
+		code, _ := get(t, base+"/v1/scores/overall?start=yesterday&end=2019-01-01")
+
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestGatewayNotFound(t *testing.T) {
+	base := startGateway(t, &mocks.MockScoringService{
+		GetAggregatedCategoryScoresFunc: func(ctx context.Context, start, end time.Time) ([]service.AggregatedCategoryScores, error) {
+			return nil, service.ErrNoRatings
+		},
+	})
+
+	code, body := get(t, base+"/v1/scores/categories?start=2030-01-01&end=2030-01-31")
+
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Contains(t, body["message"], "no ratings found")
+}
+
+func TestGatewayScoresByTicket(t *testing.T) {
+	base := startGateway(t, &mocks.MockScoringService{
+		GetScoresByTicketFunc: func(ctx context.Context, start, end time.Time) ([]service.TicketScores, error) {
+			return []service.TicketScores{
+				{TicketID: 101, CategoryScores: map[string]float64{"Tone": 80}},
+			}, nil
+		},
+	})
+
+	code, body := get(t, base+"/v1/scores/tickets?start=2019-01-01&end=2019-01-31")
+
+	require.Equal(t, http.StatusOK, code)
+	tickets := body["ticket_scores"].([]any)
+	require.Len(t, tickets, 1)
+	assert.Equal(t, "101", tickets[0].(map[string]any)["ticket_id"])
+}
+
+func TestGatewayOpenAPI(t *testing.T) {
+	base := startGateway(t, &mocks.MockScoringService{})
+
+	code, body := get(t, base+openAPIPath)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "2.0", body["swagger"])
+	assert.Contains(t, body["paths"], "/v1/scores/overall")
+	require.Contains(t, body["paths"], exportPath)
+	export := body["paths"].(map[string]any)[exportPath].(map[string]any)
+	assert.Equal(t, "TicketScoring_ExportScores", export["get"].(map[string]any)["operationId"])
+}
+
+func TestGatewayExport(t *testing.T) {
+	base := startGateway(t, &mocks.MockScoringService{
+		EachScoreByTicketFunc: func(ctx context.Context, start, end time.Time, fn func(service.TicketCategoryScore) error) error {
+			return fn(service.TicketCategoryScore{TicketID: 101, Category: "Tone", Score: 80})
+		},
+	})
+
+	t.Run("csv download", func(t *testing.T) {
+		resp, err := http.Get(base + "/v1/export?start=2019-01-01&end=2019-01-31&report=tickets")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), "scores_by_ticket_2019-01-01_2019-01-31.csv")
+		assert.Equal(t, "ticket_id,category,score\n101,Tone,80\n", string(body))
+	})
+
+	t.Run("unknown report maps to 400", func(t *testing.T) {
+		code, body := get(t, base+"/v1/export?start=2019-01-01&end=2019-01-31&report=overall")
+
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, body["message"], "report must be one of")
+	})
+
+	t.Run("missing start maps to 400", func(t *testing.T) {
+		code, body := get(t, base+"/v1/export?end=2019-01-31&report=tickets")
+
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, body["message"], "start is required")
+	})
+}
+
+func TestGatewayUsesServerInterceptors(t *testing.T) {
+	base := startGateway(t, &mocks.MockScoringService{
+		GetOverallScoreFunc: func(ctx context.Context, start, end time.Time) (float64, error) {
+			if start.Year() == 2020 {
+				panic("boom")
+			}
+			return 85.5, nil
+		},
+	})
+
+	t.Run("request ID is forwarded and echoed", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, base+"/v1/scores/overall?start=2019-01-01&end=2019-12-31", nil)
+		require.NoError(t, err)
+		req.Header.Set(requestIDHeader, "req-123")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "req-123", resp.Header.Get(requestIDHeader))
+	})
+
+	t.Run("request ID is generated when missing", func(t *testing.T) {
+		resp, err := http.Get(base + "/v1/scores/overall?start=2019-01-01&end=2019-12-31")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.NotEmpty(t, resp.Header.Get(requestIDHeader))
+	})
+
+	t.Run("handler panic is recovered as 500", func(t *testing.T) {
+		code, _ := get(t, base+"/v1/scores/overall?start=2020-01-01&end=2020-12-31")
+
+		assert.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestGatewayAccessLogClientAddress(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	base := startGateway(t, &mocks.MockScoringService{
+		GetOverallScoreFunc: func(ctx context.Context, start, end time.Time) (float64, error) {
+			return 85.5, nil
+		},
+	}, grpcsrv.WithAccessLog(zap.New(core)))
+
+	req, err := http.NewRequest(http.MethodGet, base+"/v1/scores/overall?start=2019-01-01&end=2019-12-31", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	req.Header.Set("User-Agent", "curl/8.5")
+	req.Header.Set("Grpc-Metadata-X-Remote-Addr", "198.51.100.1:1")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	entries := logs.FilterMessage("gRPC access").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	peer, _ := fields["peer"].(string)
+	host, _, err := net.SplitHostPort(peer)
+	require.NoError(t, err, "peer %q is not the client's address", peer)
+	assert.True(t, net.ParseIP(host).IsLoopback(), "peer %q is not the client's address", peer)
+	assert.Equal(t, "203.0.113.9, "+host, fields["forwarded_for"])
+	assert.Equal(t, "curl/8.5", fields["user_agent"])
+}
@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"google.golang.org/protobuf/proto"
+)
+
+// queryAliases maps the short query parameter names accepted by the HTTP API onto
+// the TimePeriodRequest field names, so ?start=...&end=... works alongside ?start_date=....
+var queryAliases = map[string]string{
+	"start": "start_date",
+	"end":   "end_date",
+}
+
+var timestampParams = map[string]bool{
+	"start_date": true,
+	"end_date":   true,
+	"startDate":  true,
+	"endDate":    true,
+}
+
+// queryParser resolves parameter aliases and accepts plain YYYY-MM-DD dates for timestamp
+// fields before delegating to the default grpc-gateway parser.
+type queryParser struct {
+	runtime.DefaultQueryParser
+}
+
+func (p *queryParser) Parse(msg proto.Message, values url.Values, filter *utilities.DoubleArray) error {
+	normalized := make(url.Values, len(values))
+	for key, vals := range values {
+		if alias, ok := queryAliases[key]; ok {
+			key = alias
+		}
+		for _, v := range vals {
+			if timestampParams[key] {
+				v = normalizeDate(v)
+			}
+			normalized.Add(key, v)
+		}
+	}
+	return p.DefaultQueryParser.Parse(msg, normalized, filter)
+}
+
+// normalizeDate expands a date-only value to midnight UTC in RFC 3339 form. Any other
+// value is returned unchanged and left to the default parser to validate.
+func normalizeDate(v string) string {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t.Format(time.RFC3339)
+	}
+	return v
+}
@@ -0,0 +1,215 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdminHandlers implements the Admin service.
+type AdminHandlers struct {
+	pb.UnimplementedAdminServer
+	cache  Cacher
+	logger *zap.Logger
+
+	config   func() map[string]string
+	dbStats  func() sql.DBStats
+	logLevel *zap.AtomicLevel
+	rebuild  func(context.Context) (int64, error)
+}
+
+// AdminOption configures the sources the Admin service reports on. RPCs whose source is
+// not configured return Unimplemented.
+type AdminOption func(*AdminHandlers)
+
+// WithConfigSource sets the func returning the effective, redacted configuration.
+func WithConfigSource(config func() map[string]string) AdminOption {
+	return func(a *AdminHandlers) {
+		a.config = config
+	}
+}
+
+// WithDBStats sets the func returning the connection pool statistics, typically
+// (*sql.DB).Stats.
+func WithDBStats(stats func() sql.DBStats) AdminOption {
+	return func(a *AdminHandlers) {
+		a.dbStats = stats
+	}
+}
+
+// WithAdminLogLevel sets the level that SetLogLevel changes.
+func WithAdminLogLevel(level zap.AtomicLevel) AdminOption {
+	return func(a *AdminHandlers) {
+		a.logLevel = &level
+	}
+}
+
+// WithRollupRebuild sets the func refolding the daily rollups, typically
+// (*repository.RollupBuilder).Rebuild.
+func WithRollupRebuild(rebuild func(context.Context) (int64, error)) AdminOption {
+	return func(a *AdminHandlers) {
+		a.rebuild = rebuild
+	}
+}
+
+// NewAdminHandlers initializes the Admin service handlers.
+func NewAdminHandlers(cache Cacher, logger *zap.Logger, opts ...AdminOption) *AdminHandlers {
+	if cache == nil {
+		panic("nil Cacher provided to NewAdminHandlers")
+	}
+	a := &AdminHandlers{
+		cache:  cache,
+		logger: logger.Named("admin-handler"),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// InvalidateCache deletes cached reports by key prefix and/or date range.
+//
+// A period-over-period change also depends on the window before its own, so when a date
+// range is given every change key whose window ends on or after the start of the range is
+// deleted too, regardless of the end of the range.
+func (a *AdminHandlers) InvalidateCache(ctx context.Context, req *pb.InvalidateCacheRequest) (*pb.InvalidateCacheResponse, error) {
+	var start, end time.Time
+	if req.GetStartDate() != nil {
+		start = req.GetStartDate().AsTime()
+	}
+	if req.GetEndDate() != nil {
+		end = req.GetEndDate().AsTime()
+	}
+	prefixes := req.GetKeyPrefixes()
+
+	if len(prefixes) == 0 && start.IsZero() && end.IsZero() {
+		return nil, status.Error(codes.InvalidArgument, "a key prefix or date is required")
+	}
+	for _, p := range prefixes {
+		if p == "" {
+			return nil, status.Error(codes.InvalidArgument, "key prefixes must not be empty")
+		}
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return nil, status.Error(codes.InvalidArgument, "end date must be after start date")
+	}
+
+	ctx, span := tracer.Start(ctx, "AdminHandlers.InvalidateCache", trace.WithAttributes(
+		attribute.StringSlice("cache.key_prefixes", prefixes),
+		attribute.String("window.start", formatOptionalTime(start)),
+		attribute.String("window.end", formatOptionalTime(end)),
+	))
+	defer span.End()
+
+	ctx, cancel := withDefaultDeadline(ctx, defaultGRPCTimeout)
+	defer cancel()
+
+	deleted, err := a.cache.Invalidate(ctx, prefixes, start, end)
+	if err != nil {
+		return nil, a.invalidateError(ctx, span, err)
+	}
+
+	if changePrefixes := periodChangePrefixes(prefixes); !start.IsZero() && !end.IsZero() && len(changePrefixes) > 0 {
+		n, err := a.cache.Invalidate(ctx, changePrefixes, start, time.Time{})
+		if err != nil {
+			return nil, a.invalidateError(ctx, span, err)
+		}
+		deleted += n
+	}
+
+	span.SetAttributes(attribute.Int64("cache.deleted_keys", deleted))
+	grpcsrv.RequestLogger(ctx, a.logger).Info("cache invalidated",
+		zap.Strings("prefixes", prefixes),
+		zap.Time("start", start),
+		zap.Time("end", end),
+		zap.Int64("deleted", deleted))
+
+	return &pb.InvalidateCacheResponse{DeletedKeys: deleted}, nil
+}
+
+// rollupPrefixes are the cached reports computed from the daily rollups.
+var rollupPrefixes = []string{
+	string(cacheKeyOverallScore),
+	string(cacheKeyPeriodChange),
+	string(cacheKeyAggregatedCategory),
+}
+
+// RebuildRollups refolds the daily rollups from every rating, then deletes the cached
+// reports computed from the old ones. The rebuild is bounded only by the caller's deadline,
+// since it reads the whole ratings table.
+func (a *AdminHandlers) RebuildRollups(ctx context.Context, _ *pb.RebuildRollupsRequest) (*pb.RebuildRollupsResponse, error) {
+	if a.rebuild == nil {
+		return nil, status.Error(codes.Unimplemented, "rollups are not enabled")
+	}
+
+	ctx, span := tracer.Start(ctx, "AdminHandlers.RebuildRollups")
+	defer span.End()
+
+	logger := grpcsrv.RequestLogger(ctx, a.logger)
+	start := time.Now()
+	ratings, err := a.rebuild(ctx)
+	if err != nil {
+		recordSpanError(span, err)
+		logger.Error("rollup rebuild failed", zap.Error(err))
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return nil, status.Error(codes.Internal, "rollup rebuild failed")
+	}
+
+	invalidateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultGRPCTimeout)
+	defer cancel()
+	deleted, err := a.cache.Invalidate(invalidateCtx, rollupPrefixes, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, a.invalidateError(ctx, span, err)
+	}
+
+	span.SetAttributes(attribute.Int64("rollups.ratings", ratings), attribute.Int64("cache.deleted_keys", deleted))
+	logger.Info("rollups rebuilt",
+		zap.Int64("ratings", ratings),
+		zap.Int64("deleted", deleted),
+		zap.Duration("took", time.Since(start)))
+
+	return &pb.RebuildRollupsResponse{Ratings: ratings, DeletedKeys: deleted}, nil
+}
+
+func (a *AdminHandlers) invalidateError(ctx context.Context, span trace.Span, err error) error {
+	recordSpanError(span, err)
+	grpcsrv.RequestLogger(ctx, a.logger).Error("cache invalidation failed", zap.Error(err))
+	return status.Error(codes.Unavailable, "cache invalidation failed")
+}
+
+// periodChangePrefixes narrows prefixes to those that select period-over-period change keys.
+func periodChangePrefixes(prefixes []string) []string {
+	key := string(cacheKeyPeriodChange)
+	if len(prefixes) == 0 {
+		return []string{key}
+	}
+
+	var out []string
+	for _, p := range prefixes {
+		switch {
+		case strings.HasPrefix(p, key):
+			out = append(out, p)
+		case strings.HasPrefix(key, p):
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
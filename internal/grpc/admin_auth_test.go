@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminAuthInterceptor(t *testing.T) {
+	interceptor := AdminAuthInterceptor([]string{"first", "second"})
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+	admin := &grpc.UnaryServerInfo{FullMethod: "/ticketscoring.v1.Admin/GetConfig"}
+
+	call := func(info *grpc.UnaryServerInfo, md ...string) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(md...))
+		_, err := interceptor(ctx, nil, info, handler)
+		return err
+	}
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(call(admin)))
+	assert.Equal(t, codes.Unauthenticated, status.Code(call(admin, "authorization", "second")))
+	assert.Equal(t, codes.PermissionDenied, status.Code(call(admin, "authorization", "Bearer third")))
+	require.NoError(t, call(admin, "authorization", "Bearer second"))
+	require.NoError(t, call(&grpc.UnaryServerInfo{FullMethod: "/ticketscoring.v1.TicketScoring/GetOverallQualityScore"}))
+}
+
+func TestAdminAuthInterceptorPrincipal(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	accessLog := grpcsrv.LoggingInterceptor(zap.New(core))
+	auth := AdminAuthInterceptor([]string{"ops:first", "second"})
+	info := &grpc.UnaryServerInfo{FullMethod: "/ticketscoring.v1.Admin/GetConfig"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return auth(ctx, req, info, func(context.Context, any) (any, error) { return nil, nil })
+	}
+
+	for _, token := range []string{"first", "second"} {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+		_, err := accessLog(ctx, nil, info, handler)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 2, logs.Len())
+	assert.Equal(t, "ops", logs.All()[0].ContextMap()["principal"])
+	assert.Equal(t, "admin", logs.All()[1].ContextMap()["principal"])
+}
@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/internal/buildinfo"
+	"github.com/godilite/qa-server/pkg/cache"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GetBuildInfo returns the version of the running binary.
+func (a *AdminHandlers) GetBuildInfo(context.Context, *pb.GetBuildInfoRequest) (*pb.GetBuildInfoResponse, error) {
+	info := buildinfo.Get()
+	return &pb.GetBuildInfoResponse{
+		Version:   info.Version,
+		Revision:  info.Revision,
+		GoVersion: info.GoVersion,
+		StartTime: timestamppb.New(info.StartTime),
+	}, nil
+}
+
+// GetConfig returns the effective configuration with secrets redacted.
+func (a *AdminHandlers) GetConfig(context.Context, *pb.GetConfigRequest) (*pb.GetConfigResponse, error) {
+	if a.config == nil {
+		return nil, status.Error(codes.Unimplemented, "configuration is not available")
+	}
+	return &pb.GetConfigResponse{Settings: a.config()}, nil
+}
+
+// GetDatabaseStats returns the connection pool statistics.
+func (a *AdminHandlers) GetDatabaseStats(context.Context, *pb.GetDatabaseStatsRequest) (*pb.GetDatabaseStatsResponse, error) {
+	if a.dbStats == nil {
+		return nil, status.Error(codes.Unimplemented, "database statistics are not available")
+	}
+	stats := a.dbStats()
+	return &pb.GetDatabaseStatsResponse{
+		MaxOpenConnections: int64(stats.MaxOpenConnections),
+		OpenConnections:    int64(stats.OpenConnections),
+		InUse:              int64(stats.InUse),
+		Idle:               int64(stats.Idle),
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       durationpb.New(stats.WaitDuration),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}, nil
+}
+
+// GetCacheStatus reports each tier of the cache stack, pinging remote backends.
+func (a *AdminHandlers) GetCacheStatus(ctx context.Context, _ *pb.GetCacheStatusRequest) (*pb.GetCacheStatusResponse, error) {
+	ctx, cancel := withDefaultDeadline(ctx, defaultGRPCTimeout)
+	defer cancel()
+
+	tiers := cache.Status(ctx, a.cache)
+	resp := &pb.GetCacheStatusResponse{Tiers: make([]*pb.CacheTier, len(tiers))}
+	for i, t := range tiers {
+		resp.Tiers[i] = &pb.CacheTier{Tier: t.Tier, Healthy: t.Healthy, Detail: t.Detail}
+	}
+	return resp, nil
+}
+
+// ListInflightFetches lists the reports being computed, oldest first.
+func (a *AdminHandlers) ListInflightFetches(context.Context, *pb.ListInflightFetchesRequest) (*pb.ListInflightFetchesResponse, error) {
+	fetches := InflightFetches()
+	resp := &pb.ListInflightFetchesResponse{Fetches: make([]*pb.InflightFetch, len(fetches))}
+	for i, f := range fetches {
+		resp.Fetches[i] = &pb.InflightFetch{
+			Key:        f.Key,
+			StartedAt:  timestamppb.New(f.StartedAt),
+			Background: f.Background,
+		}
+	}
+	return resp, nil
+}
+
+// SetLogLevel changes the log level of the whole process.
+func (a *AdminHandlers) SetLogLevel(ctx context.Context, req *pb.SetLogLevelRequest) (*pb.SetLogLevelResponse, error) {
+	if a.logLevel == nil {
+		return nil, status.Error(codes.Unimplemented, "log level is not adjustable")
+	}
+	level, err := zapcore.ParseLevel(req.GetLevel())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid log level %q", req.GetLevel())
+	}
+
+	previous := a.logLevel.Level()
+	a.logLevel.SetLevel(level)
+	grpcsrv.RequestLogger(ctx, a.logger).Warn("log level changed", zap.Stringer("from", previous), zap.Stringer("to", level))
+
+	return &pb.SetLogLevelResponse{PreviousLevel: previous.String(), Level: level.String()}, nil
+}
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminIntrospection(t *testing.T) {
+	ctx := context.Background()
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	admin := NewAdminHandlers(cache.NewNoop(), zap.NewNop(),
+		WithConfigSource(func() map[string]string { return map[string]string{"REDIS_PASSWORD": "[redacted]"} }),
+		WithDBStats(func() sql.DBStats { return sql.DBStats{MaxOpenConnections: 25, InUse: 3, WaitDuration: time.Second} }),
+		WithAdminLogLevel(level),
+	)
+
+	t.Run("build info", func(t *testing.T) {
+		resp, err := admin.GetBuildInfo(ctx, &pb.GetBuildInfoRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "dev", resp.Version)
+		assert.NotEmpty(t, resp.GoVersion)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		resp, err := admin.GetConfig(ctx, &pb.GetConfigRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"REDIS_PASSWORD": "[redacted]"}, resp.Settings)
+	})
+
+	t.Run("database stats", func(t *testing.T) {
+		resp, err := admin.GetDatabaseStats(ctx, &pb.GetDatabaseStatsRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(25), resp.MaxOpenConnections)
+		assert.Equal(t, int64(3), resp.InUse)
+		assert.Equal(t, time.Second, resp.WaitDuration.AsDuration())
+	})
+
+	t.Run("cache status", func(t *testing.T) {
+		resp, err := admin.GetCacheStatus(ctx, &pb.GetCacheStatusRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Tiers, 1)
+		assert.Equal(t, "none", resp.Tiers[0].Tier)
+		assert.True(t, resp.Tiers[0].Healthy)
+	})
+
+	t.Run("log level", func(t *testing.T) {
+		resp, err := admin.SetLogLevel(ctx, &pb.SetLogLevelRequest{Level: "debug"})
+		require.NoError(t, err)
+		assert.Equal(t, "info", resp.PreviousLevel)
+		assert.Equal(t, "debug", resp.Level)
+		assert.Equal(t, zapcore.DebugLevel, level.Level())
+
+		_, err = admin.SetLogLevel(ctx, &pb.SetLogLevelRequest{Level: "loud"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, zapcore.DebugLevel, level.Level())
+	})
+}
+
+func TestAdminIntrospectionUnavailable(t *testing.T) {
+	ctx := context.Background()
+	admin := NewAdminHandlers(cache.NewNoop(), zap.NewNop())
+
+	_, err := admin.GetConfig(ctx, &pb.GetConfigRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	_, err = admin.GetDatabaseStats(ctx, &pb.GetDatabaseStatsRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	_, err = admin.SetLogLevel(ctx, &pb.SetLogLevelRequest{Level: "debug"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestListInflightFetches(t *testing.T) {
+	admin := NewAdminHandlers(cache.NewNoop(), zap.NewNop())
+	doneFirst := inflight.start("ticket_scores:a", false)
+	time.Sleep(time.Millisecond)
+	doneSecond := inflight.start("overall_score:b", true)
+
+	resp, err := admin.ListInflightFetches(context.Background(), &pb.ListInflightFetchesRequest{})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Fetches, 2)
+	assert.Equal(t, "ticket_scores:a", resp.Fetches[0].Key)
+	assert.False(t, resp.Fetches[0].Background)
+	assert.Equal(t, "overall_score:b", resp.Fetches[1].Key)
+	assert.True(t, resp.Fetches[1].Background)
+
+	doneFirst()
+	doneSecond()
+	assert.Empty(t, InflightFetches())
+}
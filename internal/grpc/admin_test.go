@@ -0,0 +1,176 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/internal/grpc/mocks"
+	"github.com/godilite/qa-server/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type invalidateCall struct {
+	prefixes   []string
+	start, end time.Time
+}
+
+func recordingCacher(calls *[]invalidateCall, deleted int64) *mocks.MockCacher {
+	return &mocks.MockCacher{
+		InvalidateFunc: func(_ context.Context, prefixes []string, start, end time.Time) (int64, error) {
+			*calls = append(*calls, invalidateCall{prefixes: prefixes, start: start, end: end})
+			return deleted, nil
+		},
+	}
+}
+
+func TestNormalizeKeyIsWindowKey(t *testing.T) {
+	start := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 9, 8, 0, 0, 0, time.UTC)
+
+	prefix, keyStart, keyEnd, ok := cache.ParseWindowKey(normalizeKey(cacheKeyTicketScores, start, end))
+
+	require.True(t, ok)
+	assert.Equal(t, string(cacheKeyTicketScores), prefix)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), keyStart)
+	assert.Equal(t, time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC), keyEnd)
+}
+
+func TestInvalidateCache(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("prefix only", func(t *testing.T) {
+		var calls []invalidateCall
+		admin := NewAdminHandlers(recordingCacher(&calls, 3), zap.NewNop())
+
+		resp, err := admin.InvalidateCache(context.Background(), &pb.InvalidateCacheRequest{
+			KeyPrefixes: []string{string(cacheKeyTicketScores)},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), resp.DeletedKeys)
+		assert.Equal(t, []invalidateCall{{prefixes: []string{string(cacheKeyTicketScores)}}}, calls)
+	})
+
+	t.Run("date range also clears later period changes", func(t *testing.T) {
+		var calls []invalidateCall
+		admin := NewAdminHandlers(recordingCacher(&calls, 2), zap.NewNop())
+
+		resp, err := admin.InvalidateCache(context.Background(), &pb.InvalidateCacheRequest{
+			StartDate: timestamppb.New(day),
+			EndDate:   timestamppb.New(day),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), resp.DeletedKeys)
+		require.Len(t, calls, 2)
+		assert.Equal(t, invalidateCall{start: day, end: day}, calls[0])
+		assert.Equal(t, invalidateCall{prefixes: []string{string(cacheKeyPeriodChange)}, start: day}, calls[1])
+	})
+
+	t.Run("date range for other prefixes", func(t *testing.T) {
+		var calls []invalidateCall
+		admin := NewAdminHandlers(recordingCacher(&calls, 1), zap.NewNop())
+
+		_, err := admin.InvalidateCache(context.Background(), &pb.InvalidateCacheRequest{
+			KeyPrefixes: []string{string(cacheKeyOverallScore)},
+			StartDate:   timestamppb.New(day),
+			EndDate:     timestamppb.New(day),
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, calls, 1)
+	})
+
+	t.Run("cache failure", func(t *testing.T) {
+		admin := NewAdminHandlers(&mocks.MockCacher{
+			InvalidateFunc: func(context.Context, []string, time.Time, time.Time) (int64, error) {
+				return 0, errors.New("connection refused")
+			},
+		}, zap.NewNop())
+
+		_, err := admin.InvalidateCache(context.Background(), &pb.InvalidateCacheRequest{KeyPrefixes: []string{"grpc:"}})
+
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
+
+func TestInvalidateCacheValidation(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := map[string]*pb.InvalidateCacheRequest{
+		"no selector":  {},
+		"empty prefix": {KeyPrefixes: []string{""}},
+		"reversed range": {
+			StartDate: timestamppb.New(day),
+			EndDate:   timestamppb.New(day.AddDate(0, 0, -1)),
+		},
+	}
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls []invalidateCall
+			admin := NewAdminHandlers(recordingCacher(&calls, 0), zap.NewNop())
+
+			_, err := admin.InvalidateCache(context.Background(), req)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Empty(t, calls)
+		})
+	}
+}
+
+func TestPeriodChangePrefixes(t *testing.T) {
+	key := string(cacheKeyPeriodChange)
+
+	assert.Equal(t, []string{key}, periodChangePrefixes(nil))
+	assert.Equal(t, []string{key}, periodChangePrefixes([]string{"grpc:"}))
+	assert.Equal(t, []string{key + ":2025-03"}, periodChangePrefixes([]string{key + ":2025-03"}))
+	assert.Empty(t, periodChangePrefixes([]string{string(cacheKeyOverallScore)}))
+}
+
+func TestRebuildRollups(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		admin := NewAdminHandlers(&mocks.MockCacher{}, zap.NewNop())
+
+		_, err := admin.RebuildRollups(context.Background(), &pb.RebuildRollupsRequest{})
+
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	t.Run("rebuilds and clears rollup-backed reports", func(t *testing.T) {
+		var calls []invalidateCall
+		admin := NewAdminHandlers(recordingCacher(&calls, 3), zap.NewNop(),
+			WithRollupRebuild(func(context.Context) (int64, error) { return 42, nil }))
+
+		resp, err := admin.RebuildRollups(context.Background(), &pb.RebuildRollupsRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), resp.Ratings)
+		assert.Equal(t, int64(3), resp.DeletedKeys)
+		require.Len(t, calls, 1)
+		assert.ElementsMatch(t, []string{
+			string(cacheKeyOverallScore),
+			string(cacheKeyPeriodChange),
+			string(cacheKeyAggregatedCategory),
+		}, calls[0].prefixes)
+		assert.True(t, calls[0].start.IsZero() && calls[0].end.IsZero())
+	})
+
+	t.Run("rebuild failure keeps the cache", func(t *testing.T) {
+		var calls []invalidateCall
+		admin := NewAdminHandlers(recordingCacher(&calls, 0), zap.NewNop(),
+			WithRollupRebuild(func(context.Context) (int64, error) { return 0, errors.New("disk full") }))
+
+		_, err := admin.RebuildRollups(context.Background(), &pb.RebuildRollupsRequest{})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Empty(t, calls)
+	})
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// backgroundTasks tracks the detached goroutines that refresh and write cache entries, so
+// shutdown can let them finish before the cache and database are closed.
+type backgroundTasks struct {
+	mu sync.Mutex
+	n  int
+	// idle is closed whenever n drops to zero and replaced when it rises again.
+	idle chan struct{}
+}
+
+var background = &backgroundTasks{}
+
+// goTracked runs fn in a goroutine that WaitForBackground waits for.
+func (b *backgroundTasks) goTracked(fn func()) {
+	b.mu.Lock()
+	if b.n == 0 {
+		b.idle = make(chan struct{})
+	}
+	b.n++
+	b.mu.Unlock()
+
+	go func() {
+		defer b.done()
+		fn()
+	}()
+}
+
+func (b *backgroundTasks) done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.n--
+	if b.n == 0 {
+		close(b.idle)
+	}
+}
+
+func (b *backgroundTasks) wait(ctx context.Context) error {
+	b.mu.Lock()
+	if b.n == 0 {
+		b.mu.Unlock()
+		return nil
+	}
+	idle := b.idle
+	b.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		n := b.n
+		b.mu.Unlock()
+		return fmt.Errorf("%d background cache tasks still running: %w", n, ctx.Err())
+	}
+}
+
+// WaitForBackground blocks until the background cache refreshes and writes started by
+// FindAndCache have finished, or ctx is done. Call it once no more requests can arrive.
+func WaitForBackground(ctx context.Context) error {
+	return background.wait(ctx)
+}
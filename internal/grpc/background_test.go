@@ -0,0 +1,38 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godilite/qa-server/internal/grpc/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWaitForBackgroundWaitsForCacheWrites(t *testing.T) {
+	release := make(chan struct{})
+	written := make(chan struct{})
+	c := &mocks.MockCacher{
+		SetFunc: func(context.Context, string, any, time.Duration) error {
+			<-release
+			close(written)
+			return nil
+		},
+	}
+
+	setInBackground(context.Background(), c, "grpc:drain_test", newCacheEntry(1.0, time.Now(), time.Minute), time.Minute, time.Minute, zap.NewNop())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, WaitForBackground(ctx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, WaitForBackground(context.Background()))
+	select {
+	case <-written:
+	default:
+		t.Fatal("WaitForBackground returned before the write finished")
+	}
+}
@@ -0,0 +1,196 @@
+package grpc
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/internal/service"
+	"github.com/godilite/qa-server/pkg/cache"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// Field numbers of the protobuf form of cacheEntry. The value is embedded as the api/v1
+// response message that carries it.
+const (
+	entryFieldValue     protowire.Number = 1
+	entryFieldFetchedAt protowire.Number = 2
+	entryFieldTTL       protowire.Number = 3
+	entryFieldNotFound  protowire.Number = 4
+)
+
+// MarshalProto implements cache.ProtoMarshaler for the value types the handlers cache.
+// Other types return cache.ErrUnsupportedValue, so the cache falls back to msgpack.
+func (e cacheEntry[T]) MarshalProto() ([]byte, error) {
+	var msg proto.Message
+	switch v := any(e.Value).(type) {
+	case float64:
+		msg = &pb.OverallQualityScoreResponse{Score: v}
+	case service.PeriodChange:
+		msg = &pb.PeriodOverPeriodScoreChangeResponse{
+			CurrentPeriodScore:  v.CurrentPeriodScore,
+			PreviousPeriodScore: v.PreviousPeriodScore,
+			ChangePercentage:    v.ChangePercentage,
+		}
+	case []service.TicketScores:
+		msg = &pb.ScoresByTicketResponse{TicketScores: ToProtoTicketScores(v)}
+	case []service.AggregatedCategoryScores:
+		msg = &pb.AggregatedCategoryScoresResponse{CategoryScores: ToProtoCategoryScores(v)}
+	default:
+		return nil, fmt.Errorf("%w: %T", cache.ErrUnsupportedValue, e.Value)
+	}
+
+	value, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	b = protowire.AppendTag(b, entryFieldValue, protowire.BytesType)
+	b = protowire.AppendBytes(b, value)
+	b = protowire.AppendTag(b, entryFieldFetchedAt, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(e.FetchedAt.UnixNano()))
+	b = protowire.AppendTag(b, entryFieldTTL, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(e.TTL))
+	if e.NotFound {
+		b = protowire.AppendTag(b, entryFieldNotFound, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	return b, nil
+}
+
+// UnmarshalProto implements cache.ProtoUnmarshaler.
+func (e *cacheEntry[T]) UnmarshalProto(data []byte) error {
+	*e = cacheEntry[T]{}
+	var value []byte
+
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		switch {
+		case num == entryFieldValue && typ == protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		case num == entryFieldFetchedAt && typ == protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(data)
+			e.FetchedAt = time.Unix(0, int64(v)).UTC()
+		case num == entryFieldTTL && typ == protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(data)
+			e.TTL = time.Duration(v)
+		case num == entryFieldNotFound && typ == protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(data)
+			e.NotFound = v != 0
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+	}
+
+	switch v := any(&e.Value).(type) {
+	case *float64:
+		var msg pb.OverallQualityScoreResponse
+		if err := proto.Unmarshal(value, &msg); err != nil {
+			return err
+		}
+		*v = msg.GetScore()
+	case *service.PeriodChange:
+		var msg pb.PeriodOverPeriodScoreChangeResponse
+		if err := proto.Unmarshal(value, &msg); err != nil {
+			return err
+		}
+		*v = service.PeriodChange{
+			CurrentPeriodScore:  msg.GetCurrentPeriodScore(),
+			PreviousPeriodScore: msg.GetPreviousPeriodScore(),
+			ChangePercentage:    msg.GetChangePercentage(),
+		}
+	case *[]service.TicketScores:
+		var msg pb.ScoresByTicketResponse
+		if err := proto.Unmarshal(value, &msg); err != nil {
+			return err
+		}
+		*v = fromProtoTicketScores(msg.GetTicketScores())
+	case *[]service.AggregatedCategoryScores:
+		var msg pb.AggregatedCategoryScoresResponse
+		if err := proto.Unmarshal(value, &msg); err != nil {
+			return err
+		}
+		*v = fromProtoCategoryScores(msg.GetCategoryScores())
+	default:
+		return fmt.Errorf("%w: %T", cache.ErrUnsupportedValue, e.Value)
+	}
+	return nil
+}
+
+// ToProtoTicketScores converts scores to the messages of a ScoresByTicketResponse.
+func ToProtoTicketScores(scores []service.TicketScores) []*pb.TicketScore {
+	out := make([]*pb.TicketScore, len(scores))
+	for i, score := range scores {
+		out[i] = &pb.TicketScore{
+			TicketId:       score.TicketID,
+			CategoryScores: score.CategoryScores,
+		}
+	}
+	return out
+}
+
+func fromProtoTicketScores(scores []*pb.TicketScore) []service.TicketScores {
+	out := make([]service.TicketScores, len(scores))
+	for i, score := range scores {
+		out[i] = service.TicketScores{
+			TicketID:       score.GetTicketId(),
+			CategoryScores: score.GetCategoryScores(),
+		}
+	}
+	return out
+}
+
+// ToProtoCategoryScores converts scores to the messages of an AggregatedCategoryScoresResponse.
+func ToProtoCategoryScores(scores []service.AggregatedCategoryScores) []*pb.CategoryScore {
+	out := make([]*pb.CategoryScore, len(scores))
+	for i, cat := range scores {
+		periods := make([]*pb.PeriodScore, len(cat.PeriodScores))
+		for j, p := range cat.PeriodScores {
+			periods[j] = &pb.PeriodScore{
+				Period: p.Period,
+				Score:  p.Score,
+			}
+		}
+		out[i] = &pb.CategoryScore{
+			CategoryName:         cat.CategoryName,
+			TotalRatings:         int64(cat.TotalRatings),
+			OverallCategoryScore: cat.OverallCategoryScore,
+			PeriodScores:         periods,
+		}
+	}
+	return out
+}
+
+func fromProtoCategoryScores(scores []*pb.CategoryScore) []service.AggregatedCategoryScores {
+	out := make([]service.AggregatedCategoryScores, len(scores))
+	for i, cat := range scores {
+		periods := make([]service.PeriodScore, len(cat.GetPeriodScores()))
+		for j, p := range cat.GetPeriodScores() {
+			periods[j] = service.PeriodScore{
+				Period: p.GetPeriod(),
+				Score:  p.GetScore(),
+			}
+		}
+		out[i] = service.AggregatedCategoryScores{
+			CategoryName:         cat.GetCategoryName(),
+			TotalRatings:         int(cat.GetTotalRatings()),
+			OverallCategoryScore: cat.GetOverallCategoryScore(),
+			PeriodScores:         periods,
+		}
+	}
+	return out
+}
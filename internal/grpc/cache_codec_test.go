@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godilite/qa-server/internal/service"
+	"github.com/godilite/qa-server/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func roundTripProto[T any](t *testing.T, entry cacheEntry[T]) cacheEntry[T] {
+	t.Helper()
+
+	codec := cache.ProtobufCodec{}
+	data, err := codec.Marshal(entry)
+	require.NoError(t, err)
+
+	var got cacheEntry[T]
+	require.NoError(t, codec.Unmarshal(data, &got))
+	return got
+}
+
+// TestCacheEntryProtoRoundTrip tests that every cached value type survives the protobuf codec
+func TestCacheEntryProtoRoundTrip(t *testing.T) {
+	fetchedAt := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	t.Run("overall score", func(t *testing.T) {
+		entry := newCacheEntry(85.5, fetchedAt, 10*time.Minute)
+		assert.Equal(t, entry, roundTripProto(t, entry))
+	})
+
+	t.Run("period change", func(t *testing.T) {
+		entry := newCacheEntry(service.PeriodChange{
+			CurrentPeriodScore:  80,
+			PreviousPeriodScore: 75,
+			ChangePercentage:    6.67,
+		}, fetchedAt, time.Minute)
+		assert.Equal(t, entry, roundTripProto(t, entry))
+	})
+
+	t.Run("ticket scores", func(t *testing.T) {
+		entry := newCacheEntry([]service.TicketScores{
+			{TicketID: 1, CategoryScores: map[string]float64{"Tone": 90, "Grammar": 70}},
+			{TicketID: 2, CategoryScores: map[string]float64{"Tone": 50}},
+		}, fetchedAt, time.Minute)
+		assert.Equal(t, entry, roundTripProto(t, entry))
+	})
+
+	t.Run("category scores", func(t *testing.T) {
+		entry := newCacheEntry([]service.AggregatedCategoryScores{{
+			CategoryName:         "Tone",
+			TotalRatings:         12,
+			OverallCategoryScore: 81.25,
+			PeriodScores:         []service.PeriodScore{{Period: "2025-01-01", Score: 80}, {Period: "2025-01-02", Score: 82.5}},
+		}}, fetchedAt, time.Minute)
+		assert.Equal(t, entry, roundTripProto(t, entry))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		entry := newNotFoundEntry[[]service.TicketScores](fetchedAt, 30*time.Second)
+		got := roundTripProto(t, entry)
+		assert.True(t, got.NotFound)
+		assert.Equal(t, entry.FetchedAt, got.FetchedAt)
+		assert.Equal(t, entry.TTL, got.TTL)
+		assert.Empty(t, got.Value)
+	})
+}
+
+// TestCacheEntryProtoUnsupported tests that other value types are left to the fallback codec
+func TestCacheEntryProtoUnsupported(t *testing.T) {
+	_, err := cache.ProtobufCodec{}.Marshal(newCacheEntry("text", time.Now(), time.Minute))
+	assert.ErrorIs(t, err, cache.ErrUnsupportedValue)
+}
@@ -7,18 +7,145 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/godilite/qa-server/internal/service"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc"
 )
 
 type FetchFunc[T any] func(ctx context.Context) (T, error)
 
 const (
-	defaultFetchTimeout = 15 * time.Second
-	defaultSetTimeout   = 5 * time.Second
+	defaultFetchTimeout  = 15 * time.Second
+	defaultSetTimeout    = 5 * time.Second
+	defaultRefreshWindow = 0.2
 )
 
+// RefreshPolicy decides when a cache hit also refreshes the entry in the background.
+type RefreshPolicy int
+
+const (
+	// RefreshNearExpiry refreshes only once the entry has entered the last
+	// RefreshAhead.Window fraction of its TTL.
+	RefreshNearExpiry RefreshPolicy = iota
+	// RefreshAlways refreshes on every hit.
+	RefreshAlways
+	// RefreshNever lets entries expire and be refetched on the next miss.
+	RefreshNever
+)
+
+// ParseRefreshPolicy parses "near-expiry", "always" or "never".
+func ParseRefreshPolicy(s string) (RefreshPolicy, error) {
+	switch s {
+	case "near-expiry", "":
+		return RefreshNearExpiry, nil
+	case "always":
+		return RefreshAlways, nil
+	case "never":
+		return RefreshNever, nil
+	default:
+		return 0, fmt.Errorf("unknown refresh policy %q: must be near-expiry, always or never", s)
+	}
+}
+
+// RefreshAhead configures background refreshes of cache hits.
+type RefreshAhead struct {
+	Policy RefreshPolicy
+	// Window is the fraction of the TTL at the end of an entry's life during which hits
+	// trigger a refresh under RefreshNearExpiry. Values outside (0, 1] use the default of 0.2.
+	Window float64
+}
+
+// due reports whether a hit on entry should trigger a refresh at now.
+func (r RefreshAhead) due(fetchedAt time.Time, ttl time.Duration, now time.Time) bool {
+	switch r.Policy {
+	case RefreshAlways:
+		return true
+	case RefreshNever:
+		return false
+	}
+
+	if ttl <= 0 {
+		return false
+	}
+	window := r.Window
+	if window <= 0 || window > 1 {
+		window = defaultRefreshWindow
+	}
+	refreshAfter := time.Duration(float64(ttl) * (1 - window))
+	return now.Sub(fetchedAt) >= refreshAfter
+}
+
+// CachePolicy configures how FindAndCache stores and refreshes entries.
+type CachePolicy struct {
+	TTL time.Duration
+	// NegativeTTL is how long a window without ratings is remembered. Zero disables
+	// negative caching.
+	NegativeTTL time.Duration
+	Refresh     RefreshAhead
+	// FetchTimeout bounds background refreshes and SetTimeout background cache writes,
+	// neither of which has a request deadline to inherit. Zero uses 15s and 5s.
+	FetchTimeout time.Duration
+	SetTimeout   time.Duration
+	// StaleTTL keeps entries this long past their TTL, to be served when the database is
+	// saturated or failing. Zero disables serving stale entries.
+	StaleTTL time.Duration
+	// Saturated reports whether the database is too busy to take another query, in which
+	// case expired entries are served stale and refreshed in the background. Nil means never.
+	Saturated func() bool
+	// Limiter, if set, must admit the fetch of a miss before it queries the database, under
+	// the RPC's method. Hits and stale entries are served without a slot.
+	Limiter *grpcsrv.ConcurrencyLimiter
+}
+
+func (p CachePolicy) fetchTimeout() time.Duration {
+	if p.FetchTimeout > 0 {
+		return p.FetchTimeout
+	}
+	return defaultFetchTimeout
+}
+
+func (p CachePolicy) saturated() bool {
+	return p.Saturated != nil && p.Saturated()
+}
+
+func (p CachePolicy) setTimeout() time.Duration {
+	if p.SetTimeout > 0 {
+		return p.SetTimeout
+	}
+	return defaultSetTimeout
+}
+
+// cacheEntry is what FindAndCache stores: the value together with when it was fetched and
+// the TTL it was stored with, so a hit can tell how close the entry is to expiring. The
+// cache keeps it for CachePolicy.StaleTTL longer, during which it is expired but can be
+// served stale.
+// NotFound entries record that the fetch returned service.ErrNoRatings.
+type cacheEntry[T any] struct {
+	Value     T             `json:"value"`
+	FetchedAt time.Time     `json:"fetched_at"`
+	TTL       time.Duration `json:"ttl"`
+	NotFound  bool          `json:"not_found,omitempty"`
+}
+
+func newCacheEntry[T any](value T, fetchedAt time.Time, ttl time.Duration) cacheEntry[T] {
+	return cacheEntry[T]{Value: value, FetchedAt: fetchedAt, TTL: ttl}
+}
+
+func newNotFoundEntry[T any](fetchedAt time.Time, ttl time.Duration) cacheEntry[T] {
+	return cacheEntry[T]{FetchedAt: fetchedAt, TTL: ttl, NotFound: true}
+}
+
+// expired reports whether the entry has outlived its TTL at now and is only kept to be
+// served stale.
+func (e cacheEntry[T]) expired(now time.Time) bool {
+	return e.TTL > 0 && now.Sub(e.FetchedAt) >= e.TTL
+}
+
 // addTTLJitter adds up to ±30s random jitter to TTL to avoid mass expiration.
 func addTTLJitter(ttl time.Duration) time.Duration {
 	if ttl <= 0 {
@@ -28,38 +155,68 @@ func addTTLJitter(ttl time.Duration) time.Duration {
 	return ttl + jitter
 }
 
+// triggerBackgroundRefresh refreshes key in a tracked background goroutine. The refresh runs in its own
+// trace, linked to the request that triggered it, since it outlives that request.
 func triggerBackgroundRefresh[T any](
 	c Cacher,
 	sf *singleflight.Group,
 	key string,
-	ttl time.Duration,
+	policy CachePolicy,
 	logger *zap.Logger,
+	link trace.Link,
 	fn FetchFunc[T],
 ) {
-	go func() {
+	background.goTracked(func() {
 		time.Sleep(time.Duration(rand.Intn(1000)) * time.Millisecond)
 
 		_, _, _ = sf.Do(key+":refresh", func() (any, error) {
-			ctx, cancel := context.WithTimeout(context.Background(), defaultFetchTimeout)
+			defer inflight.start(key, true)()
+
+			ctx, cancel := context.WithTimeout(context.Background(), policy.fetchTimeout())
 			defer cancel()
 
+			ctx, span := tracer.Start(ctx, "cache.BackgroundRefresh",
+				trace.WithNewRoot(),
+				trace.WithLinks(link),
+				trace.WithAttributes(attribute.String("cache.key", key)))
+			defer span.End()
+
+			fetchedAt := time.Now()
 			value, err := fn(ctx)
+			if errors.Is(err, service.ErrNoRatings) && policy.NegativeTTL > 0 {
+				// The window has emptied since it was cached; remember that instead.
+				setCtx, cancelSet := context.WithTimeout(trace.ContextWithSpan(context.Background(), span), policy.setTimeout())
+				defer cancelSet()
+				if err := c.Set(setCtx, key, newNotFoundEntry[T](fetchedAt, policy.NegativeTTL), policy.NegativeTTL); err != nil {
+					recordSpanError(span, err)
+					backgroundRefreshes.WithLabelValues(metricPrefix(key), refreshOutcomeSetError).Inc()
+					logger.Warn("failed to update cache in background", zap.String("key", key), zap.Error(err))
+					return nil, err
+				}
+				backgroundRefreshes.WithLabelValues(metricPrefix(key), refreshOutcomeSuccess).Inc()
+				return nil, nil
+			}
 			if err != nil {
+				recordSpanError(span, err)
+				backgroundRefreshes.WithLabelValues(metricPrefix(key), refreshOutcomeFetchError).Inc()
 				logger.Warn("background refresh failed",
 					zap.String("key", key),
 					zap.Error(err))
 				return nil, err
 			}
 
-			setCtx, cancelSet := context.WithTimeout(context.Background(), defaultSetTimeout)
+			setCtx, cancelSet := context.WithTimeout(trace.ContextWithSpan(context.Background(), span), policy.setTimeout())
 			defer cancelSet()
 
-			ttlWithJitter := addTTLJitter(ttl)
-			if err := c.Set(setCtx, key, value, ttlWithJitter); err != nil {
+			ttlWithJitter := addTTLJitter(policy.TTL)
+			if err := c.Set(setCtx, key, newCacheEntry(value, fetchedAt, ttlWithJitter), ttlWithJitter+policy.StaleTTL); err != nil {
+				recordSpanError(span, err)
+				backgroundRefreshes.WithLabelValues(metricPrefix(key), refreshOutcomeSetError).Inc()
 				logger.Warn("failed to update cache in background",
 					zap.String("key", key),
 					zap.Error(err))
 			} else {
+				backgroundRefreshes.WithLabelValues(metricPrefix(key), refreshOutcomeSuccess).Inc()
 				logger.Debug("cache refreshed in background",
 					zap.String("key", key),
 					zap.Duration("ttl", ttlWithJitter))
@@ -67,47 +224,128 @@ func triggerBackgroundRefresh[T any](
 
 			return value, nil
 		})
-	}()
+	})
 }
 
+// fetchAndCacheInBackground fetches the value and stores it without making the caller
+// wait for the cache write. ErrNoRatings is stored as a NotFound entry for the negative TTL.
 func fetchAndCacheInBackground[T any](
 	ctx context.Context,
 	c Cacher,
 	key string,
-	ttl time.Duration,
+	policy CachePolicy,
 	logger *zap.Logger,
 	fn FetchFunc[T],
 ) (T, error) {
 	var zero T
 
+	fetchedAt := time.Now()
 	value, err := fn(ctx)
+	if errors.Is(err, service.ErrNoRatings) {
+		if policy.NegativeTTL > 0 {
+			setInBackground(ctx, c, key, newNotFoundEntry[T](fetchedAt, policy.NegativeTTL), policy.NegativeTTL, policy.setTimeout(), logger)
+		}
+		return zero, err
+	}
 	if err != nil {
 		logger.Error("fetch failed", zap.String("key", key), zap.Error(err))
 		return zero, err
 	}
 
-	go func(v T) {
-		setCtx, cancel := context.WithTimeout(context.Background(), defaultSetTimeout)
+	ttlWithJitter := addTTLJitter(policy.TTL)
+	setInBackground(ctx, c, key, newCacheEntry(value, fetchedAt, ttlWithJitter), ttlWithJitter+policy.StaleTTL, policy.setTimeout(), logger)
+	return value, nil
+}
+
+// setInBackground writes entry in a tracked background goroutine, traced as a new root linked to ctx.
+func setInBackground[T any](ctx context.Context, c Cacher, key string, entry cacheEntry[T], ttl, timeout time.Duration, logger *zap.Logger) {
+	link := trace.LinkFromContext(ctx)
+	background.goTracked(func() {
+		setCtx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 
-		ttlWithJitter := addTTLJitter(ttl)
-		if err := c.Set(setCtx, key, v, ttlWithJitter); err != nil {
+		setCtx, span := tracer.Start(setCtx, "cache.BackgroundSet",
+			trace.WithNewRoot(),
+			trace.WithLinks(link),
+			trace.WithAttributes(
+				attribute.String("cache.key", key),
+				attribute.Bool("cache.negative", entry.NotFound)))
+		defer span.End()
+
+		if err := c.Set(setCtx, key, entry, ttl); err != nil {
+			recordSpanError(span, err)
 			logger.Warn("failed to set cache on miss", zap.String("key", key), zap.Error(err))
 		} else {
-			logger.Debug("cache populated on miss", zap.String("key", key))
+			logger.Debug("cache populated on miss", zap.String("key", key), zap.Bool("not_found", entry.NotFound))
 		}
-	}(value)
+	})
+}
 
-	return value, nil
+// WarmCache fetches key and stores it whether or not it is cached already, returning once
+// both are done with the fetch or cache write error. A window without ratings is stored as
+// a NotFound entry for policy.NegativeTTL and reported as service.ErrNoRatings. A warm
+// and a background refresh of the same key share one fetch.
+func WarmCache[T any](
+	ctx context.Context,
+	c Cacher,
+	sf *singleflight.Group,
+	key string,
+	policy CachePolicy,
+	logger *zap.Logger,
+	fn FetchFunc[T],
+) error {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	ctx, span := tracer.Start(ctx, "cache.Warm", trace.WithAttributes(attribute.String("cache.key", key)))
+	defer span.End()
+
+	_, err, _ := sf.Do(key+":refresh", func() (any, error) {
+		defer inflight.start(key, true)()
+
+		fetchedAt := time.Now()
+		value, err := fn(ctx)
+		entry, ttl := newCacheEntry(value, fetchedAt, addTTLJitter(policy.TTL)), time.Duration(0)
+		switch {
+		case errors.Is(err, service.ErrNoRatings) && policy.NegativeTTL > 0:
+			entry, ttl = newNotFoundEntry[T](fetchedAt, policy.NegativeTTL), policy.NegativeTTL
+		case err != nil:
+			return nil, err
+		default:
+			ttl = entry.TTL + policy.StaleTTL
+		}
+
+		setCtx, cancel := context.WithTimeout(ctx, policy.setTimeout())
+		defer cancel()
+		if setErr := c.Set(setCtx, key, entry, ttl); setErr != nil {
+			return nil, fmt.Errorf("cache %s: %w", key, setErr)
+		}
+		logger.Debug("cache warmed", zap.String("key", key), zap.Bool("not_found", entry.NotFound))
+		return nil, err
+	})
+	if err != nil && !errors.Is(err, service.ErrNoRatings) {
+		recordSpanError(span, err)
+	}
+	return err
 }
 
 // FindAndCache implements read-through caching with singleflight and refresh-ahead logic.
+// Values are stored as a cacheEntry; entries without a fetch time, such as those written
+// before entries carried one, are treated as misses. Windows without ratings are cached for
+// policy.NegativeTTL and served as service.ErrNoRatings. Expired entries kept for
+// policy.StaleTTL are served stale instead of querying a saturated database, and in place
+// of a failed fetch. Only the fetch of a miss takes a policy.Limiter slot, so hits are
+// served however busy the database is, and a fetch the limiter sheds falls back to a stale
+// entry like a failed one. The logger should carry the request ID (see
+// grpcsrv.RequestLogger); fetches and refreshes shared with later callers log it with the
+// request that started them.
 func FindAndCache[T any](
 	ctx context.Context,
 	c Cacher,
 	sf *singleflight.Group,
 	key string,
-	ttl time.Duration,
+	policy CachePolicy,
 	logger *zap.Logger,
 	fn FetchFunc[T],
 ) (T, error) {
@@ -116,25 +354,95 @@ func FindAndCache[T any](
 		logger = zap.NewNop()
 	}
 
-	var cached T
-	err := c.Get(ctx, key, &cached)
+	prefix := metricPrefix(key)
+
+	ctx, span := tracer.Start(ctx, "cache.FindAndCache", trace.WithAttributes(attribute.String("cache.key", key)))
+	defer span.End()
+
+	var cached cacheEntry[T]
+	getCtx, getSpan := tracer.Start(ctx, "cache.Get")
+	err := c.Get(getCtx, key, &cached)
+	getSpan.End()
+	if err == nil && cached.FetchedAt.IsZero() {
+		err = redis.Nil
+	}
+
+	var stale *cacheEntry[T]
 	switch {
+	case err == nil && cached.NotFound:
+		cacheLookups.WithLabelValues(prefix, cacheResultHit).Inc()
+		span.SetAttributes(
+			attribute.String("cache.result", cacheResultHit),
+			attribute.Bool("cache.negative", true))
+		grpcsrv.AddAccessLogFields(ctx, zap.String("cache", cacheResultHit), zap.Bool("cache_negative", true))
+		logger.Debug("negative cache hit", zap.String("key", key))
+		return zero, service.ErrNoRatings
+
+	case err == nil && cached.expired(time.Now()) && policy.saturated():
+		// Another query would only queue behind the ones keeping the database busy.
+		triggerBackgroundRefresh(c, sf, key, policy, logger, trace.LinkFromContext(ctx), fn)
+		return serveStale(ctx, span, prefix, staleReasonSaturated, cached, logger), nil
+
+	case err == nil && cached.expired(time.Now()):
+		stale = &cached
+		cacheLookups.WithLabelValues(prefix, cacheResultMiss).Inc()
+		span.SetAttributes(attribute.String("cache.result", cacheResultMiss))
+		grpcsrv.AddAccessLogFields(ctx, zap.String("cache", cacheResultMiss))
+		logger.Debug("cache entry expired", zap.String("key", key))
+
 	case err == nil:
+		cacheLookups.WithLabelValues(prefix, cacheResultHit).Inc()
+		span.SetAttributes(attribute.String("cache.result", cacheResultHit))
+		grpcsrv.AddAccessLogFields(ctx, zap.String("cache", cacheResultHit))
 		logger.Debug("cache hit", zap.String("key", key))
-		triggerBackgroundRefresh(c, sf, key, ttl, logger, fn)
-		return cached, nil
+		if policy.Refresh.due(cached.FetchedAt, cached.TTL, time.Now()) {
+			span.SetAttributes(attribute.Bool("cache.refresh", true))
+			triggerBackgroundRefresh(c, sf, key, policy, logger, trace.LinkFromContext(ctx), fn)
+		}
+		return cached.Value, nil
 
 	case errors.Is(err, redis.Nil):
+		cacheLookups.WithLabelValues(prefix, cacheResultMiss).Inc()
+		span.SetAttributes(attribute.String("cache.result", cacheResultMiss))
+		grpcsrv.AddAccessLogFields(ctx, zap.String("cache", cacheResultMiss))
 		logger.Debug("cache miss", zap.String("key", key))
 
 	default:
+		cacheLookups.WithLabelValues(prefix, cacheResultError).Inc()
+		span.SetAttributes(attribute.String("cache.result", cacheResultError))
+		grpcsrv.AddAccessLogFields(ctx, zap.String("cache", cacheResultError))
 		logger.Warn("cache get error (treating as miss)", zap.String("key", key), zap.Error(err))
 	}
 
-	v, err, shared := sf.Do(key, func() (any, error) {
-		return fetchAndCacheInBackground(ctx, c, key, ttl, logger, fn)
+	sfCtx, sfSpan := tracer.Start(ctx, "singleflight.Do")
+	v, err, shared := sf.Do(key, func() (v any, err error) {
+		defer inflight.start(key, false)()
+		release, err := admitFetch(sfCtx, policy.Limiter, key)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			// The service wraps query errors without %w, hiding a deadline that cut the
+			// query off; the limiter must still see it as a timeout.
+			outcome := err
+			if err != nil {
+				outcome = errors.Join(err, sfCtx.Err())
+			}
+			release(outcome)
+		}()
+		return fetchAndCacheInBackground(sfCtx, c, key, policy, logger, fn)
 	})
+	sfSpan.SetAttributes(attribute.Bool("singleflight.shared", shared))
+	if err != nil {
+		recordSpanError(sfSpan, err)
+	}
+	sfSpan.End()
+	if err != nil && stale != nil && !errors.Is(err, service.ErrNoRatings) {
+		logger.Warn("fetch failed, serving stale cache entry", zap.String("key", key), zap.Error(err))
+		return serveStale(ctx, span, prefix, staleReasonFetchError, *stale, logger), nil
+	}
 	if err != nil {
+		recordSpanError(span, err)
 		return zero, err
 	}
 
@@ -145,8 +453,39 @@ func FindAndCache[T any](
 	}
 
 	if shared {
+		singleflightShared.WithLabelValues(prefix).Inc()
+		grpcsrv.AddAccessLogFields(ctx, zap.Bool("singleflight_shared", true))
 		logger.Debug("singleflight shared result", zap.String("key", key))
 	}
 
 	return value, nil
 }
+
+// admitFetch takes a limiter slot for the fetch of key, named after the RPC it serves, or
+// after the key's prefix outside of one. A nil limiter admits every fetch.
+func admitFetch(ctx context.Context, limiter *grpcsrv.ConcurrencyLimiter, key string) (release func(error), err error) {
+	if limiter == nil {
+		return func(error) {}, nil
+	}
+	method, ok := grpc.Method(ctx)
+	if !ok {
+		method = metricPrefix(key)
+	}
+	return limiter.Acquire(ctx, method)
+}
+
+// serveStale records that an expired entry is answered in place of a fresh value.
+func serveStale[T any](ctx context.Context, span trace.Span, prefix, reason string, entry cacheEntry[T], logger *zap.Logger) T {
+	age := time.Since(entry.FetchedAt)
+	cacheLookups.WithLabelValues(prefix, cacheResultStale).Inc()
+	staleServed.WithLabelValues(prefix, reason).Inc()
+	span.SetAttributes(
+		attribute.String("cache.result", cacheResultStale),
+		attribute.String("cache.stale_reason", reason))
+	grpcsrv.AddAccessLogFields(ctx,
+		zap.String("cache", cacheResultStale),
+		zap.String("cache_stale_reason", reason),
+		zap.Duration("cache_age", age))
+	logger.Debug("serving stale cache entry", zap.String("prefix", prefix), zap.String("reason", reason), zap.Duration("age", age))
+	return entry.Value
+}
@@ -0,0 +1,305 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/godilite/qa-server/internal/grpc/mocks"
+	"github.com/godilite/qa-server/internal/service"
+	"github.com/godilite/qa-server/pkg/cache"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestParseRefreshPolicy tests the policy names accepted from configuration
+func TestParseRefreshPolicy(t *testing.T) {
+	for name, expected := range map[string]RefreshPolicy{
+		"":            RefreshNearExpiry,
+		"near-expiry": RefreshNearExpiry,
+		"always":      RefreshAlways,
+		"never":       RefreshNever,
+	} {
+		policy, err := ParseRefreshPolicy(name)
+		require.NoError(t, err)
+		assert.Equal(t, expected, policy, name)
+	}
+
+	_, err := ParseRefreshPolicy("sometimes")
+	assert.Error(t, err)
+}
+
+// TestRefreshAheadDue tests when a hit is due for a background refresh
+func TestRefreshAheadDue(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ttl := 10 * time.Minute
+
+	tests := []struct {
+		name     string
+		refresh  RefreshAhead
+		age      time.Duration
+		ttl      time.Duration
+		expected bool
+	}{
+		{"always, fresh", RefreshAhead{Policy: RefreshAlways}, 0, ttl, true},
+		{"never, about to expire", RefreshAhead{Policy: RefreshNever}, 9*time.Minute + 59*time.Second, ttl, false},
+		{"near expiry, fresh", RefreshAhead{Policy: RefreshNearExpiry, Window: 0.2}, time.Minute, ttl, false},
+		{"near expiry, just before window", RefreshAhead{Policy: RefreshNearExpiry, Window: 0.2}, 8*time.Minute - time.Second, ttl, false},
+		{"near expiry, inside window", RefreshAhead{Policy: RefreshNearExpiry, Window: 0.2}, 8 * time.Minute, ttl, true},
+		{"near expiry, wider window", RefreshAhead{Policy: RefreshNearExpiry, Window: 0.5}, 5 * time.Minute, ttl, true},
+		{"near expiry, invalid window uses default", RefreshAhead{Policy: RefreshNearExpiry, Window: 3}, 5 * time.Minute, ttl, false},
+		{"near expiry, no ttl", RefreshAhead{Policy: RefreshNearExpiry}, time.Hour, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.refresh.due(now.Add(-tt.age), tt.ttl, now))
+		})
+	}
+}
+
+// TestFindAndCacheRefreshesNearExpiry tests that a hit close to expiry is served from the
+// cache and rewritten in the background with a new fetch time
+func TestFindAndCacheRefreshesNearExpiry(t *testing.T) {
+	fetchedAt := time.Now().Add(-9 * time.Minute)
+	written := make(chan cacheEntry[float64], 1)
+
+	c := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			*dest.(*cacheEntry[float64]) = newCacheEntry(1.0, fetchedAt, 10*time.Minute)
+			return nil
+		},
+		SetFunc: func(ctx context.Context, key string, value any, expiration time.Duration) error {
+			written <- value.(cacheEntry[float64])
+			return nil
+		},
+	}
+
+	var sf singleflight.Group
+	v, err := FindAndCache(context.Background(), c, &sf, "grpc:refresh_test:2025-01-01:2025-01-02",
+		CachePolicy{TTL: 10 * time.Minute, Refresh: RefreshAhead{Policy: RefreshNearExpiry, Window: 0.2}}, zap.NewNop(), func(ctx context.Context) (float64, error) {
+			return 2, nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, v)
+
+	select {
+	case entry := <-written:
+		assert.Equal(t, 2.0, entry.Value)
+		assert.True(t, entry.FetchedAt.After(fetchedAt))
+		assert.Positive(t, entry.TTL)
+	case <-time.After(3 * time.Second):
+		t.Fatal("entry near expiry was not refreshed")
+	}
+}
+
+// TestFindAndCacheLegacyEntryIsMiss tests that values stored without a fetch time are refetched
+func TestFindAndCacheLegacyEntryIsMiss(t *testing.T) {
+	var fetches atomic.Int32
+	c := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			return nil // decoded nothing: no fetch time
+		},
+	}
+
+	var sf singleflight.Group
+	v, err := FindAndCache(context.Background(), c, &sf, "grpc:legacy_test:2025-01-01:2025-01-02",
+		CachePolicy{TTL: time.Minute}, zap.NewNop(), func(ctx context.Context) (float64, error) {
+			fetches.Add(1)
+			return 3, nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, v)
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+// TestFindAndCacheNegativeCaching tests that empty windows are stored as NotFound entries
+// for the negative TTL and served as ErrNoRatings without fetching again
+func TestFindAndCacheNegativeCaching(t *testing.T) {
+	ctx := context.Background()
+	const key = "grpc:negative_test:2030-01-01:2030-01-31"
+	c, err := cache.NewMemory()
+	require.NoError(t, err)
+	defer c.Close()
+
+	var fetches atomic.Int32
+	fetch := func(ctx context.Context) (float64, error) {
+		fetches.Add(1)
+		return 0, service.ErrNoRatings
+	}
+	policy := CachePolicy{TTL: time.Hour, NegativeTTL: time.Minute}
+
+	var sf singleflight.Group
+	_, err = FindAndCache(ctx, c, &sf, key, policy, zap.NewNop(), fetch)
+	require.ErrorIs(t, err, service.ErrNoRatings)
+
+	var entry cacheEntry[float64]
+	require.Eventually(t, func() bool {
+		return c.Get(ctx, key, &entry) == nil
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, entry.NotFound)
+	assert.Equal(t, time.Minute, entry.TTL)
+
+	for i := 0; i < 3; i++ {
+		_, err := FindAndCache(ctx, c, &sf, key, policy, zap.NewNop(), fetch)
+		assert.ErrorIs(t, err, service.ErrNoRatings)
+	}
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+// TestFindAndCacheNegativeCachingDisabled tests that a zero negative TTL stores nothing
+func TestFindAndCacheNegativeCachingDisabled(t *testing.T) {
+	var sets atomic.Int32
+	c := &mocks.MockCacher{
+		SetFunc: func(ctx context.Context, key string, value any, expiration time.Duration) error {
+			sets.Add(1)
+			return nil
+		},
+	}
+
+	var sf singleflight.Group
+	_, err := FindAndCache(context.Background(), c, &sf, "grpc:negative_test:2030-01-01:2030-01-31",
+		CachePolicy{TTL: time.Minute}, zap.NewNop(), func(ctx context.Context) (float64, error) {
+			return 0, service.ErrNoRatings
+		})
+
+	assert.ErrorIs(t, err, service.ErrNoRatings)
+	assert.Zero(t, sets.Load())
+}
+
+// TestFindAndCacheServesStaleWhenSaturated tests that an expired entry is served without
+// querying a saturated database, and refreshed in the background for its TTL plus StaleTTL
+func TestFindAndCacheServesStaleWhenSaturated(t *testing.T) {
+	written := make(chan time.Duration, 1)
+	c := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			*dest.(*cacheEntry[float64]) = newCacheEntry(1.0, time.Now().Add(-15*time.Minute), 10*time.Minute)
+			return nil
+		},
+		SetFunc: func(ctx context.Context, key string, value any, expiration time.Duration) error {
+			written <- expiration
+			return nil
+		},
+	}
+	policy := CachePolicy{TTL: 10 * time.Minute, StaleTTL: time.Hour, Saturated: func() bool { return true }}
+
+	var sf singleflight.Group
+	v, err := FindAndCache(context.Background(), c, &sf, "grpc:stale_test:2025-01-01:2025-01-02",
+		policy, zap.NewNop(), func(ctx context.Context) (float64, error) {
+			return 2, nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, v)
+
+	select {
+	case expiration := <-written:
+		assert.Greater(t, expiration, time.Hour, "the refreshed entry is kept for its stale period too")
+	case <-time.After(3 * time.Second):
+		t.Fatal("stale entry was not refreshed")
+	}
+}
+
+// TestFindAndCacheServesStaleOnFetchError tests that an expired entry stands in for a
+// failed fetch, but not for a window that no longer has ratings
+func TestFindAndCacheServesStaleOnFetchError(t *testing.T) {
+	c := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			*dest.(*cacheEntry[float64]) = newCacheEntry(1.0, time.Now().Add(-15*time.Minute), 10*time.Minute)
+			return nil
+		},
+	}
+	policy := CachePolicy{TTL: 10 * time.Minute, StaleTTL: time.Hour, Saturated: func() bool { return false }}
+
+	var sf singleflight.Group
+	v, err := FindAndCache(context.Background(), c, &sf, "grpc:stale_test:2025-01-01:2025-01-02",
+		policy, zap.NewNop(), func(ctx context.Context) (float64, error) {
+			return 0, service.ErrStorageFailure
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, v)
+
+	_, err = FindAndCache(context.Background(), c, &sf, "grpc:stale_test:2025-01-01:2025-01-02",
+		policy, zap.NewNop(), func(ctx context.Context) (float64, error) {
+			return 0, service.ErrNoRatings
+		})
+	assert.ErrorIs(t, err, service.ErrNoRatings)
+
+	v, err = FindAndCache(context.Background(), c, &sf, "grpc:stale_test:2025-01-01:2025-01-02",
+		policy, zap.NewNop(), func(ctx context.Context) (float64, error) {
+			return 2, nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, v, "an unsaturated database is queried for expired entries")
+}
+
+// TestFindAndCacheLimitsOnlyFetches tests that a cache hit is served while every limiter
+// slot is taken, and a miss, which must query the database, is shed
+func TestFindAndCacheLimitsOnlyFetches(t *testing.T) {
+	const key = "grpc:limit_test:2025-01-01:2025-01-02"
+	limiter := grpcsrv.NewConcurrencyLimiter(grpcsrv.WithLimit(1, 1, 1), grpcsrv.WithQueue(0, 0))
+	release, err := limiter.Acquire(context.Background(), metricPrefix(key))
+	require.NoError(t, err)
+	defer release(nil)
+
+	hit := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			*dest.(*cacheEntry[float64]) = newCacheEntry(1.0, time.Now(), 10*time.Minute)
+			return nil
+		},
+	}
+	policy := CachePolicy{TTL: 10 * time.Minute, Refresh: RefreshAhead{Policy: RefreshNever}, Limiter: limiter}
+	var fetches atomic.Int32
+	fetch := func(ctx context.Context) (float64, error) {
+		fetches.Add(1)
+		return 2, nil
+	}
+
+	var sf singleflight.Group
+	v, err := FindAndCache(context.Background(), hit, &sf, key, policy, zap.NewNop(), fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, v)
+
+	miss := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			return redis.Nil
+		},
+	}
+	_, err = FindAndCache(context.Background(), miss, &sf, key, policy, zap.NewNop(), fetch)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Zero(t, fetches.Load())
+}
+
+// TestFindAndCacheTimeoutLowersLimit tests that a fetch cut off by the client's deadline
+// counts as a timeout for the limiter, even though the service wraps the error
+func TestFindAndCacheTimeoutLowersLimit(t *testing.T) {
+	const key = "grpc:limit_test:2025-01-01:2025-01-02"
+	limiter := grpcsrv.NewConcurrencyLimiter(grpcsrv.WithLimit(10, 1, 10), grpcsrv.WithLatencyThreshold(time.Minute))
+	c := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			return redis.Nil
+		},
+	}
+	policy := CachePolicy{TTL: 10 * time.Minute, Limiter: limiter}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var sf singleflight.Group
+	_, err := FindAndCache(ctx, c, &sf, key, policy, zap.NewNop(), func(ctx context.Context) (float64, error) {
+		<-ctx.Done()
+		return 0, fmt.Errorf("%w: %v", service.ErrStorageFailure, ctx.Err())
+	})
+
+	require.ErrorIs(t, err, service.ErrStorageFailure)
+	assert.Less(t, limiter.Limits()[metricPrefix(key)], 10)
+}
@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/internal/export"
+	"github.com/godilite/qa-server/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// exportChunkSize caps the payload of a single ExportChunk message.
+const exportChunkSize = 64 * 1024
+
+// ExportScores streams the requested report as CSV or Parquet in ExportChunk messages.
+func (s *GRPCHandlers) ExportScores(req *pb.ExportScoresRequest, stream pb.TicketScoring_ExportScoresServer) error {
+	w := bufio.NewWriterSize(&chunkWriter{stream: stream}, exportChunkSize)
+
+	if err := s.writeExport(stream.Context(), req, w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return s.handleError(stream.Context(), "ExportScores", err)
+	}
+	return nil
+}
+
+// writeExport validates req and encodes the report to w. The aggregated category scores
+// report, one row per category and period, is read through the same cache as the query RPCs.
+// The scores by ticket report grows with the number of tickets, so it is streamed from the
+// database row by row, bypassing the cache, and never held in memory. Errors are gRPC status
+// errors; once data has been written to w a failure can only be reported by aborting the
+// stream.
+func (s *GRPCHandlers) writeExport(ctx context.Context, req *pb.ExportScoresRequest, w io.Writer) error {
+	start, end, err := s.parseAndValidate(&pb.TimePeriodRequest{
+		StartDate: req.GetStartDate(),
+		EndDate:   req.GetEndDate(),
+	})
+	if err != nil {
+		return err
+	}
+
+	format, err := exportFormat(req.GetFormat())
+	if err != nil {
+		return err
+	}
+
+	ctx, span := tracer.Start(ctx, "GRPCHandlers.ExportScores", windowAttributes(start, end))
+	defer span.End()
+
+	switch req.GetReportType() {
+	case pb.ReportType_REPORT_TYPE_AGGREGATED_CATEGORY_SCORES:
+		// Only the fetch is bounded by the RPC timeout; encoding to a slow client may
+		// legitimately take longer.
+		fetchCtx, cancel := s.requestContext(ctx, "ExportScores")
+		defer cancel()
+
+		results, err := s.aggregatedCategoryScores(fetchCtx, start, end)
+		if err != nil {
+			return s.handleError(fetchCtx, "ExportScores", err)
+		}
+		err = export.WriteCategoryScores(w, format, results)
+		if err != nil {
+			return s.handleError(ctx, "ExportScores", err)
+		}
+
+	case pb.ReportType_REPORT_TYPE_SCORES_BY_TICKET:
+		return s.exportTicketScores(ctx, start, end, format, w)
+
+	default:
+		return status.Error(codes.InvalidArgument, "report_type is required")
+	}
+
+	return nil
+}
+
+// exportTicketScores encodes each row to w as the database returns it. The query and the
+// encoding interleave, so only ctx, the client's deadline, bounds them. The writer is
+// created on the first row, so an empty window or a failed query is still reported as a
+// status before anything has been written.
+func (s *GRPCHandlers) exportTicketScores(ctx context.Context, start, end time.Time, format export.Format, w io.Writer) error {
+	var tw *export.TicketScoreWriter
+	err := s.scoring.EachScoreByTicket(ctx, start, end, func(r service.TicketCategoryScore) error {
+		if tw == nil {
+			var err error
+			if tw, err = export.NewTicketScoreWriter(w, format); err != nil {
+				return err
+			}
+		}
+		return tw.Write(export.TicketScoreRow{TicketID: r.TicketID, Category: r.Category, Score: r.Score})
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if err != nil {
+		return s.handleError(ctx, "ExportScores", err)
+	}
+	return nil
+}
+
+func exportFormat(f pb.ExportFormat) (export.Format, error) {
+	switch f {
+	case pb.ExportFormat_EXPORT_FORMAT_UNSPECIFIED, pb.ExportFormat_EXPORT_FORMAT_CSV:
+		return export.FormatCSV, nil
+	case pb.ExportFormat_EXPORT_FORMAT_PARQUET:
+		return export.FormatParquet, nil
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "unsupported export format %v", f)
+	}
+}
+
+// chunkWriter sends everything written to it as ExportChunk messages of at most exportChunkSize bytes.
+type chunkWriter struct {
+	stream pb.TicketScoring_ExportScoresServer
+}
+
+func (c *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), exportChunkSize)
+		if err := c.stream.Send(&pb.ExportChunk{Data: p[:n]}); err != nil {
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
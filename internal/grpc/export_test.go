@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/godilite/qa-server/internal/grpc/mocks"
+	"github.com/godilite/qa-server/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeExportStream collects the chunks sent by ExportScores
+type fakeExportStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks [][]byte
+}
+
+func (f *fakeExportStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeExportStream) Send(chunk *pb.ExportChunk) error {
+	f.chunks = append(f.chunks, append([]byte(nil), chunk.Data...))
+	return nil
+}
+
+func exportRequest(report pb.ReportType) *pb.ExportScoresRequest {
+	return &pb.ExportScoresRequest{
+		StartDate:  timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:    timestamppb.New(time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)),
+		ReportType: report,
+	}
+}
+
+// TestExportScores tests streaming exports over gRPC
+func TestExportScores(t *testing.T) {
+	const tickets = 5000
+	var cacheCalls atomic.Int32
+	cacher := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			cacheCalls.Add(1)
+			return errors.New("cache miss")
+		},
+		SetFunc: func(ctx context.Context, key string, value any, expiration time.Duration) error {
+			cacheCalls.Add(1)
+			return nil
+		},
+	}
+	mockScoring := &mocks.MockScoringService{
+		EachScoreByTicketFunc: func(ctx context.Context, start, end time.Time, fn func(service.TicketCategoryScore) error) error {
+			for i := range tickets {
+				for _, c := range []string{"Grammar", "Tone"} {
+					score := 60.0
+					if c == "Tone" {
+						score = 80
+					}
+					if err := fn(service.TicketCategoryScore{TicketID: int64(i), Category: c, Score: score}); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+	handlers := NewGRPCHandlers(mockScoring, cacher, zap.NewNop(), time.Minute)
+
+	t.Run("chunks reassemble into the csv", func(t *testing.T) {
+		stream := &fakeExportStream{ctx: context.Background()}
+
+		err := handlers.ExportScores(exportRequest(pb.ReportType_REPORT_TYPE_SCORES_BY_TICKET), stream)
+		require.NoError(t, err)
+
+		require.Greater(t, len(stream.chunks), 1)
+		for _, c := range stream.chunks {
+			assert.LessOrEqual(t, len(c), exportChunkSize)
+		}
+
+		csv := string(bytes.Join(stream.chunks, nil))
+		lines := strings.Split(strings.TrimSuffix(csv, "\n"), "\n")
+		assert.Equal(t, "ticket_id,category,score", lines[0])
+		assert.Len(t, lines, 1+2*tickets)
+		assert.Equal(t, "0,Grammar,60", lines[1])
+		assert.Equal(t, fmt.Sprintf("%d,Tone,80", tickets-1), lines[len(lines)-1])
+	})
+
+	t.Run("tickets bypass the cache", func(t *testing.T) {
+		stream := &fakeExportStream{ctx: context.Background()}
+
+		err := handlers.ExportScores(exportRequest(pb.ReportType_REPORT_TYPE_SCORES_BY_TICKET), stream)
+		require.NoError(t, err)
+
+		assert.Zero(t, cacheCalls.Load())
+	})
+
+	t.Run("no ticket ratings maps to not found", func(t *testing.T) {
+		noRatings := NewGRPCHandlers(&mocks.MockScoringService{
+			EachScoreByTicketFunc: func(ctx context.Context, start, end time.Time, fn func(service.TicketCategoryScore) error) error {
+				return service.ErrNoRatings
+			},
+		}, &mocks.MockCacher{}, zap.NewNop(), time.Minute)
+		stream := &fakeExportStream{ctx: context.Background()}
+
+		err := noRatings.ExportScores(exportRequest(pb.ReportType_REPORT_TYPE_SCORES_BY_TICKET), stream)
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Empty(t, stream.chunks)
+	})
+
+	t.Run("missing report type", func(t *testing.T) {
+		stream := &fakeExportStream{ctx: context.Background()}
+
+		err := handlers.ExportScores(exportRequest(pb.ReportType_REPORT_TYPE_UNSPECIFIED), stream)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Empty(t, stream.chunks)
+	})
+
+	t.Run("no ratings maps to not found", func(t *testing.T) {
+		noRatings := NewGRPCHandlers(&mocks.MockScoringService{
+			GetAggregatedCategoryScoresFunc: func(ctx context.Context, start, end time.Time) ([]service.AggregatedCategoryScores, error) {
+				return nil, service.ErrNoRatings
+			},
+		}, &mocks.MockCacher{}, zap.NewNop(), time.Minute)
+		stream := &fakeExportStream{ctx: context.Background()}
+
+		err := noRatings.ExportScores(exportRequest(pb.ReportType_REPORT_TYPE_AGGREGATED_CATEGORY_SCORES), stream)
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Empty(t, stream.chunks)
+	})
+}
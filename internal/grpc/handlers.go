@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	pb "github.com/godilite/qa-server/api/v1"
 	"github.com/godilite/qa-server/internal/service"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/sync/singleflight"
 	"google.golang.org/grpc/codes"
@@ -15,8 +18,9 @@ import (
 )
 
 const (
-	defaultCacheDuration = 10 * time.Minute
-	defaultGRPCTimeout   = 10 * time.Second
+	defaultCacheDuration         = 10 * time.Minute
+	defaultNegativeCacheDuration = 30 * time.Second
+	defaultGRPCTimeout           = 10 * time.Second
 )
 
 type CacheKeyType string
@@ -30,27 +34,169 @@ const (
 
 type GRPCHandlers struct {
 	pb.UnimplementedTicketScoringServer
-	scoring  ScoringService
-	cache    Cacher
-	logger   *zap.Logger
-	sfGroup  singleflight.Group
+	scoring ScoringService
+	cache   Cacher
+	logger  *zap.Logger
+	sfGroup singleflight.Group
+
+	// policyMu guards the cache settings below, which SetCachePolicy can change while
+	// requests are being served.
+	policyMu sync.RWMutex
 	cacheTTL time.Duration
+	// negativeTTL is how long windows without ratings are cached; zero disables it.
+	negativeTTL time.Duration
+	refresh     RefreshAhead
+
+	// timeout bounds RPCs whose client set no deadline; methodTimeouts overrides it per RPC.
+	timeout        time.Duration
+	methodTimeouts map[string]time.Duration
+	fetchTimeout   time.Duration
+	setTimeout     time.Duration
+
+	// staleTTL and saturated decide when expired entries are served stale.
+	staleTTL  time.Duration
+	saturated func() bool
+	// limiter admits the database queries of cache misses; nil admits them all.
+	limiter *grpcsrv.ConcurrencyLimiter
+}
+
+// HandlerOption configures optional GRPCHandlers behaviour.
+type HandlerOption func(*GRPCHandlers)
+
+// WithRefreshPolicy sets when cache hits refresh the entry in the background.
+// The default is RefreshNearExpiry.
+func WithRefreshPolicy(policy RefreshPolicy) HandlerOption {
+	return func(h *GRPCHandlers) {
+		h.refresh.Policy = policy
+	}
+}
+
+// WithRefreshWindow sets the fraction of the TTL, at the end of an entry's life, during
+// which hits trigger a refresh under RefreshNearExpiry. The default is 0.2.
+func WithRefreshWindow(fraction float64) HandlerOption {
+	return func(h *GRPCHandlers) {
+		h.refresh.Window = fraction
+	}
+}
+
+// WithNegativeTTL sets how long windows without ratings are cached, so repeated
+// requests for them are answered with NotFound without querying the database.
+// Zero disables negative caching. The default is 30s.
+func WithNegativeTTL(ttl time.Duration) HandlerOption {
+	return func(h *GRPCHandlers) {
+		h.negativeTTL = ttl
+	}
+}
+
+// WithTimeout sets how long an RPC may run when the client sets no deadline. A deadline
+// set by the client is always honoured as is. The default is 10s.
+func WithTimeout(timeout time.Duration) HandlerOption {
+	return func(h *GRPCHandlers) {
+		if timeout > 0 {
+			h.timeout = timeout
+		}
+	}
+}
+
+// WithMethodTimeout overrides the WithTimeout timeout for one RPC, named as in the service
+// definition, e.g. "ExportScores".
+func WithMethodTimeout(method string, timeout time.Duration) HandlerOption {
+	return func(h *GRPCHandlers) {
+		if h.methodTimeouts == nil {
+			h.methodTimeouts = make(map[string]time.Duration)
+		}
+		h.methodTimeouts[method] = timeout
+	}
+}
+
+// WithCacheTimeouts bounds background refreshes (fetch) and background cache writes (set),
+// which run detached from any request. Zero keeps the defaults of 15s and 5s.
+func WithCacheTimeouts(fetch, set time.Duration) HandlerOption {
+	return func(h *GRPCHandlers) {
+		h.fetchTimeout = fetch
+		h.setTimeout = set
+	}
+}
+
+// WithStaleTTL keeps cache entries for ttl past their expiry so they can be served stale
+// while the database is saturated (see WithSaturation) or failing. Zero, the default,
+// disables serving stale entries.
+func WithStaleTTL(ttl time.Duration) HandlerOption {
+	return func(h *GRPCHandlers) {
+		h.staleTTL = max(ttl, 0)
+	}
+}
+
+// WithSaturation sets how the handlers tell that the database is saturated, such as every
+// pooled connection being in use with queries waiting for one.
+func WithSaturation(saturated func() bool) HandlerOption {
+	return func(h *GRPCHandlers) {
+		h.saturated = saturated
+	}
+}
+
+// WithConcurrencyLimiter makes cache misses take a limiter slot, per RPC, for the query
+// that fills them. Hits and stale entries are served without one, so a saturated database
+// sheds only the requests the cache cannot answer.
+func WithConcurrencyLimiter(limiter *grpcsrv.ConcurrencyLimiter) HandlerOption {
+	return func(h *GRPCHandlers) {
+		h.limiter = limiter
+	}
 }
 
 // NewGRPCHandlers initializes the gRPC handlers.
-func NewGRPCHandlers(scoring ScoringService, cache Cacher, logger *zap.Logger, ttl time.Duration) *GRPCHandlers {
+func NewGRPCHandlers(scoring ScoringService, cache Cacher, logger *zap.Logger, ttl time.Duration, opts ...HandlerOption) *GRPCHandlers {
 	if scoring == nil {
 		panic("nil ScoringService provided to NewGRPCHandlers")
 	}
 	if ttl <= 0 {
 		ttl = defaultCacheDuration
 	}
-	return &GRPCHandlers{
-		scoring:  scoring,
-		cache:    cache,
-		logger:   logger.Named("grpc-handler"),
-		cacheTTL: ttl,
+	h := &GRPCHandlers{
+		scoring:     scoring,
+		cache:       cache,
+		logger:      logger.Named("grpc-handler"),
+		cacheTTL:    ttl,
+		negativeTTL: defaultNegativeCacheDuration,
+		refresh:     RefreshAhead{Policy: RefreshNearExpiry, Window: defaultRefreshWindow},
+		timeout:     defaultGRPCTimeout,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.negativeTTL < 0 {
+		h.negativeTTL = 0
+	}
+	return h
+}
+
+func (s *GRPCHandlers) cachePolicy() CachePolicy {
+	s.policyMu.RLock()
+	defer s.policyMu.RUnlock()
+	return CachePolicy{
+		TTL:          s.cacheTTL,
+		NegativeTTL:  s.negativeTTL,
+		Refresh:      s.refresh,
+		FetchTimeout: s.fetchTimeout,
+		SetTimeout:   s.setTimeout,
+		StaleTTL:     s.staleTTL,
+		Saturated:    s.saturated,
+		Limiter:      s.limiter,
+	}
+}
+
+// SetCachePolicy changes the TTLs and refresh policy of new and refreshed entries, e.g. on a
+// config reload. Entries already in the cache keep the TTL they were stored with. A
+// non-positive TTL is ignored and a negative NegativeTTL disables negative caching. The
+// policy's timeouts, stale settings and limiter are ignored.
+func (s *GRPCHandlers) SetCachePolicy(policy CachePolicy) {
+	s.policyMu.Lock()
+	defer s.policyMu.Unlock()
+	if policy.TTL > 0 {
+		s.cacheTTL = policy.TTL
 	}
+	s.negativeTTL = max(policy.NegativeTTL, 0)
+	s.refresh = policy.Refresh
 }
 
 func (s *GRPCHandlers) parseAndValidate(req *pb.TimePeriodRequest) (start, end time.Time, err error) {
@@ -70,6 +216,24 @@ func (s *GRPCHandlers) parseAndValidate(req *pb.TimePeriodRequest) (start, end t
 	return
 }
 
+// requestContext bounds ctx by the timeout configured for method, unless the client already
+// set a deadline, which is honoured instead of being shortened or extended.
+func (s *GRPCHandlers) requestContext(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	timeout, ok := s.methodTimeouts[method]
+	if !ok {
+		timeout = s.timeout
+	}
+	return withDefaultDeadline(ctx, timeout)
+}
+
+// withDefaultDeadline applies timeout to ctx only if it has no deadline yet.
+func withDefaultDeadline(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 func normalizeKey(prefix CacheKeyType, start, end time.Time) string {
 	s := start.UTC().Truncate(24 * time.Hour).Format("2006-01-02")
 	e := end.UTC().Truncate(24 * time.Hour).Format("2006-01-02")
@@ -77,24 +241,27 @@ func normalizeKey(prefix CacheKeyType, start, end time.Time) string {
 }
 
 func (s *GRPCHandlers) handleError(ctx context.Context, op string, err error) error {
+	recordSpanError(trace.SpanFromContext(ctx), err)
+	logger := grpcsrv.RequestLogger(ctx, s.logger)
+
 	switch ctx.Err() {
 	case context.Canceled:
-		s.logger.Warn("request canceled", zap.String("op", op))
+		logger.Warn("request canceled", zap.String("op", op))
 		return status.Error(codes.Canceled, "request canceled")
 	case context.DeadlineExceeded:
-		s.logger.Warn("request timeout", zap.String("op", op))
+		logger.Warn("request timeout", zap.String("op", op))
 		return status.Error(codes.DeadlineExceeded, "request timed out")
 	}
 
 	switch {
 	case errors.Is(err, service.ErrNoRatings):
-		s.logger.Info("no ratings found", zap.String("op", op))
+		logger.Info("no ratings found", zap.String("op", op))
 		return status.Error(codes.NotFound, "no ratings found for the given period")
 	case errors.Is(err, service.ErrStorageFailure):
-		s.logger.Error("storage failure", zap.String("op", op), zap.Error(err))
+		logger.Error("storage failure", zap.String("op", op), zap.Error(err))
 		return status.Error(codes.Internal, "database error")
 	default:
-		s.logger.Error("unexpected error", zap.String("op", op), zap.Error(err))
+		logger.Error("unexpected error", zap.String("op", op), zap.Error(err))
 		return status.Errorf(codes.Internal, "%s failed: %v", op, err)
 	}
 }
@@ -105,12 +272,15 @@ func (s *GRPCHandlers) GetOverallQualityScore(ctx context.Context, req *pb.TimeP
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, defaultGRPCTimeout)
+	ctx, span := tracer.Start(ctx, "GRPCHandlers.GetOverallQualityScore", windowAttributes(start, end))
+	defer span.End()
+
+	ctx, cancel := s.requestContext(ctx, "GetOverallQualityScore")
 	defer cancel()
 
 	cacheKey := normalizeKey(cacheKeyOverallScore, start, end)
 
-	score, err := FindAndCache(ctx, s.cache, &s.sfGroup, string(cacheKey), s.cacheTTL, s.logger, func(fetchCtx context.Context) (float64, error) {
+	score, err := FindAndCache(ctx, s.cache, &s.sfGroup, string(cacheKey), s.cachePolicy(), grpcsrv.RequestLogger(ctx, s.logger), func(fetchCtx context.Context) (float64, error) {
 		return s.scoring.GetOverallScore(fetchCtx, start, end)
 	})
 	if err != nil {
@@ -126,27 +296,18 @@ func (s *GRPCHandlers) GetScoresByTicket(ctx context.Context, req *pb.TimePeriod
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, defaultGRPCTimeout)
-	defer cancel()
+	ctx, span := tracer.Start(ctx, "GRPCHandlers.GetScoresByTicket", windowAttributes(start, end))
+	defer span.End()
 
-	cacheKey := normalizeKey(cacheKeyTicketScores, start, end)
+	ctx, cancel := s.requestContext(ctx, "GetScoresByTicket")
+	defer cancel()
 
-	scores, err := FindAndCache(ctx, s.cache, &s.sfGroup, string(cacheKey), s.cacheTTL, s.logger, func(fetchCtx context.Context) ([]service.TicketScores, error) {
-		return s.scoring.GetScoresByTicket(fetchCtx, start, end)
-	})
+	scores, err := s.scoresByTicket(ctx, start, end)
 	if err != nil {
 		return nil, s.handleError(ctx, "GetScoresByTicket", err)
 	}
 
-	pbScores := make([]*pb.TicketScore, len(scores))
-	for i, score := range scores {
-		pbScores[i] = &pb.TicketScore{
-			TicketId:       score.TicketID,
-			CategoryScores: score.CategoryScores,
-		}
-	}
-
-	return &pb.ScoresByTicketResponse{TicketScores: pbScores}, nil
+	return &pb.ScoresByTicketResponse{TicketScores: ToProtoTicketScores(scores)}, nil
 }
 
 func (s *GRPCHandlers) GetPeriodOverPeriodScoreChange(ctx context.Context, req *pb.TimePeriodRequest) (*pb.PeriodOverPeriodScoreChangeResponse, error) {
@@ -155,12 +316,15 @@ func (s *GRPCHandlers) GetPeriodOverPeriodScoreChange(ctx context.Context, req *
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, defaultGRPCTimeout)
+	ctx, span := tracer.Start(ctx, "GRPCHandlers.GetPeriodOverPeriodScoreChange", windowAttributes(start, end))
+	defer span.End()
+
+	ctx, cancel := s.requestContext(ctx, "GetPeriodOverPeriodScoreChange")
 	defer cancel()
 
 	cacheKey := normalizeKey(cacheKeyPeriodChange, start, end)
 
-	change, err := FindAndCache(ctx, s.cache, &s.sfGroup, string(cacheKey), s.cacheTTL, s.logger, func(fetchCtx context.Context) (service.PeriodChange, error) {
+	change, err := FindAndCache(ctx, s.cache, &s.sfGroup, string(cacheKey), s.cachePolicy(), grpcsrv.RequestLogger(ctx, s.logger), func(fetchCtx context.Context) (service.PeriodChange, error) {
 		return s.scoring.GetPeriodOverPeriodScoreChange(fetchCtx, start, end)
 	})
 	if err != nil {
@@ -180,14 +344,13 @@ func (s *GRPCHandlers) GetAggregatedCategoryScores(ctx context.Context, req *pb.
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, defaultGRPCTimeout)
-	defer cancel()
+	ctx, span := tracer.Start(ctx, "GRPCHandlers.GetAggregatedCategoryScores", windowAttributes(start, end))
+	defer span.End()
 
-	cacheKey := normalizeKey(cacheKeyAggregatedCategory, start, end)
+	ctx, cancel := s.requestContext(ctx, "GetAggregatedCategoryScores")
+	defer cancel()
 
-	results, err := FindAndCache(ctx, s.cache, &s.sfGroup, string(cacheKey), s.cacheTTL, s.logger, func(fetchCtx context.Context) ([]service.AggregatedCategoryScores, error) {
-		return s.scoring.GetAggregatedCategoryScores(fetchCtx, start, end)
-	})
+	results, err := s.aggregatedCategoryScores(ctx, start, end)
 	if err != nil {
 		return nil, s.handleError(ctx, "GetAggregatedCategoryScores", err)
 	}
@@ -196,22 +359,62 @@ func (s *GRPCHandlers) GetAggregatedCategoryScores(ctx context.Context, req *pb.
 	return &pb.AggregatedCategoryScoresResponse{CategoryScores: pbScores}, nil
 }
 
-func (s *GRPCHandlers) mapToProtoCategoryScores(scores []service.AggregatedCategoryScores) []*pb.CategoryScore {
-	out := make([]*pb.CategoryScore, len(scores))
-	for i, cat := range scores {
-		periods := make([]*pb.PeriodScore, len(cat.PeriodScores))
-		for j, p := range cat.PeriodScores {
-			periods[j] = &pb.PeriodScore{
-				Period: p.Period,
-				Score:  p.Score,
-			}
-		}
-		out[i] = &pb.CategoryScore{
-			CategoryName:         cat.CategoryName,
-			TotalRatings:         int64(cat.TotalRatings),
-			OverallCategoryScore: cat.OverallCategoryScore,
-			PeriodScores:         periods,
+// scoresByTicket reads per-ticket scores through the cache. Shared by GetScoresByTicket and ExportScores.
+func (s *GRPCHandlers) scoresByTicket(ctx context.Context, start, end time.Time) ([]service.TicketScores, error) {
+	cacheKey := normalizeKey(cacheKeyTicketScores, start, end)
+
+	return FindAndCache(ctx, s.cache, &s.sfGroup, cacheKey, s.cachePolicy(), grpcsrv.RequestLogger(ctx, s.logger), func(fetchCtx context.Context) ([]service.TicketScores, error) {
+		return s.scoring.GetScoresByTicket(fetchCtx, start, end)
+	})
+}
+
+// aggregatedCategoryScores reads per-category aggregates through the cache. Shared by
+// GetAggregatedCategoryScores and ExportScores.
+func (s *GRPCHandlers) aggregatedCategoryScores(ctx context.Context, start, end time.Time) ([]service.AggregatedCategoryScores, error) {
+	cacheKey := normalizeKey(cacheKeyAggregatedCategory, start, end)
+
+	return FindAndCache(ctx, s.cache, &s.sfGroup, cacheKey, s.cachePolicy(), grpcsrv.RequestLogger(ctx, s.logger), func(fetchCtx context.Context) ([]service.AggregatedCategoryScores, error) {
+		return s.scoring.GetAggregatedCategoryScores(fetchCtx, start, end)
+	})
+}
+
+// Warm fetches the window for all four RPCs and stores it in the cache under the same
+// day-truncated keys the RPCs read, whether or not it is cached already, so entries never
+// expire between warm runs. It returns once every fetch and cache write is done. Windows
+// without ratings are cached as NotFound and not reported as errors.
+func (s *GRPCHandlers) Warm(ctx context.Context, start, end time.Time) error {
+	ctx, span := tracer.Start(ctx, "GRPCHandlers.Warm", windowAttributes(start, end))
+	defer span.End()
+
+	policy := s.cachePolicy()
+	logger := grpcsrv.RequestLogger(ctx, s.logger)
+
+	overallErr := WarmCache(ctx, s.cache, &s.sfGroup, normalizeKey(cacheKeyOverallScore, start, end), policy, logger, func(fetchCtx context.Context) (float64, error) {
+		return s.scoring.GetOverallScore(fetchCtx, start, end)
+	})
+	ticketErr := WarmCache(ctx, s.cache, &s.sfGroup, normalizeKey(cacheKeyTicketScores, start, end), policy, logger, func(fetchCtx context.Context) ([]service.TicketScores, error) {
+		return s.scoring.GetScoresByTicket(fetchCtx, start, end)
+	})
+	changeErr := WarmCache(ctx, s.cache, &s.sfGroup, normalizeKey(cacheKeyPeriodChange, start, end), policy, logger, func(fetchCtx context.Context) (service.PeriodChange, error) {
+		return s.scoring.GetPeriodOverPeriodScoreChange(fetchCtx, start, end)
+	})
+	categoryErr := WarmCache(ctx, s.cache, &s.sfGroup, normalizeKey(cacheKeyAggregatedCategory, start, end), policy, logger, func(fetchCtx context.Context) ([]service.AggregatedCategoryScores, error) {
+		return s.scoring.GetAggregatedCategoryScores(fetchCtx, start, end)
+	})
+
+	var errs []error
+	for _, err := range []error{overallErr, ticketErr, changeErr, categoryErr} {
+		if err != nil && !errors.Is(err, service.ErrNoRatings) {
+			errs = append(errs, err)
 		}
 	}
-	return out
+	err := errors.Join(errs...)
+	if err != nil {
+		recordSpanError(span, err)
+	}
+	return err
+}
+
+func (s *GRPCHandlers) mapToProtoCategoryScores(scores []service.AggregatedCategoryScores) []*pb.CategoryScore {
+	return ToProtoCategoryScores(scores)
 }
@@ -3,15 +3,22 @@ package grpc
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	pb "github.com/godilite/qa-server/api/v1"
 	"github.com/godilite/qa-server/internal/grpc/mocks"
 	"github.com/godilite/qa-server/internal/service"
+	"github.com/godilite/qa-server/pkg/cache"
+	grpcsrv "github.com/godilite/qa-server/pkg/grpc/server"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -61,6 +68,17 @@ func TestNewGRPCHandlers(t *testing.T) {
 
 		assert.Equal(t, defaultCacheDuration, handlers.cacheTTL)
 	})
+
+	t.Run("negative caching defaults and can be disabled", func(t *testing.T) {
+		mockScoring := &mocks.MockScoringService{}
+		mockCache := &mocks.MockCacher{}
+
+		handlers := NewGRPCHandlers(mockScoring, mockCache, zap.NewNop(), time.Minute)
+		assert.Equal(t, defaultNegativeCacheDuration, handlers.negativeTTL)
+
+		handlers = NewGRPCHandlers(mockScoring, mockCache, zap.NewNop(), time.Minute, WithNegativeTTL(0))
+		assert.Zero(t, handlers.negativeTTL)
+	})
 }
 
 // TestRequestValidation tests request validation through the actual handler methods
@@ -403,6 +421,74 @@ func TestGetOverallQualityScore(t *testing.T) {
 		assert.Equal(t, codes.NotFound, status.Code(err))
 		assert.Contains(t, err.Error(), "no ratings found")
 	})
+
+	t.Run("cached empty window is NotFound without querying", func(t *testing.T) {
+		var calls atomic.Int32
+		mockScoring := &mocks.MockScoringService{
+			GetOverallScoreFunc: func(ctx context.Context, start, end time.Time) (float64, error) {
+				calls.Add(1)
+				return 0, service.ErrNoRatings
+			},
+		}
+		memory, err := cache.NewMemory()
+		require.NoError(t, err)
+		defer memory.Close()
+		handlers := NewGRPCHandlers(mockScoring, memory, zap.NewNop(), time.Minute, WithNegativeTTL(time.Minute))
+
+		start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)
+		req := &pb.TimePeriodRequest{
+			StartDate: timestamppb.New(start),
+			EndDate:   timestamppb.New(end),
+		}
+
+		_, err = handlers.GetOverallQualityScore(context.Background(), req)
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		key := normalizeKey(cacheKeyOverallScore, start, end)
+		require.Eventually(t, func() bool {
+			var entry cacheEntry[float64]
+			return memory.Get(context.Background(), key, &entry) == nil
+		}, time.Second, 5*time.Millisecond)
+
+		resp, err := handlers.GetOverallQualityScore(context.Background(), req)
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+// TestCacheLogsCarryRequestID tests that cache and fetch logs can be tied to the request
+func TestCacheLogsCarryRequestID(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	mockScoring := &mocks.MockScoringService{
+		GetOverallScoreFunc: func(ctx context.Context, start, end time.Time) (float64, error) {
+			return 0, errors.New("connection lost")
+		},
+	}
+	mockCache := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			return errors.New("redis down")
+		},
+	}
+	handlers := NewGRPCHandlers(mockScoring, mockCache, zap.New(core), time.Minute)
+	req := &pb.TimePeriodRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)),
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(grpcsrv.RequestIDHeader, "req-42"))
+	_, err := grpcsrv.RequestIDInterceptor()(ctx, req, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		return handlers.GetOverallQualityScore(ctx, req.(*pb.TimePeriodRequest))
+	})
+	require.Equal(t, codes.Internal, status.Code(err))
+
+	for _, msg := range []string{"cache get error (treating as miss)", "fetch failed"} {
+		entries := logs.FilterMessage(msg).All()
+		require.Len(t, entries, 1, msg)
+		assert.Equal(t, "req-42", entries[0].ContextMap()["request_id"], msg)
+	}
 }
 
 // TestGetScoresByTicket tests validation for GetScoresByTicket
@@ -622,3 +708,156 @@ func TestSuccessfulCalls(t *testing.T) {
 		assert.Len(t, cat.PeriodScores, 2)
 	})
 }
+
+func TestWarm(t *testing.T) {
+	t.Run("populates every RPC's cache key", func(t *testing.T) {
+		var calls atomic.Int32
+		mockScoring := &mocks.MockScoringService{
+			GetOverallScoreFunc: func(ctx context.Context, start, end time.Time) (float64, error) {
+				calls.Add(1)
+				return 90.0, nil
+			},
+			GetScoresByTicketFunc: func(ctx context.Context, start, end time.Time) ([]service.TicketScores, error) {
+				calls.Add(1)
+				return []service.TicketScores{{TicketID: 1, CategoryScores: map[string]float64{"Tone": 80}}}, nil
+			},
+			GetPeriodOverPeriodScoreChangeFunc: func(ctx context.Context, start, end time.Time) (service.PeriodChange, error) {
+				calls.Add(1)
+				return service.PeriodChange{}, service.ErrNoRatings
+			},
+			GetAggregatedCategoryScoresFunc: func(ctx context.Context, start, end time.Time) ([]service.AggregatedCategoryScores, error) {
+				calls.Add(1)
+				return []service.AggregatedCategoryScores{{CategoryName: "Tone", TotalRatings: 1}}, nil
+			},
+		}
+		memory, err := cache.NewMemory()
+		require.NoError(t, err)
+		defer memory.Close()
+		handlers := NewGRPCHandlers(mockScoring, memory, zap.NewNop(), time.Minute)
+
+		start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)
+
+		require.NoError(t, handlers.Warm(context.Background(), start, end))
+		assert.Equal(t, int32(4), calls.Load())
+
+		var overall cacheEntry[float64]
+		var change cacheEntry[service.PeriodChange]
+		require.NoError(t, memory.Get(context.Background(), normalizeKey(cacheKeyOverallScore, start, end), &overall))
+		require.NoError(t, memory.Get(context.Background(), normalizeKey(cacheKeyPeriodChange, start, end), &change))
+		assert.True(t, change.NotFound)
+
+		// A request for the same days, ending later in the day, is served from the warm entry.
+		req := &pb.TimePeriodRequest{
+			StartDate: timestamppb.New(start),
+			EndDate:   timestamppb.New(end.Add(15 * time.Hour)),
+		}
+		resp, err := handlers.GetOverallQualityScore(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 90.0, resp.Score)
+		assert.Equal(t, int32(4), calls.Load())
+
+		// Warming again refetches every entry before returning, NotFound ones included.
+		require.NoError(t, handlers.Warm(context.Background(), start, end))
+		assert.Equal(t, int32(8), calls.Load())
+	})
+
+	t.Run("reports cache write failures", func(t *testing.T) {
+		mockScoring := &mocks.MockScoringService{
+			GetOverallScoreFunc: func(ctx context.Context, start, end time.Time) (float64, error) {
+				return 90.0, nil
+			},
+			GetScoresByTicketFunc: func(ctx context.Context, start, end time.Time) ([]service.TicketScores, error) {
+				return nil, nil
+			},
+			GetPeriodOverPeriodScoreChangeFunc: func(ctx context.Context, start, end time.Time) (service.PeriodChange, error) {
+				return service.PeriodChange{}, nil
+			},
+			GetAggregatedCategoryScoresFunc: func(ctx context.Context, start, end time.Time) ([]service.AggregatedCategoryScores, error) {
+				return nil, nil
+			},
+		}
+		failing := &mocks.MockCacher{
+			SetFunc: func(context.Context, string, any, time.Duration) error { return errors.New("connection refused") },
+		}
+		handlers := NewGRPCHandlers(mockScoring, failing, zap.NewNop(), time.Minute)
+
+		err := handlers.Warm(context.Background(), time.Now().AddDate(0, 0, -7), time.Now())
+
+		assert.ErrorContains(t, err, "connection refused")
+	})
+
+	t.Run("reports fetch failures", func(t *testing.T) {
+		mockScoring := &mocks.MockScoringService{
+			GetOverallScoreFunc: func(ctx context.Context, start, end time.Time) (float64, error) {
+				return 0, service.ErrStorageFailure
+			},
+			GetScoresByTicketFunc: func(ctx context.Context, start, end time.Time) ([]service.TicketScores, error) {
+				return nil, nil
+			},
+			GetPeriodOverPeriodScoreChangeFunc: func(ctx context.Context, start, end time.Time) (service.PeriodChange, error) {
+				return service.PeriodChange{}, nil
+			},
+			GetAggregatedCategoryScoresFunc: func(ctx context.Context, start, end time.Time) ([]service.AggregatedCategoryScores, error) {
+				return nil, nil
+			},
+		}
+		handlers := NewGRPCHandlers(mockScoring, cache.NewNoop(), zap.NewNop(), time.Minute)
+
+		err := handlers.Warm(context.Background(), time.Now().AddDate(0, 0, -7), time.Now())
+
+		assert.ErrorIs(t, err, service.ErrStorageFailure)
+	})
+}
+
+func TestRequestDeadlines(t *testing.T) {
+	newHandlers := func(deadlines chan<- time.Duration, opts ...HandlerOption) *GRPCHandlers {
+		mockScoring := &mocks.MockScoringService{
+			GetOverallScoreFunc: func(ctx context.Context, start, end time.Time) (float64, error) {
+				deadline, ok := ctx.Deadline()
+				require.True(t, ok)
+				deadlines <- time.Until(deadline)
+				return 90.0, nil
+			},
+		}
+		return NewGRPCHandlers(mockScoring, cache.NewNoop(), zap.NewNop(), time.Minute, opts...)
+	}
+	req := &pb.TimePeriodRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)),
+	}
+
+	t.Run("client deadline is honoured", func(t *testing.T) {
+		deadlines := make(chan time.Duration, 1)
+		handlers := newHandlers(deadlines, WithTimeout(time.Second))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		_, err := handlers.GetOverallQualityScore(ctx, req)
+
+		require.NoError(t, err)
+		assert.Greater(t, <-deadlines, 50*time.Second)
+	})
+
+	t.Run("configured timeout applies without a client deadline", func(t *testing.T) {
+		deadlines := make(chan time.Duration, 1)
+		handlers := newHandlers(deadlines, WithTimeout(time.Minute), WithMethodTimeout("GetOverallQualityScore", 2*time.Second))
+
+		_, err := handlers.GetOverallQualityScore(context.Background(), req)
+
+		require.NoError(t, err)
+		remaining := <-deadlines
+		assert.LessOrEqual(t, remaining, 2*time.Second)
+		assert.Greater(t, remaining, time.Second)
+	})
+
+	t.Run("default timeout", func(t *testing.T) {
+		deadlines := make(chan time.Duration, 1)
+		handlers := newHandlers(deadlines, WithMethodTimeout("ExportScores", time.Hour))
+
+		_, err := handlers.GetOverallQualityScore(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.LessOrEqual(t, <-deadlines, defaultGRPCTimeout)
+	})
+}
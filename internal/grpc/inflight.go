@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// InflightFetch is a cache key whose value FindAndCache is fetching right now.
+type InflightFetch struct {
+	Key        string
+	StartedAt  time.Time
+	Background bool
+}
+
+// inflightFetches tracks the fetches running inside singleflight, which does not expose
+// its keys, so operators can see what the server is busy computing.
+type inflightFetches struct {
+	mu      sync.Mutex
+	fetches map[*InflightFetch]struct{}
+}
+
+var inflight = &inflightFetches{fetches: make(map[*InflightFetch]struct{})}
+
+// start records a fetch of key and returns the func that removes it.
+func (f *inflightFetches) start(key string, background bool) func() {
+	fetch := &InflightFetch{Key: key, StartedAt: time.Now(), Background: background}
+
+	f.mu.Lock()
+	f.fetches[fetch] = struct{}{}
+	f.mu.Unlock()
+
+	return func() {
+		f.mu.Lock()
+		delete(f.fetches, fetch)
+		f.mu.Unlock()
+	}
+}
+
+// InflightFetches returns the fetches in progress, oldest first.
+func InflightFetches() []InflightFetch {
+	inflight.mu.Lock()
+	out := make([]InflightFetch, 0, len(inflight.fetches))
+	for fetch := range inflight.fetches {
+		out = append(out, *fetch)
+	}
+	inflight.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out
+}
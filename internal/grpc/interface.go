@@ -12,11 +12,16 @@ type Cacher interface {
 	Close() error
 	Get(ctx context.Context, key string, dest any) error
 	Set(ctx context.Context, key string, value any, expiration time.Duration) error
+	// Invalidate deletes keys that begin with one of prefixes (any prefix when empty) and,
+	// when start or end is set, whose normalizeKey window overlaps those days. A zero start
+	// or end leaves that side open. It returns the number of keys deleted.
+	Invalidate(ctx context.Context, prefixes []string, start, end time.Time) (int64, error)
 }
 
 type ScoringService interface {
 	GetOverallScore(ctx context.Context, start, end time.Time) (float64, error)
 	GetScoresByTicket(ctx context.Context, start, end time.Time) ([]service.TicketScores, error)
+	EachScoreByTicket(ctx context.Context, start, end time.Time, fn func(service.TicketCategoryScore) error) error
 	GetPeriodOverPeriodScoreChange(ctx context.Context, start, end time.Time) (service.PeriodChange, error)
 	GetAggregatedCategoryScores(ctx context.Context, start, end time.Time) ([]service.AggregatedCategoryScores, error)
 }
@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	cacheResultHit   = "hit"
+	cacheResultMiss  = "miss"
+	cacheResultError = "error"
+	cacheResultStale = "stale"
+
+	staleReasonSaturated  = "db_saturated"
+	staleReasonFetchError = "fetch_error"
+
+	refreshOutcomeSuccess    = "success"
+	refreshOutcomeFetchError = "fetch_error"
+	refreshOutcomeSetError   = "set_error"
+)
+
+var (
+	cacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "qa_cache_lookups_total",
+		Help: "Cache lookups performed by FindAndCache, by key prefix and result (hit, miss, error, stale).",
+	}, []string{"prefix", "result"})
+
+	singleflightShared = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "qa_cache_singleflight_shared_total",
+		Help: "Fetches whose result was shared with concurrent callers through singleflight.",
+	}, []string{"prefix"})
+
+	staleServed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "qa_cache_stale_served_total",
+		Help: "Expired cache entries served in place of a fetch, by key prefix and reason (db_saturated, fetch_error).",
+	}, []string{"prefix", "reason"})
+
+	backgroundRefreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "qa_cache_background_refresh_total",
+		Help: "Background cache refreshes, by key prefix and outcome (success, fetch_error, set_error).",
+	}, []string{"prefix", "outcome"})
+)
+
+// metricPrefix strips the trailing start/end dates from a cache key so that metric
+// labels stay bounded to the handful of key types instead of every requested window.
+func metricPrefix(key string) string {
+	for range 2 {
+		i := strings.LastIndexByte(key, ':')
+		if i < 0 {
+			return key
+		}
+		key = key[:i]
+	}
+	return key
+}
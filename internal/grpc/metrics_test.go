@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godilite/qa-server/internal/grpc/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// TestMetricPrefix tests that cache keys are reduced to bounded metric labels
+func TestMetricPrefix(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "grpc:overall_quality_score", metricPrefix(normalizeKey(cacheKeyOverallScore, start, end)))
+	assert.Equal(t, "grpc:scores_by_ticket", metricPrefix(normalizeKey(cacheKeyTicketScores, start, end)))
+	assert.Equal(t, "plain", metricPrefix("plain"))
+}
+
+// TestFindAndCacheMetrics tests that lookups are counted by result
+func TestFindAndCacheMetrics(t *testing.T) {
+	const key = "grpc:metrics_test:2025-01-01:2025-01-02"
+	prefix := metricPrefix(key)
+
+	hitsBefore := testutil.ToFloat64(cacheLookups.WithLabelValues(prefix, cacheResultHit))
+	missesBefore := testutil.ToFloat64(cacheLookups.WithLabelValues(prefix, cacheResultMiss))
+
+	var sf singleflight.Group
+	missCache := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			return redis.Nil
+		},
+	}
+	_, err := FindAndCache(context.Background(), missCache, &sf, key, CachePolicy{TTL: time.Minute, Refresh: RefreshAhead{Policy: RefreshAlways}}, zap.NewNop(), func(ctx context.Context) (float64, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+
+	hitCache := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			*dest.(*cacheEntry[float64]) = newCacheEntry(42.0, time.Now(), time.Minute)
+			return nil
+		},
+	}
+	v, err := FindAndCache(context.Background(), hitCache, &sf, key, CachePolicy{TTL: time.Minute, Refresh: RefreshAhead{Policy: RefreshAlways}}, zap.NewNop(), func(ctx context.Context) (float64, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, v)
+
+	assert.Equal(t, missesBefore+1, testutil.ToFloat64(cacheLookups.WithLabelValues(prefix, cacheResultMiss)))
+	assert.Equal(t, hitsBefore+1, testutil.ToFloat64(cacheLookups.WithLabelValues(prefix, cacheResultHit)))
+}
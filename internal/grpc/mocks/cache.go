@@ -9,9 +9,10 @@ import (
 // MockCacher is a mock implementation of the cache interface
 // for testing the handler layer. It uses function-based mocking for flexibility.
 type MockCacher struct {
-	GetFunc   func(ctx context.Context, key string, dest any) error
-	SetFunc   func(ctx context.Context, key string, value any, expiration time.Duration) error
-	CloseFunc func() error
+	GetFunc        func(ctx context.Context, key string, dest any) error
+	SetFunc        func(ctx context.Context, key string, value any, expiration time.Duration) error
+	CloseFunc      func() error
+	InvalidateFunc func(ctx context.Context, prefixes []string, start, end time.Time) (int64, error)
 }
 
 // Get implements the cache interface
@@ -37,3 +38,11 @@ func (m *MockCacher) Close() error {
 	}
 	return nil
 }
+
+// Invalidate implements the cache interface
+func (m *MockCacher) Invalidate(ctx context.Context, prefixes []string, start, end time.Time) (int64, error) {
+	if m.InvalidateFunc != nil {
+		return m.InvalidateFunc(ctx, prefixes, start, end)
+	}
+	return 0, nil
+}
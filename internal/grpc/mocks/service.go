@@ -13,6 +13,7 @@ import (
 type MockScoringService struct {
 	GetOverallScoreFunc                func(ctx context.Context, start, end time.Time) (float64, error)
 	GetScoresByTicketFunc              func(ctx context.Context, start, end time.Time) ([]service.TicketScores, error)
+	EachScoreByTicketFunc              func(ctx context.Context, start, end time.Time, fn func(service.TicketCategoryScore) error) error
 	GetPeriodOverPeriodScoreChangeFunc func(ctx context.Context, start, end time.Time) (service.PeriodChange, error)
 	GetAggregatedCategoryScoresFunc    func(ctx context.Context, start, end time.Time) ([]service.AggregatedCategoryScores, error)
 }
@@ -33,6 +34,14 @@ func (m *MockScoringService) GetScoresByTicket(ctx context.Context, start, end t
 	return nil, errors.New("GetScoresByTicketFunc not implemented")
 }
 
+// EachScoreByTicket implements the ScoringService interface
+func (m *MockScoringService) EachScoreByTicket(ctx context.Context, start, end time.Time, fn func(service.TicketCategoryScore) error) error {
+	if m.EachScoreByTicketFunc != nil {
+		return m.EachScoreByTicketFunc(ctx, start, end, fn)
+	}
+	return errors.New("EachScoreByTicketFunc not implemented")
+}
+
 // GetPeriodOverPeriodScoreChange implements the ScoringService interface
 func (m *MockScoringService) GetPeriodOverPeriodScoreChange(ctx context.Context, start, end time.Time) (service.PeriodChange, error) {
 	if m.GetPeriodOverPeriodScoreChangeFunc != nil {
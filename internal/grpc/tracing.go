@@ -0,0 +1,24 @@
+package grpc
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/godilite/qa-server/internal/grpc")
+
+func windowAttributes(start, end time.Time) trace.SpanStartOption {
+	return trace.WithAttributes(
+		attribute.String("window.start", start.UTC().Format(time.RFC3339)),
+		attribute.String("window.end", end.UTC().Format(time.RFC3339)),
+	)
+}
+
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(otelcodes.Error, err.Error())
+}
@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godilite/qa-server/internal/grpc/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	testTracingOnce  sync.Once
+	testSpanRecorder *tracetest.SpanRecorder
+)
+
+// setupTestTracing installs a recording tracer provider as the global one. The global
+// provider can only be delegated to once per process, so it is shared by all tests.
+func setupTestTracing() (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	testTracingOnce.Do(func() {
+		testSpanRecorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(testSpanRecorder)))
+	})
+	return testSpanRecorder, otel.GetTracerProvider().(*sdktrace.TracerProvider)
+}
+
+// TestFindAndCacheBackgroundRefreshSpan tests that detached refreshes are traced in
+// their own trace, linked back to the request that triggered them
+func TestFindAndCacheBackgroundRefreshSpan(t *testing.T) {
+	recorder, tp := setupTestTracing()
+
+	hitCache := &mocks.MockCacher{
+		GetFunc: func(ctx context.Context, key string, dest any) error {
+			*dest.(*cacheEntry[float64]) = newCacheEntry(1.0, time.Now(), time.Minute)
+			return nil
+		},
+	}
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	var sf singleflight.Group
+	_, err := FindAndCache(ctx, hitCache, &sf, "grpc:trace_test:2025-01-01:2025-01-02", CachePolicy{TTL: time.Minute, Refresh: RefreshAhead{Policy: RefreshAlways}}, zap.NewNop(), func(ctx context.Context) (float64, error) {
+		return 2, nil
+	})
+	require.NoError(t, err)
+	parent.End()
+
+	var find sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "cache.FindAndCache" && s.Parent().SpanID() == parent.SpanContext().SpanID() {
+			find = s
+		}
+	}
+	require.NotNil(t, find)
+
+	// Other tests may leave refreshes running, so match on the link rather than the name alone.
+	var refresh sdktrace.ReadOnlySpan
+	require.Eventually(t, func() bool {
+		for _, s := range recorder.Ended() {
+			if s.Name() != "cache.BackgroundRefresh" {
+				continue
+			}
+			for _, l := range s.Links() {
+				if l.SpanContext.SpanID() == find.SpanContext().SpanID() {
+					refresh = s
+					return true
+				}
+			}
+		}
+		return false
+	}, 3*time.Second, 10*time.Millisecond)
+
+	assert.NotEqual(t, parent.SpanContext().TraceID(), refresh.SpanContext().TraceID())
+	assert.Len(t, refresh.Links(), 1)
+}
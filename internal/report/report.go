@@ -0,0 +1,203 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Format is the output encoding of a report.
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatCSV   Format = "csv"
+)
+
+// ParseFormat validates an output format name. "text" is accepted as an alias for table.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatTable, FormatJSON, FormatCSV:
+		return f, nil
+	case "text":
+		return FormatTable, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q: must be table, json or csv", s)
+	}
+}
+
+// table is the tabular form of a response shared by the table and CSV formats.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// Write renders a TicketScoring response. JSON output uses the same field names as the
+// HTTP gateway; table and CSV output are sorted by category and ticket ID so they are
+// stable across runs.
+func Write(w io.Writer, format Format, msg proto.Message) error {
+	if format == FormatJSON {
+		b, err := protojson.MarshalOptions{
+			Multiline:       true,
+			UseProtoNames:   true,
+			EmitUnpopulated: true,
+		}.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("marshal json: %w", err)
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+
+	t, err := toTable(msg)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(t.header, "\t")))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(t.header); err != nil {
+			return fmt.Errorf("write csv header: %w", err)
+		}
+		if err := cw.WriteAll(t.rows); err != nil {
+			return fmt.Errorf("write csv rows: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func toTable(msg proto.Message) (table, error) {
+	switch m := msg.(type) {
+	case *pb.OverallQualityScoreResponse:
+		return table{
+			header: []string{"score"},
+			rows:   [][]string{{formatFloat(m.GetScore())}},
+		}, nil
+
+	case *pb.PeriodOverPeriodScoreChangeResponse:
+		return table{
+			header: []string{"current_period_score", "previous_period_score", "change_percentage"},
+			rows: [][]string{{
+				formatFloat(m.GetCurrentPeriodScore()),
+				formatFloat(m.GetPreviousPeriodScore()),
+				formatFloat(m.GetChangePercentage()),
+			}},
+		}, nil
+
+	case *pb.AggregatedCategoryScoresResponse:
+		categories := append([]*pb.CategoryScore(nil), m.GetCategoryScores()...)
+		sort.Slice(categories, func(i, j int) bool {
+			return categories[i].GetCategoryName() < categories[j].GetCategoryName()
+		})
+
+		t := table{header: []string{"category", "total_ratings", "overall_category_score", "period", "period_score"}}
+		for _, c := range categories {
+			for _, p := range c.GetPeriodScores() {
+				t.rows = append(t.rows, []string{
+					c.GetCategoryName(),
+					strconv.FormatInt(c.GetTotalRatings(), 10),
+					formatFloat(c.GetOverallCategoryScore()),
+					p.GetPeriod(),
+					formatFloat(p.GetScore()),
+				})
+			}
+		}
+		return t, nil
+
+	case *pb.ScoresByTicketResponse:
+		tickets := append([]*pb.TicketScore(nil), m.GetTicketScores()...)
+		sort.Slice(tickets, func(i, j int) bool {
+			return tickets[i].GetTicketId() < tickets[j].GetTicketId()
+		})
+
+		t := table{header: []string{"ticket_id", "category", "score"}}
+		for _, ticket := range tickets {
+			categories := make([]string, 0, len(ticket.GetCategoryScores()))
+			for c := range ticket.GetCategoryScores() {
+				categories = append(categories, c)
+			}
+			sort.Strings(categories)
+
+			for _, c := range categories {
+				t.rows = append(t.rows, []string{
+					strconv.FormatInt(ticket.GetTicketId(), 10),
+					c,
+					formatFloat(ticket.GetCategoryScores()[c]),
+				})
+			}
+		}
+		return t, nil
+
+	default:
+		return table{}, fmt.Errorf("unsupported response type %T", msg)
+	}
+}
+
+// FilterCategories keeps only the named categories (case-insensitive) in a category or
+// ticket response; tickets left without any matching category are dropped. Responses
+// without per-category data are returned unchanged. The input is not modified.
+func FilterCategories(msg proto.Message, names []string) proto.Message {
+	if len(names) == 0 {
+		return msg
+	}
+
+	keep := make(map[string]bool, len(names))
+	for _, n := range names {
+		keep[strings.ToLower(n)] = true
+	}
+
+	switch m := msg.(type) {
+	case *pb.AggregatedCategoryScoresResponse:
+		out := &pb.AggregatedCategoryScoresResponse{}
+		for _, c := range m.GetCategoryScores() {
+			if keep[strings.ToLower(c.GetCategoryName())] {
+				out.CategoryScores = append(out.CategoryScores, c)
+			}
+		}
+		return out
+
+	case *pb.ScoresByTicketResponse:
+		out := &pb.ScoresByTicketResponse{}
+		for _, t := range m.GetTicketScores() {
+			scores := make(map[string]float64)
+			for c, s := range t.GetCategoryScores() {
+				if keep[strings.ToLower(c)] {
+					scores[c] = s
+				}
+			}
+			if len(scores) > 0 {
+				out.TicketScores = append(out.TicketScores, &pb.TicketScore{
+					TicketId:       t.GetTicketId(),
+					CategoryScores: scores,
+				})
+			}
+		}
+		return out
+
+	default:
+		return msg
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
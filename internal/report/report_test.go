@@ -0,0 +1,133 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func categoryResponse() *pb.AggregatedCategoryScoresResponse {
+	return &pb.AggregatedCategoryScoresResponse{
+		CategoryScores: []*pb.CategoryScore{
+			{
+				CategoryName:         "Tone",
+				TotalRatings:         3,
+				OverallCategoryScore: 66.666,
+				PeriodScores:         []*pb.PeriodScore{{Period: "2025-01-01", Score: 66.666}},
+			},
+			{
+				CategoryName:         "Grammar",
+				TotalRatings:         2,
+				OverallCategoryScore: 50,
+				PeriodScores: []*pb.PeriodScore{
+					{Period: "2025-01-01", Score: 40},
+					{Period: "2025-01-02", Score: 60},
+				},
+			},
+		},
+	}
+}
+
+func ticketResponse() *pb.ScoresByTicketResponse {
+	return &pb.ScoresByTicketResponse{
+		TicketScores: []*pb.TicketScore{
+			{TicketId: 2, CategoryScores: map[string]float64{"Tone": 80}},
+			{TicketId: 1, CategoryScores: map[string]float64{"Tone": 100, "Grammar": 20}},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"table", "JSON", "csv"} {
+		_, err := ParseFormat(s)
+		assert.NoError(t, err, s)
+	}
+
+	f, err := ParseFormat("text")
+	require.NoError(t, err)
+	assert.Equal(t, FormatTable, f)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, Write(&buf, FormatTable, categoryResponse()))
+
+	expected := "" +
+		"CATEGORY  TOTAL_RATINGS  OVERALL_CATEGORY_SCORE  PERIOD      PERIOD_SCORE\n" +
+		"Grammar   2              50.00                   2025-01-01  40.00\n" +
+		"Grammar   2              50.00                   2025-01-02  60.00\n" +
+		"Tone      3              66.67                   2025-01-01  66.67\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, Write(&buf, FormatCSV, ticketResponse()))
+
+	expected := "ticket_id,category,score\n" +
+		"1,Grammar,20.00\n" +
+		"1,Tone,100.00\n" +
+		"2,Tone,80.00\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, Write(&buf, FormatJSON, &pb.PeriodOverPeriodScoreChangeResponse{
+		CurrentPeriodScore: 75,
+	}))
+
+	assert.JSONEq(t, `{"current_period_score": 75, "previous_period_score": 0, "change_percentage": 0}`, buf.String())
+}
+
+func TestWriteScalarResponses(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, Write(&buf, FormatCSV, &pb.OverallQualityScoreResponse{Score: 87.5}))
+	require.NoError(t, Write(&buf, FormatCSV, &pb.PeriodOverPeriodScoreChangeResponse{
+		CurrentPeriodScore:  80,
+		PreviousPeriodScore: 64,
+		ChangePercentage:    25,
+	}))
+
+	expected := "score\n87.50\n" +
+		"current_period_score,previous_period_score,change_percentage\n80.00,64.00,25.00\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestFilterCategories(t *testing.T) {
+	t.Run("categories", func(t *testing.T) {
+		in := categoryResponse()
+
+		out := FilterCategories(in, []string{"tone"}).(*pb.AggregatedCategoryScoresResponse)
+
+		require.Len(t, out.CategoryScores, 1)
+		assert.Equal(t, "Tone", out.CategoryScores[0].CategoryName)
+		assert.Len(t, in.CategoryScores, 2, "input must not be modified")
+	})
+
+	t.Run("tickets", func(t *testing.T) {
+		in := ticketResponse()
+
+		out := FilterCategories(in, []string{"Grammar"}).(*pb.ScoresByTicketResponse)
+
+		require.Len(t, out.TicketScores, 1)
+		assert.Equal(t, int64(1), out.TicketScores[0].TicketId)
+		assert.Equal(t, map[string]float64{"Grammar": 20}, out.TicketScores[0].CategoryScores)
+		assert.Len(t, in.TicketScores[1].CategoryScores, 2, "input must not be modified")
+	})
+
+	t.Run("no filter", func(t *testing.T) {
+		in := ticketResponse()
+		assert.Same(t, in, FilterCategories(in, nil))
+	})
+}
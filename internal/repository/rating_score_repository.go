@@ -7,19 +7,66 @@ import (
 	"time"
 
 	"github.com/godilite/qa-server/internal/repository/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/godilite/qa-server/internal/repository")
+
 type RatingScoreRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	rollups bool
+}
+
+type Option func(*RatingScoreRepository)
+
+// WithRollups answers overall, daily and weekly queries from the daily rollups maintained
+// by a RollupBuilder, reading raw ratings only for the partial days at either end of the
+// window and for ratings not yet rolled up. The rollup tables must exist.
+func WithRollups() Option {
+	return func(r *RatingScoreRepository) {
+		r.rollups = true
+	}
 }
 
-func NewRatingScoreRepository(db *sql.DB) *RatingScoreRepository {
-	return &RatingScoreRepository{db: db}
+// startQuerySpan starts a client span for a single repository query.
+func startQuerySpan(ctx context.Context, op string, start, end time.Time) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "RatingScoreRepository."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "sqlite"),
+			attribute.String("db.operation.name", op),
+			attribute.String("window.start", start.UTC().Format(time.RFC3339)),
+			attribute.String("window.end", end.UTC().Format(time.RFC3339)),
+		))
+}
+
+// endQuerySpan records err, if any, on span and ends it.
+func endQuerySpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+func NewRatingScoreRepository(db *sql.DB, opts ...Option) *RatingScoreRepository {
+	r := &RatingScoreRepository{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // GetOverallRatings fetches weighted score computed entirely in SQL.
-func (s *RatingScoreRepository) GetOverallRatings(ctx context.Context, start, end time.Time) (models.OverallRatingResult, error) {
-	const query = `
+func (s *RatingScoreRepository) GetOverallRatings(ctx context.Context, start, end time.Time) (_ models.OverallRatingResult, err error) {
+	ctx, span := startQuerySpan(ctx, "GetOverallRatings", start, end)
+	span.SetAttributes(attribute.Bool("rollups", s.rollups))
+	defer func() { endQuerySpan(span, err) }()
+
+	query := `
 		SELECT
 			CASE 
 				WHEN SUM(rc.weight) > 0 
@@ -32,10 +79,26 @@ func (s *RatingScoreRepository) GetOverallRatings(ctx context.Context, start, en
 		WHERE r.created_at >= ? AND r.created_at <= ?
 	`
 
+	args := []any{start, end}
+	if s.rollups {
+		query = `
+			WITH parts AS (` + rollupPartsQuery + `)
+			SELECT
+				CASE
+					WHEN SUM(sum_weight) > 0
+					THEN SUM(sum_weighted) * 20.0 / SUM(sum_weight)
+					ELSE 0
+				END AS score,
+				SUM(rating_count) AS count
+			FROM parts
+		`
+		args = rollupPartsArgs("", start, end)
+	}
+
 	var score sql.NullFloat64
 	var count sql.NullInt64
 
-	err := s.db.QueryRowContext(ctx, query, start, end).Scan(&score, &count)
+	err = s.db.QueryRowContext(ctx, query, args...).Scan(&score, &count)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return models.OverallRatingResult{Score: 0, Count: 0}, nil
@@ -60,13 +123,17 @@ func (s *RatingScoreRepository) GetOverallRatings(ctx context.Context, start, en
 }
 
 // GetRatingsInPeriod aggregates ratings by category and daily or weekly period with SQL-computed scores.
-func (s *RatingScoreRepository) GetRatingsInPeriod(ctx context.Context, start, end time.Time, isWeekly bool) ([]models.AggregatedCategoryData, error) {
+func (s *RatingScoreRepository) GetRatingsInPeriod(ctx context.Context, start, end time.Time, isWeekly bool) (_ []models.AggregatedCategoryData, err error) {
+	ctx, span := startQuerySpan(ctx, "GetRatingsInPeriod", start, end)
+	span.SetAttributes(attribute.Bool("aggregation.weekly", isWeekly), attribute.Bool("rollups", s.rollups))
+	defer func() { endQuerySpan(span, err) }()
+
 	periodFormat := "%Y-%m-%d"
 	if isWeekly {
 		periodFormat = "%Y-W%W"
 	}
 
-	const query = `
+	query := `
 		SELECT
 			rc.name AS category,
 			strftime(?, r.created_at) AS period,
@@ -85,7 +152,29 @@ func (s *RatingScoreRepository) GetRatingsInPeriod(ctx context.Context, start, e
 		ORDER BY category, period
 	`
 
-	rows, err := s.db.QueryContext(ctx, query, periodFormat, start, end)
+	args := []any{periodFormat, start, end}
+	if s.rollups {
+		query = `
+			WITH parts AS (` + rollupPartsQuery + `)
+			SELECT
+				category,
+				period,
+				CASE
+					WHEN SUM(sum_weight) > 0
+					THEN SUM(sum_weighted) * 20.0 / SUM(sum_weight)
+					ELSE 0
+				END AS period_score,
+				SUM(sum_weighted) AS total_weighted_rating,
+				SUM(sum_weight) AS total_weight,
+				SUM(rating_count) AS rating_count
+			FROM parts
+			GROUP BY category, period
+			ORDER BY category, period
+		`
+		args = rollupPartsArgs(periodFormat, start, end)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query GetRatingsInPeriod: %w", err)
 	}
@@ -108,6 +197,29 @@ func (s *RatingScoreRepository) GetRatingsInPeriod(ctx context.Context, start, e
 
 // GetScoresByTicket aggregates scores grouped by ticket and category with SQL-computed scores.
 func (s *RatingScoreRepository) GetScoresByTicket(ctx context.Context, start, end time.Time) ([]models.TicketCategoryScore, error) {
+	var results []models.TicketCategoryScore
+	err := s.scoresByTicket(ctx, "GetScoresByTicket", start, end, func(tcs models.TicketCategoryScore) error {
+		results = append(results, tcs)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// EachScoreByTicket calls fn for every row GetScoresByTicket would return, in ticket ID then
+// category order, as they are read, so a large window is never held in memory. The query
+// keeps its connection until the last row has been handled. An error from fn stops the
+// iteration and is returned as is.
+func (s *RatingScoreRepository) EachScoreByTicket(ctx context.Context, start, end time.Time, fn func(models.TicketCategoryScore) error) error {
+	return s.scoresByTicket(ctx, "EachScoreByTicket", start, end, fn)
+}
+
+func (s *RatingScoreRepository) scoresByTicket(ctx context.Context, op string, start, end time.Time, fn func(models.TicketCategoryScore) error) (err error) {
+	ctx, span := startQuerySpan(ctx, op, start, end)
+	defer func() { endQuerySpan(span, err) }()
+
 	const query = `
 		SELECT
 			r.ticket_id,
@@ -126,21 +238,59 @@ func (s *RatingScoreRepository) GetScoresByTicket(ctx context.Context, start, en
 
 	rows, err := s.db.QueryContext(ctx, query, start, end)
 	if err != nil {
-		return nil, fmt.Errorf("query GetScoresByTicket: %w", err)
+		return fmt.Errorf("query %s: %w", op, err)
 	}
 	defer rows.Close()
 
-	var results []models.TicketCategoryScore
 	for rows.Next() {
 		var tcs models.TicketCategoryScore
 		if err := rows.Scan(&tcs.TicketID, &tcs.Category, &tcs.Score); err != nil {
-			return nil, fmt.Errorf("scan GetScoresByTicket row: %w", err)
+			return fmt.Errorf("scan %s row: %w", op, err)
+		}
+		if err := fn(tcs); err != nil {
+			return err
 		}
-		results = append(results, tcs)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate GetScoresByTicket: %w", err)
+		return fmt.Errorf("iterate %s: %w", op, err)
 	}
-	return results, nil
+	return nil
+}
+
+// rollupPartsQuery yields partial sums per category and period: rollup rows for the whole
+// days strictly inside the window, and raw ratings for the first and last day and for any
+// rating past the rollup watermark. Days are the YYYY-MM-DD prefix of created_at, and the
+// day bounds are compared as strings just like the raw window, so the union covers exactly
+// the ratings the raw queries would. The period is empty when no format is given.
+const rollupPartsQuery = `
+	SELECT
+		rc.name AS category,
+		COALESCE(strftime(?1, ro.day), '') AS period,
+		ro.sum_weighted,
+		ro.sum_weight,
+		ro.rating_count
+	FROM rating_rollups_daily AS ro
+	JOIN rating_categories AS rc ON ro.rating_category_id = rc.id
+	WHERE ro.day >= ?4 AND ro.day < ?5
+	UNION ALL
+	SELECT
+		rc.name,
+		COALESCE(strftime(?1, r.created_at), ''),
+		CAST(r.rating AS REAL) * rc.weight,
+		rc.weight,
+		1
+	FROM ratings AS r
+	JOIN rating_categories AS rc ON r.rating_category_id = rc.id
+	WHERE r.created_at >= ?2 AND r.created_at <= ?3
+		AND (r.created_at < ?4 OR r.created_at >= ?5
+			OR r.id > (SELECT last_rating_id FROM rating_rollup_state WHERE id = 1))
+`
+
+// rollupPartsArgs binds rollupPartsQuery: the period format, the window, the first whole
+// day and the last (partial) day.
+func rollupPartsArgs(periodFormat string, start, end time.Time) []any {
+	firstWholeDay := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())
+	lastDay := end.Format(time.DateOnly)
+	return []any{periodFormat, start, end, firstWholeDay.Format(time.DateOnly), lastDay}
 }
@@ -3,6 +3,7 @@ package repository_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/godilite/qa-server/internal/repository"
+	"github.com/godilite/qa-server/internal/repository/models"
 )
 
 func setupTestDB(t *testing.T) *sql.DB {
@@ -128,4 +130,26 @@ func TestRatingScoreRepository_Integration(t *testing.T) {
 		}
 		require.True(t, found, "expected Grammar category for ticket 1001")
 	})
+
+	t.Run("EachScoreByTicket", func(t *testing.T) {
+		want, err := repo.GetScoresByTicket(ctx, start, end)
+		require.NoError(t, err)
+
+		var got []models.TicketCategoryScore
+		err = repo.EachScoreByTicket(ctx, start, end, func(tcs models.TicketCategoryScore) error {
+			got = append(got, tcs)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+
+		stop := errors.New("stop")
+		calls := 0
+		err = repo.EachScoreByTicket(ctx, start, end, func(models.TicketCategoryScore) error {
+			calls++
+			return stop
+		})
+		require.ErrorIs(t, err, stop)
+		require.Equal(t, 1, calls)
+	})
 }
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultRollupBatchSize bounds how many ratings one Refresh transaction folds into the
+// rollups, so catching up on a large backlog never holds the write lock for long.
+const defaultRollupBatchSize = 50_000
+
+// rollupSchema creates the daily rollups and the single-row watermark. A rollup row holds
+// the sums of one category's ratings on one day, where the day is the YYYY-MM-DD prefix of
+// created_at; the watermark is the highest ratings.id folded into the rollups.
+const rollupSchema = `
+	CREATE TABLE IF NOT EXISTS rating_rollups_daily (
+		day TEXT NOT NULL,
+		rating_category_id INTEGER NOT NULL,
+		sum_weighted REAL NOT NULL,
+		sum_weight REAL NOT NULL,
+		rating_count INTEGER NOT NULL,
+		PRIMARY KEY (day, rating_category_id)
+	);
+	CREATE TABLE IF NOT EXISTS rating_rollup_state (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		last_rating_id INTEGER NOT NULL
+	);
+	INSERT OR IGNORE INTO rating_rollup_state (id, last_rating_id) VALUES (1, 0);
+`
+
+type RollupOption func(*RollupBuilder)
+
+// WithRollupBatchSize sets how many ratings are folded in per transaction.
+func WithRollupBatchSize(n int64) RollupOption {
+	return func(b *RollupBuilder) {
+		b.batchSize = n
+	}
+}
+
+func WithRollupLogger(logger *zap.Logger) RollupOption {
+	return func(b *RollupBuilder) {
+		b.logger = logger
+	}
+}
+
+// RollupBuilder maintains the daily rollups read by a RatingScoreRepository created with
+// WithRollups. Ratings are folded in incrementally by id, so it assumes ratings are only
+// ever appended; after rows are updated or deleted, or a category weight changes, call
+// Rebuild.
+type RollupBuilder struct {
+	db        *sql.DB
+	batchSize int64
+	logger    *zap.Logger
+}
+
+func NewRollupBuilder(db *sql.DB, opts ...RollupOption) *RollupBuilder {
+	b := &RollupBuilder{
+		db:        db,
+		batchSize: defaultRollupBatchSize,
+		logger:    zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.batchSize <= 0 {
+		b.batchSize = defaultRollupBatchSize
+	}
+	if b.logger == nil {
+		b.logger = zap.NewNop()
+	}
+	b.logger = b.logger.Named("rollups")
+	return b
+}
+
+// EnsureSchema creates the rollup tables if they do not exist.
+func (b *RollupBuilder) EnsureSchema(ctx context.Context) error {
+	if _, err := b.db.ExecContext(ctx, rollupSchema); err != nil {
+		return fmt.Errorf("create rollup tables: %w", err)
+	}
+	return nil
+}
+
+// Refresh folds ratings added since the last refresh into the rollups and returns how
+// many were folded in.
+func (b *RollupBuilder) Refresh(ctx context.Context) (_ int64, err error) {
+	ctx, span := tracer.Start(ctx, "RollupBuilder.Refresh")
+	defer func() { endQuerySpan(span, err) }()
+
+	var total int64
+	for {
+		n, err := b.refreshBatch(ctx)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, nil
+		}
+	}
+}
+
+// refreshBatch folds in at most batchSize ratings past the watermark and advances it, in
+// one transaction so readers see either none or all of the batch.
+func (b *RollupBuilder) refreshBatch(ctx context.Context) (_ int64, err error) {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin rollup refresh: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var from int64
+	if err := tx.QueryRowContext(ctx, `SELECT last_rating_id FROM rating_rollup_state WHERE id = 1`).Scan(&from); err != nil {
+		return 0, fmt.Errorf("read rollup watermark: %w", err)
+	}
+
+	var to sql.NullInt64
+	var count int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT MAX(id), COUNT(*) FROM (
+			SELECT id FROM ratings WHERE id > ? ORDER BY id LIMIT ?
+		)`, from, b.batchSize).Scan(&to, &count)
+	if err != nil {
+		return 0, fmt.Errorf("find ratings to roll up: %w", err)
+	}
+	if !to.Valid {
+		return 0, tx.Rollback()
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO rating_rollups_daily (day, rating_category_id, sum_weighted, sum_weight, rating_count)
+		SELECT
+			substr(r.created_at, 1, 10) AS day,
+			r.rating_category_id,
+			SUM(CAST(r.rating AS REAL) * rc.weight),
+			SUM(rc.weight),
+			COUNT(r.id)
+		FROM ratings AS r
+		JOIN rating_categories AS rc ON r.rating_category_id = rc.id
+		WHERE r.id > ? AND r.id <= ?
+		GROUP BY day, r.rating_category_id
+		ON CONFLICT (day, rating_category_id) DO UPDATE SET
+			sum_weighted = sum_weighted + excluded.sum_weighted,
+			sum_weight = sum_weight + excluded.sum_weight,
+			rating_count = rating_count + excluded.rating_count
+	`, from, to.Int64)
+	if err != nil {
+		return 0, fmt.Errorf("update rollups: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE rating_rollup_state SET last_rating_id = ? WHERE id = 1`, to.Int64); err != nil {
+		return 0, fmt.Errorf("advance rollup watermark: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit rollup refresh: %w", err)
+	}
+
+	b.logger.Debug("rolled up ratings", zap.Int64("from_id", from), zap.Int64("to_id", to.Int64), zap.Int64("count", count))
+	return count, nil
+}
+
+// Rebuild discards the rollups and folds in every rating again. Until it finishes, queries
+// read the missing days from the raw ratings and stay correct, only slower.
+func (b *RollupBuilder) Rebuild(ctx context.Context) (int64, error) {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin rollup rebuild: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM rating_rollups_daily`); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("clear rollups: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE rating_rollup_state SET last_rating_id = 0 WHERE id = 1`); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("reset rollup watermark: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit rollup rebuild: %w", err)
+	}
+	return b.Refresh(ctx)
+}
+
+// Run refreshes the rollups immediately and then every interval until ctx is cancelled.
+func (b *RollupBuilder) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := b.Refresh(ctx)
+		switch {
+		case err != nil && !errors.Is(err, context.Canceled):
+			b.logger.Warn("rollup refresh failed", zap.Error(err))
+		case n > 0:
+			b.logger.Info("rollups refreshed", zap.Int64("ratings", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/godilite/qa-server/internal/repository"
+	"github.com/godilite/qa-server/internal/repository/models"
+)
+
+// seedSpreadRatings inserts n ratings spread over days starting at base, several per day
+// at different hours, cycling through the seeded categories.
+func seedSpreadRatings(t *testing.T, db *sql.DB, base time.Time, days, perDay int) {
+	t.Helper()
+
+	for d := 0; d < days; d++ {
+		for i := 0; i < perDay; i++ {
+			ts := base.AddDate(0, 0, d).Add(time.Duration(i*5) * time.Hour).Format(time.RFC3339)
+			_, err := db.Exec(`INSERT INTO ratings (ticket_id, rating, rating_category_id, created_at) VALUES (?, ?, ?, ?)`,
+				2000+d, (d+i)%5+1, i%3+1, ts)
+			require.NoError(t, err)
+		}
+	}
+}
+
+func assertSameRatings(t *testing.T, raw, rolled *repository.RatingScoreRepository, start, end time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	window := fmt.Sprintf("%s..%s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+
+	wantOverall, err := raw.GetOverallRatings(ctx, start, end)
+	require.NoError(t, err)
+	gotOverall, err := rolled.GetOverallRatings(ctx, start, end)
+	require.NoError(t, err)
+	assert.Equal(t, wantOverall.Count, gotOverall.Count, window)
+	assert.InDelta(t, wantOverall.Score, gotOverall.Score, 1e-9, window)
+
+	for _, weekly := range []bool{false, true} {
+		want, err := raw.GetRatingsInPeriod(ctx, start, end, weekly)
+		require.NoError(t, err)
+		got, err := rolled.GetRatingsInPeriod(ctx, start, end, weekly)
+		require.NoError(t, err)
+
+		require.Len(t, got, len(want), window)
+		for i := range want {
+			assertSamePeriod(t, want[i], got[i], window)
+		}
+	}
+}
+
+func assertSamePeriod(t *testing.T, want, got models.AggregatedCategoryData, window string) {
+	t.Helper()
+	assert.Equal(t, want.Category, got.Category, window)
+	assert.Equal(t, want.Period, got.Period, window)
+	assert.Equal(t, want.EvaluationCount, got.EvaluationCount, window)
+	assert.InDelta(t, want.PeriodScore, got.PeriodScore, 1e-9, window)
+	assert.InDelta(t, want.TotalWeightedEvaluation, got.TotalWeightedEvaluation, 1e-9, window)
+	assert.InDelta(t, want.TotalWeight, got.TotalWeight, 1e-9, window)
+}
+
+func TestRollups_MatchRawQueries(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	seedTestData(t, db, base)
+	seedSpreadRatings(t, db, base, 20, 5)
+
+	builder := repository.NewRollupBuilder(db, repository.WithRollupBatchSize(7))
+	require.NoError(t, builder.EnsureSchema(ctx))
+
+	raw := repository.NewRatingScoreRepository(db)
+	rolled := repository.NewRatingScoreRepository(db, repository.WithRollups())
+
+	windows := [][2]time.Time{
+		{base, base.AddDate(0, 0, 19)},
+		{base.Add(7 * time.Hour), base.AddDate(0, 0, 10).Add(3 * time.Hour)},
+		{base.AddDate(0, 0, 2), base.AddDate(0, 0, 3)},
+		{base.Add(2 * time.Hour), base.Add(20 * time.Hour)},
+		{base.AddDate(0, 0, -5), base.AddDate(0, 1, 0)},
+	}
+
+	// Before the first refresh everything is read from raw ratings.
+	for _, w := range windows {
+		assertSameRatings(t, raw, rolled, w[0], w[1])
+	}
+
+	n, err := builder.Refresh(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(105), n)
+	for _, w := range windows {
+		assertSameRatings(t, raw, rolled, w[0], w[1])
+	}
+
+	// Ratings added after the refresh are read raw until the next one.
+	seedSpreadRatings(t, db, base.AddDate(0, 0, 4), 3, 2)
+	for _, w := range windows {
+		assertSameRatings(t, raw, rolled, w[0], w[1])
+	}
+
+	n, err = builder.Refresh(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	for _, w := range windows {
+		assertSameRatings(t, raw, rolled, w[0], w[1])
+	}
+
+	n, err = builder.Refresh(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "nothing left to roll up")
+}
+
+func TestRollups_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	seedTestData(t, db, base)
+	seedSpreadRatings(t, db, base, 10, 3)
+
+	builder := repository.NewRollupBuilder(db)
+	require.NoError(t, builder.EnsureSchema(ctx))
+	require.NoError(t, builder.EnsureSchema(ctx), "schema creation is idempotent")
+	_, err := builder.Refresh(ctx)
+	require.NoError(t, err)
+
+	// Weight changes are not picked up incrementally.
+	_, err = db.Exec(`UPDATE rating_categories SET weight = 2.0 WHERE name = 'Grammar'`)
+	require.NoError(t, err)
+
+	raw := repository.NewRatingScoreRepository(db)
+	rolled := repository.NewRatingScoreRepository(db, repository.WithRollups())
+	start, end := base, base.AddDate(0, 0, 9)
+
+	n, err := builder.Rebuild(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(35), n)
+	assertSameRatings(t, raw, rolled, start, end)
+}
+
+func TestRollups_ReadsRollupsForWholeDays(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	seedTestData(t, db, base)
+	seedSpreadRatings(t, db, base, 5, 2)
+
+	builder := repository.NewRollupBuilder(db)
+	require.NoError(t, builder.EnsureSchema(ctx))
+	_, err := builder.Refresh(ctx)
+	require.NoError(t, err)
+
+	// Removing the raw ratings of whole days leaves the answer unchanged, because those
+	// days are read from the rollups; removing an edge day's ratings changes it.
+	rolled := repository.NewRatingScoreRepository(db, repository.WithRollups())
+	start, end := base.Add(time.Hour), base.AddDate(0, 0, 4).Add(time.Hour)
+	before, err := rolled.GetOverallRatings(ctx, start, end)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`DELETE FROM ratings WHERE substr(created_at, 1, 10) IN ('2025-03-02', '2025-03-03')`)
+	require.NoError(t, err)
+	after, err := rolled.GetOverallRatings(ctx, start, end)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	_, err = db.Exec(`DELETE FROM ratings WHERE substr(created_at, 1, 10) = '2025-03-01'`)
+	require.NoError(t, err)
+	after, err = rolled.GetOverallRatings(ctx, start, end)
+	require.NoError(t, err)
+	assert.Less(t, after.Count, before.Count)
+}
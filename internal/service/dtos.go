@@ -17,6 +17,13 @@ type TicketScores struct {
 	CategoryScores map[string]float64
 }
 
+// TicketCategoryScore is the score of a single category of a single ticket.
+type TicketCategoryScore struct {
+	TicketID int64
+	Category string
+	Score    float64
+}
+
 type PeriodChange struct {
 	CurrentPeriodScore  float64
 	PreviousPeriodScore float64
@@ -12,4 +12,5 @@ type RatingScoreRepository interface {
 	GetOverallRatings(ctx context.Context, start, end time.Time) (models.OverallRatingResult, error)
 	GetRatingsInPeriod(ctx context.Context, start, end time.Time, isWeekly bool) ([]models.AggregatedCategoryData, error)
 	GetScoresByTicket(ctx context.Context, start, end time.Time) ([]models.TicketCategoryScore, error)
+	EachScoreByTicket(ctx context.Context, start, end time.Time, fn func(models.TicketCategoryScore) error) error
 }
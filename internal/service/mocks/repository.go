@@ -14,6 +14,7 @@ type MockRatingScoreRepository struct {
 	GetOverallRatingsFunc  func(ctx context.Context, start, end time.Time) (models.OverallRatingResult, error)
 	GetRatingsInPeriodFunc func(ctx context.Context, start, end time.Time, isWeekly bool) ([]models.AggregatedCategoryData, error)
 	GetScoresByTicketFunc  func(ctx context.Context, start, end time.Time) ([]models.TicketCategoryScore, error)
+	EachScoreByTicketFunc  func(ctx context.Context, start, end time.Time, fn func(models.TicketCategoryScore) error) error
 }
 
 // GetOverallRatings implements the RatingScoreRepository interface
@@ -39,3 +40,11 @@ func (m *MockRatingScoreRepository) GetScoresByTicket(ctx context.Context, start
 	}
 	return nil, errors.New("GetScoresByTicketFunc not implemented")
 }
+
+// EachScoreByTicket implements the RatingScoreRepository interface
+func (m *MockRatingScoreRepository) EachScoreByTicket(ctx context.Context, start, end time.Time, fn func(models.TicketCategoryScore) error) error {
+	if m.EachScoreByTicketFunc != nil {
+		return m.EachScoreByTicketFunc(ctx, start, end, fn)
+	}
+	return errors.New("EachScoreByTicketFunc not implemented")
+}
@@ -4,24 +4,88 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"time"
 
+	"github.com/godilite/qa-server/internal/repository/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// Default DBBudget: 1s plus 20ms per day of the window, at most 15s, so a year-long
+// window gets about 8s.
 const (
-	dbTimeout = 1 * time.Second
+	defaultDBTimeout       = 1 * time.Second
+	defaultDBTimeoutPerDay = 20 * time.Millisecond
+	defaultDBTimeoutMax    = 15 * time.Second
 )
 
+var tracer = otel.Tracer("github.com/godilite/qa-server/internal/service")
+
+// startSpan starts a span for a ScoringService method tagged with the requested window.
+func startSpan(ctx context.Context, name string, start, end time.Time) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "ScoringService."+name, trace.WithAttributes(
+		attribute.String("window.start", start.UTC().Format(time.RFC3339)),
+		attribute.String("window.end", end.UTC().Format(time.RFC3339)),
+	))
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+// DBBudget sizes the timeout of each repository query from the window it covers: Base plus
+// PerDay for every started day, capped at Max. A zero Max leaves it uncapped. The caller's
+// deadline still applies when it is sooner.
+type DBBudget struct {
+	Base   time.Duration
+	PerDay time.Duration
+	Max    time.Duration
+}
+
+// For returns the timeout for a query over [start, end].
+func (b DBBudget) For(start, end time.Time) time.Duration {
+	timeout := b.Base
+	if days := end.Sub(start).Hours() / 24; days > 0 {
+		timeout += time.Duration(math.Ceil(days)) * b.PerDay
+	}
+	if b.Max > 0 && timeout > b.Max {
+		timeout = b.Max
+	}
+	return timeout
+}
+
 // ScoringService handles rating aggregation and scoring.
 type ScoringService struct {
-	storage RatingScoreRepository
-	logger  *zap.Logger
+	storage  RatingScoreRepository
+	logger   *zap.Logger
+	dbBudget DBBudget
+}
+
+// Option configures optional ScoringService behaviour.
+type Option func(*ScoringService)
+
+// WithDBBudget sets how long repository queries may run. A non-positive Base keeps the
+// default budget.
+func WithDBBudget(budget DBBudget) Option {
+	return func(s *ScoringService) {
+		if budget.Base > 0 {
+			s.dbBudget = budget
+		}
+	}
 }
 
 // NewScoringService creates a new ScoringService instance.
-func NewScoringService(storage RatingScoreRepository, logger *zap.Logger) *ScoringService {
+func NewScoringService(storage RatingScoreRepository, logger *zap.Logger, opts ...Option) *ScoringService {
 	if storage == nil {
 		panic("storage must not be nil")
 	}
@@ -29,10 +93,19 @@ func NewScoringService(storage RatingScoreRepository, logger *zap.Logger) *Scori
 		l, _ := zap.NewProduction()
 		logger = l
 	}
-	return &ScoringService{
+	s := &ScoringService{
 		storage: storage,
 		logger:  logger,
+		dbBudget: DBBudget{
+			Base:   defaultDBTimeout,
+			PerDay: defaultDBTimeoutPerDay,
+			Max:    defaultDBTimeoutMax,
+		},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 var (
@@ -58,9 +131,11 @@ func isWeeklyAggregation(start, end time.Time) bool {
 }
 
 // GetOverallScore returns the overall weighted score for the requested window.
-func (s *ScoringService) GetOverallScore(ctx context.Context, start, end time.Time) (float64, error) {
+func (s *ScoringService) GetOverallScore(ctx context.Context, start, end time.Time) (_ float64, err error) {
+	ctx, span := startSpan(ctx, "GetOverallScore", start, end)
+	defer func() { endSpan(span, err) }()
 
-	dbCtx, cancel := context.WithTimeout(ctx, dbTimeout)
+	dbCtx, cancel := context.WithTimeout(ctx, s.dbBudget.For(start, end))
 	defer cancel()
 
 	result, err := s.storage.GetOverallRatings(dbCtx, start, end)
@@ -81,9 +156,11 @@ func (s *ScoringService) GetOverallScore(ctx context.Context, start, end time.Ti
 }
 
 // GetAggregatedCategoryScores returns per-category (daily or weekly) aggregates.
-func (s *ScoringService) GetAggregatedCategoryScores(ctx context.Context, start, end time.Time) ([]AggregatedCategoryScores, error) {
+func (s *ScoringService) GetAggregatedCategoryScores(ctx context.Context, start, end time.Time) (_ []AggregatedCategoryScores, err error) {
+	ctx, span := startSpan(ctx, "GetAggregatedCategoryScores", start, end)
+	defer func() { endSpan(span, err) }()
 
-	dbCtx, cancel := context.WithTimeout(ctx, dbTimeout)
+	dbCtx, cancel := context.WithTimeout(ctx, s.dbBudget.For(start, end))
 	defer cancel()
 
 	weekly := isWeeklyAggregation(start, end)
@@ -138,8 +215,11 @@ func (s *ScoringService) GetAggregatedCategoryScores(ctx context.Context, start,
 }
 
 // GetScoresByTicket pivots pre-aggregated per-ticket rows into TicketScores.
-func (s *ScoringService) GetScoresByTicket(ctx context.Context, start, end time.Time) ([]TicketScores, error) {
-	dbCtx, cancel := context.WithTimeout(ctx, dbTimeout)
+func (s *ScoringService) GetScoresByTicket(ctx context.Context, start, end time.Time) (_ []TicketScores, err error) {
+	ctx, span := startSpan(ctx, "GetScoresByTicket", start, end)
+	defer func() { endSpan(span, err) }()
+
+	dbCtx, cancel := context.WithTimeout(ctx, s.dbBudget.For(start, end))
 	defer cancel()
 
 	rows, err := s.storage.GetScoresByTicket(dbCtx, start, end)
@@ -170,8 +250,38 @@ func (s *ScoringService) GetScoresByTicket(ctx context.Context, start, end time.
 	return out, nil
 }
 
+// EachScoreByTicket calls fn for every ticket and category score in the window, in ticket ID
+// then category order, as rows are read, so the window is never held in memory. Unlike the
+// other queries it is bounded only by ctx, since fn may be writing to a slow client. An error
+// from fn is returned as is; an empty window returns ErrNoRatings.
+func (s *ScoringService) EachScoreByTicket(ctx context.Context, start, end time.Time, fn func(TicketCategoryScore) error) (err error) {
+	ctx, span := startSpan(ctx, "EachScoreByTicket", start, end)
+	defer func() { endSpan(span, err) }()
+
+	rows := 0
+	var fnErr error
+	err = s.storage.EachScoreByTicket(ctx, start, end, func(r models.TicketCategoryScore) error {
+		rows++
+		fnErr = fn(TicketCategoryScore{TicketID: r.TicketID, Category: r.Category, Score: r.Score})
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		s.logger.Error("failed to stream scores by ticket", zap.Error(err))
+		return fmt.Errorf("stream scores by ticket: %w", err)
+	}
+	if rows == 0 {
+		return ErrNoRatings
+	}
+	return nil
+}
+
 // GetPeriodOverPeriodScoreChange calculates the score change vs the previous period.
-func (s *ScoringService) GetPeriodOverPeriodScoreChange(ctx context.Context, start, end time.Time) (PeriodChange, error) {
+func (s *ScoringService) GetPeriodOverPeriodScoreChange(ctx context.Context, start, end time.Time) (_ PeriodChange, err error) {
+	ctx, span := startSpan(ctx, "GetPeriodOverPeriodScoreChange", start, end)
+	defer func() { endSpan(span, err) }()
 
 	currentScore, err := s.GetOverallScore(ctx, start, end)
 	if err != nil {
@@ -189,6 +189,87 @@ func TestGetAggregatedCategoryScores(t *testing.T) {
 	})
 }
 
+// TestEachScoreByTicket tests streaming ticket scores
+func TestEachScoreByTicket(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	rows := func(rows ...models.TicketCategoryScore) func(context.Context, time.Time, time.Time, func(models.TicketCategoryScore) error) error {
+		return func(ctx context.Context, s, e time.Time, fn func(models.TicketCategoryScore) error) error {
+			for _, r := range rows {
+				if err := fn(r); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	t.Run("rows in order", func(t *testing.T) {
+		mockRepo := &mocks.MockRatingScoreRepository{
+			EachScoreByTicketFunc: rows(
+				models.TicketCategoryScore{TicketID: 101, Category: "Grammar", Score: 92.0},
+				models.TicketCategoryScore{TicketID: 101, Category: "Tone", Score: 85.0},
+				models.TicketCategoryScore{TicketID: 102, Category: "GDPR", Score: 95.0},
+			),
+		}
+
+		var got []TicketCategoryScore
+		err := NewScoringService(mockRepo, logger).EachScoreByTicket(ctx, start, end, func(r TicketCategoryScore) error {
+			got = append(got, r)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []TicketCategoryScore{
+			{TicketID: 101, Category: "Grammar", Score: 92.0},
+			{TicketID: 101, Category: "Tone", Score: 85.0},
+			{TicketID: 102, Category: "GDPR", Score: 95.0},
+		}, got)
+	})
+
+	t.Run("no tickets found", func(t *testing.T) {
+		mockRepo := &mocks.MockRatingScoreRepository{EachScoreByTicketFunc: rows()}
+
+		err := NewScoringService(mockRepo, logger).EachScoreByTicket(ctx, start, end, func(TicketCategoryScore) error {
+			t.Fatal("unexpected row")
+			return nil
+		})
+
+		assert.ErrorIs(t, err, ErrNoRatings)
+	})
+
+	t.Run("callback error is returned as is", func(t *testing.T) {
+		mockRepo := &mocks.MockRatingScoreRepository{
+			EachScoreByTicketFunc: rows(models.TicketCategoryScore{TicketID: 101, Category: "Tone", Score: 85.0}),
+		}
+		writeErr := errors.New("client went away")
+
+		err := NewScoringService(mockRepo, logger).EachScoreByTicket(ctx, start, end, func(TicketCategoryScore) error {
+			return writeErr
+		})
+
+		assert.Equal(t, writeErr, err)
+	})
+
+	t.Run("storage failure", func(t *testing.T) {
+		mockRepo := &mocks.MockRatingScoreRepository{
+			EachScoreByTicketFunc: func(ctx context.Context, s, e time.Time, fn func(models.TicketCategoryScore) error) error {
+				return errors.New("connection lost")
+			},
+		}
+
+		err := NewScoringService(mockRepo, logger).EachScoreByTicket(ctx, start, end, func(TicketCategoryScore) error {
+			return nil
+		})
+
+		assert.ErrorContains(t, err, "stream scores by ticket")
+		assert.ErrorContains(t, err, "connection lost")
+	})
+}
+
 // TestGetScoresByTicket tests ticket score pivoting
 func TestGetScoresByTicket(t *testing.T) {
 	logger := zap.NewNop()
@@ -459,3 +540,55 @@ func TestIsWeeklyAggregation(t *testing.T) {
 		assert.True(t, result)
 	})
 }
+
+func TestDBBudget(t *testing.T) {
+	budget := DBBudget{Base: time.Second, PerDay: 20 * time.Millisecond, Max: 5 * time.Second}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		end      time.Time
+		expected time.Duration
+	}{
+		{"empty window", start, time.Second},
+		{"partial day counts as a day", start.Add(time.Hour), time.Second + 20*time.Millisecond},
+		{"one month", start.AddDate(0, 1, 0), time.Second + 31*20*time.Millisecond},
+		{"capped", start.AddDate(1, 0, 0), 5 * time.Second},
+		{"end before start", start.Add(-time.Hour), time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, budget.For(start, tt.end))
+		})
+	}
+
+	t.Run("zero max is uncapped", func(t *testing.T) {
+		budget := DBBudget{Base: time.Second, PerDay: time.Second}
+		assert.Equal(t, 366*time.Second, budget.For(start, start.AddDate(1, 0, 0)))
+	})
+}
+
+func TestDBTimeoutScalesWithWindow(t *testing.T) {
+	var remaining time.Duration
+	mockRepo := &mocks.MockRatingScoreRepository{
+		GetOverallRatingsFunc: func(ctx context.Context, s, e time.Time) (models.OverallRatingResult, error) {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			remaining = time.Until(deadline)
+			return models.OverallRatingResult{Score: 80, Count: 1}, nil
+		},
+	}
+	service := NewScoringService(mockRepo, zap.NewNop(),
+		WithDBBudget(DBBudget{Base: time.Second, PerDay: 100 * time.Millisecond, Max: time.Minute}))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.GetOverallScore(context.Background(), start, start.AddDate(1, 0, 0))
+	assert.NoError(t, err)
+	assert.Greater(t, remaining, 30*time.Second, "a year-long window gets 1s + 366 * 100ms")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = service.GetOverallScore(ctx, start, start.AddDate(1, 0, 0))
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, remaining, 2*time.Second, "a sooner caller deadline still applies")
+}
@@ -0,0 +1,112 @@
+package timeexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse resolves a date expression relative to now. All results are in UTC and,
+// except for "now" and RFC 3339 timestamps, truncated to midnight.
+//
+// Supported expressions:
+//
+//	2025-01-31, 2025-01-31T10:00:00Z  absolute date or RFC 3339 timestamp
+//	now, today, yesterday, tomorrow
+//	last-week, last-month, last-quarter, last-year  same day one period ago
+//	start-of-week, start-of-month, start-of-quarter, start-of-year  (weeks start on Monday)
+//	-7d, +2w, -3m, -1y, 7d-ago  offsets from today in days, weeks, months or years
+func Parse(expr string, now time.Time) (time.Time, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	now = now.UTC()
+	today := startOfDay(now)
+
+	if expr == "" {
+		return time.Time{}, fmt.Errorf("empty date expression")
+	}
+
+	if t, err := time.Parse(time.DateOnly, expr); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(expr)); err == nil {
+		return t.UTC(), nil
+	}
+
+	switch expr {
+	case "now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "last-week":
+		return today.AddDate(0, 0, -7), nil
+	case "last-month":
+		return today.AddDate(0, -1, 0), nil
+	case "last-quarter":
+		return today.AddDate(0, -3, 0), nil
+	case "last-year":
+		return today.AddDate(-1, 0, 0), nil
+	case "start-of-week":
+		offset := (int(today.Weekday()) + 6) % 7
+		return today.AddDate(0, 0, -offset), nil
+	case "start-of-month":
+		return time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	case "start-of-quarter":
+		month := time.Month((int(today.Month())-1)/3*3 + 1)
+		return time.Date(today.Year(), month, 1, 0, 0, 0, 0, time.UTC), nil
+	case "start-of-year":
+		return time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+
+	if t, ok := parseOffset(expr, today); ok {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized date expression %q", expr)
+}
+
+// parseOffset handles [+-]N{d,w,m,y} and N{d,w,m,y}-ago.
+func parseOffset(expr string, today time.Time) (time.Time, bool) {
+	sign := 1
+	switch {
+	case strings.HasSuffix(expr, "-ago"):
+		expr = strings.TrimSuffix(expr, "-ago")
+		sign = -1
+	case strings.HasPrefix(expr, "-"):
+		expr = expr[1:]
+		sign = -1
+	case strings.HasPrefix(expr, "+"):
+		expr = expr[1:]
+	default:
+		return time.Time{}, false
+	}
+
+	if len(expr) < 2 {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(expr[:len(expr)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	n *= sign
+
+	switch expr[len(expr)-1] {
+	case 'd':
+		return today.AddDate(0, 0, n), true
+	case 'w':
+		return today.AddDate(0, 0, 7*n), true
+	case 'm':
+		return today.AddDate(0, n, 0), true
+	case 'y':
+		return today.AddDate(n, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
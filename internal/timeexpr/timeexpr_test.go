@@ -0,0 +1,72 @@
+package timeexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 5, 14, 15, 30, 0, 0, time.UTC)
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"2025-01-01", date(2025, 1, 1)},
+		{"2025-01-01T10:00:00Z", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"2025-01-01T10:00:00+02:00", time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)},
+		{"now", now},
+		{"today", date(2025, 5, 14)},
+		{"Yesterday", date(2025, 5, 13)},
+		{"tomorrow", date(2025, 5, 15)},
+		{"last-week", date(2025, 5, 7)},
+		{"last-month", date(2025, 4, 14)},
+		{"last-quarter", date(2025, 2, 14)},
+		{"last-year", date(2024, 5, 14)},
+		{"start-of-week", date(2025, 5, 12)},
+		{"start-of-month", date(2025, 5, 1)},
+		{"start-of-quarter", date(2025, 4, 1)},
+		{"start-of-year", date(2025, 1, 1)},
+		{"-7d", date(2025, 5, 7)},
+		{"7d-ago", date(2025, 5, 7)},
+		{"+2w", date(2025, 5, 28)},
+		{"-3m", date(2025, 2, 14)},
+		{"-1y", date(2024, 5, 14)},
+		{" -0d ", date(2025, 5, 14)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Parse(tt.expr, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseStartOfWeekOnSunday(t *testing.T) {
+	sunday := time.Date(2025, 5, 18, 9, 0, 0, 0, time.UTC)
+
+	got, err := Parse("start-of-week", sunday)
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC), got)
+}
+
+func TestParseInvalid(t *testing.T) {
+	now := time.Date(2025, 5, 14, 0, 0, 0, 0, time.UTC)
+
+	for _, expr := range []string{"", "last-decade", "-7", "-xd", "7q-ago", "2025-13-01", "+-1d"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := Parse(expr, now)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultInvalidationChannel is the Redis pub/sub channel replicas share by default.
+const DefaultInvalidationChannel = "qa:cache:invalidate"
+
+// Invalidation tells other replicas which keys to drop from their local tier: the listed
+// keys, plus those selected by InvalidationMatches(Prefixes, Start, End) when any of those
+// is set.
+type Invalidation struct {
+	Keys     []string  `json:"keys,omitempty"`
+	Prefixes []string  `json:"prefixes,omitempty"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+// InvalidationBus broadcasts invalidations between replicas.
+type InvalidationBus interface {
+	Publish(ctx context.Context, inv Invalidation) error
+	// Subscribe calls handler for every invalidation published by other replicas until
+	// the returned function is called.
+	Subscribe(ctx context.Context, handler func(Invalidation)) (unsubscribe func() error, err error)
+}
+
+// RedisBus is an InvalidationBus over Redis pub/sub. Delivery is at-most-once: messages
+// published while a subscriber is reconnecting are lost, so local entries must also expire.
+type RedisBus struct {
+	client  redis.UniversalClient
+	channel string
+	origin  string
+}
+
+type busMessage struct {
+	Origin string `json:"origin"`
+	Invalidation
+}
+
+// NewRedisBus creates a bus on the given channel using c's connection.
+func NewRedisBus(c *Cache, channel string) *RedisBus {
+	if channel == "" {
+		channel = DefaultInvalidationChannel
+	}
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return &RedisBus{client: c.client, channel: channel, origin: hex.EncodeToString(id)}
+}
+
+func (b *RedisBus) Publish(ctx context.Context, inv Invalidation) error {
+	data, err := json.Marshal(busMessage{Origin: b.origin, Invalidation: inv})
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.channel, data).Err()
+}
+
+func (b *RedisBus) Subscribe(ctx context.Context, handler func(Invalidation)) (func() error, error) {
+	ps := b.client.Subscribe(ctx, b.channel)
+	if _, err := ps.Receive(ctx); err != nil {
+		_ = ps.Close()
+		return nil, fmt.Errorf("subscribe %s: %w", b.channel, err)
+	}
+
+	go func() {
+		for msg := range ps.Channel() {
+			var m busMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil || m.Origin == b.origin {
+				continue
+			}
+			handler(m.Invalidation)
+		}
+	}()
+
+	return ps.Close, nil
+}
@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Backend is implemented by every cache in this package and is what tiers wrap.
+type Backend interface {
+	Get(ctx context.Context, key string, dest any) error
+	Set(ctx context.Context, key string, value any, expiration time.Duration) error
+	Invalidate(ctx context.Context, prefixes []string, start, end time.Time) (int64, error)
+	Close() error
+}
+
+// TTLGetter is implemented by backends that can report how long a key has left to live
+// along with its value, so a tier in front of them never keeps it longer. The TTL is
+// negative when the key does not expire.
+type TTLGetter interface {
+	GetWithTTL(ctx context.Context, key string, dest any) (time.Duration, error)
+}
+
+// getWithTTL reads key from b along with its remaining TTL, which is negative when b does
+// not report one.
+func getWithTTL(ctx context.Context, b Backend, key string, dest any) (time.Duration, error) {
+	if g, ok := b.(TTLGetter); ok {
+		return g.GetWithTTL(ctx, key, dest)
+	}
+	return -1, b.Get(ctx, key, dest)
+}
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec IDs. Every stored value starts with a header byte holding the ID of the codec that
+// wrote it, with flagZstd set when the payload is compressed, so entries remain readable
+// after the configured codec or threshold changes. Values written before headers existed
+// are JSON and are recognised by their first byte, which is never a valid header.
+const (
+	CodecIDJSON     byte = 1
+	CodecIDMsgpack  byte = 2
+	CodecIDProtobuf byte = 3
+
+	flagZstd byte = 0x80
+)
+
+// ErrUnsupportedValue is returned by a Codec that cannot encode a value. The cache then
+// stores the value with msgpack instead.
+var ErrUnsupportedValue = errors.New("cache: value not supported by codec")
+
+// Codec serializes cache values.
+type Codec interface {
+	ID() byte
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// ProtoMarshaler is implemented by values the protobuf codec can store without being
+// proto.Messages themselves, typically by converting to and from an api/v1 message.
+type ProtoMarshaler interface {
+	MarshalProto() ([]byte, error)
+}
+
+// ProtoUnmarshaler is the decoding counterpart of ProtoMarshaler.
+type ProtoUnmarshaler interface {
+	UnmarshalProto(data []byte) error
+}
+
+// JSONCodec is the original encoding: readable in redis-cli, but slow and large.
+type JSONCodec struct{}
+
+func (JSONCodec) ID() byte     { return CodecIDJSON }
+func (JSONCodec) Name() string { return "json" }
+
+func (JSONCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// MsgpackCodec encodes any value as MessagePack, reusing its json struct tags.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) ID() byte     { return CodecIDMsgpack }
+func (MsgpackCodec) Name() string { return "msgpack" }
+
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// ProtobufCodec encodes proto.Messages and values implementing ProtoMarshaler. Other
+// values are rejected with ErrUnsupportedValue.
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) ID() byte     { return CodecIDProtobuf }
+func (ProtobufCodec) Name() string { return "protobuf" }
+
+func (ProtobufCodec) Marshal(v any) ([]byte, error) {
+	switch m := v.(type) {
+	case proto.Message:
+		return proto.Marshal(m)
+	case ProtoMarshaler:
+		return m.MarshalProto()
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedValue, v)
+	}
+}
+
+func (ProtobufCodec) Unmarshal(data []byte, v any) error {
+	switch m := v.(type) {
+	case proto.Message:
+		return proto.Unmarshal(data, m)
+	case ProtoUnmarshaler:
+		return m.UnmarshalProto(data)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedValue, v)
+	}
+}
+
+var codecs = map[byte]Codec{
+	CodecIDJSON:     JSONCodec{},
+	CodecIDMsgpack:  MsgpackCodec{},
+	CodecIDProtobuf: ProtobufCodec{},
+}
+
+// ParseCodec returns the codec called name: json, msgpack or protobuf. An empty name
+// selects JSON, the default.
+func ParseCodec(name string) (Codec, error) {
+	if name == "" {
+		return JSONCodec{}, nil
+	}
+	for _, c := range codecs {
+		if c.Name() == name {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown cache codec %q: must be json, msgpack or protobuf", name)
+}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// zstdCoders returns process-wide zstd coders; EncodeAll and DecodeAll are safe for
+// concurrent use.
+func zstdCoders() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// encoding writes values with codec, compressing payloads larger than threshold bytes
+// (never when threshold is 0), and reads values written by any known codec.
+type encoding struct {
+	codec     Codec
+	threshold int
+}
+
+func (e encoding) encode(v any) ([]byte, error) {
+	codec := e.codec
+	data, err := codec.Marshal(v)
+	if errors.Is(err, ErrUnsupportedValue) {
+		codec = MsgpackCodec{}
+		data, err = codec.Marshal(v)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode with %s: %w", codec.Name(), err)
+	}
+
+	header := codec.ID()
+	if e.threshold > 0 && len(data) > e.threshold {
+		enc, _, err := zstdCoders()
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+		out := make([]byte, 1, len(data)/2)
+		out[0] = header | flagZstd
+		return enc.EncodeAll(data, out), nil
+	}
+
+	out := make([]byte, 0, len(data)+1)
+	out = append(out, header)
+	return append(out, data...), nil
+}
+
+func (e encoding) decode(data []byte, v any) error {
+	if len(data) == 0 || isLegacyJSON(data[0]) {
+		return json.Unmarshal(data, v)
+	}
+
+	header, payload := data[0], data[1:]
+	codec, ok := codecs[header&^flagZstd]
+	if !ok {
+		return fmt.Errorf("cache: unknown encoding header 0x%02x", header)
+	}
+	if header&flagZstd != 0 {
+		_, dec, err := zstdCoders()
+		if err != nil {
+			return fmt.Errorf("zstd: %w", err)
+		}
+		if payload, err = dec.DecodeAll(payload, nil); err != nil {
+			return fmt.Errorf("decompress: %w", err)
+		}
+	}
+	return codec.Unmarshal(payload, v)
+}
+
+// isLegacyJSON reports whether b can start a json.Marshal output.
+func isLegacyJSON(b byte) bool {
+	switch {
+	case b == '{', b == '[', b == '"', b == '-', b == 't', b == 'f', b == 'n':
+		return true
+	case b >= '0' && b <= '9':
+		return true
+	}
+	return false
+}
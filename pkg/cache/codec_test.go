@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type codecTestValue struct {
+	Name    string             `json:"name"`
+	Scores  map[string]float64 `json:"scores"`
+	Created time.Time          `json:"created"`
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	value := codecTestValue{
+		Name:    strings.Repeat("ticket ", 200),
+		Scores:  map[string]float64{"Tone": 85.5, "Grammar": 72},
+		Created: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	for _, codec := range []Codec{JSONCodec{}, MsgpackCodec{}, ProtobufCodec{}} {
+		for _, threshold := range []int{0, 64} {
+			e := encoding{codec: codec, threshold: threshold}
+
+			data, err := e.encode(value)
+			require.NoError(t, err, codec.Name())
+
+			var got codecTestValue
+			require.NoError(t, e.decode(data, &got), codec.Name())
+			assert.True(t, value.Created.Equal(got.Created), codec.Name())
+			got.Created = value.Created // msgpack decodes times in the local zone
+			assert.Equal(t, value, got, codec.Name())
+			assert.Equal(t, threshold > 0, data[0]&flagZstd != 0, "compressed above threshold")
+		}
+	}
+}
+
+func TestEncodingHeaders(t *testing.T) {
+	data, err := encoding{codec: ProtobufCodec{}}.encode(durationpb.New(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, CodecIDProtobuf, data[0])
+
+	data, err = encoding{codec: ProtobufCodec{}}.encode(map[string]int{"a": 1})
+	require.NoError(t, err)
+	assert.Equal(t, CodecIDMsgpack, data[0], "values protobuf cannot encode fall back to msgpack")
+
+	var got durationpb.Duration
+	assert.Error(t, encoding{}.decode([]byte{0x7f, 1, 2}, &got))
+}
+
+func TestEncodingReadsOtherCodecs(t *testing.T) {
+	value := map[string]float64{"Tone": 1.5}
+
+	written, err := encoding{codec: MsgpackCodec{}, threshold: 1}.encode(value)
+	require.NoError(t, err)
+
+	var got map[string]float64
+	require.NoError(t, encoding{codec: JSONCodec{}}.decode(written, &got))
+	assert.Equal(t, value, got)
+}
+
+func TestCacheReadsLegacyJSON(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestCache(t, WithCodec(MsgpackCodec{}))
+	require.NoError(t, mr.Set("legacy", `{"name":"old","scores":{"Tone":1}}`))
+
+	var got codecTestValue
+	require.NoError(t, c.Get(ctx, "legacy", &got))
+	assert.Equal(t, "old", got.Name)
+	assert.Equal(t, 1.0, got.Scores["Tone"])
+}
+
+func TestCacheCompressesLargeValues(t *testing.T) {
+	ctx := context.Background()
+	c, mr := newTestCache(t, WithCodec(MsgpackCodec{}), WithCompressionThreshold(256))
+	value := codecTestValue{Name: strings.Repeat("a", 4096)}
+
+	require.NoError(t, c.Set(ctx, "big", value, time.Minute))
+
+	raw, err := mr.Get("big")
+	require.NoError(t, err)
+	assert.Less(t, len(raw), 1024)
+
+	var got codecTestValue
+	require.NoError(t, c.Get(ctx, "big", &got))
+	assert.Equal(t, value.Name, got.Name)
+}
+
+func TestParseCodec(t *testing.T) {
+	for name, id := range map[string]byte{"": CodecIDJSON, "json": CodecIDJSON, "msgpack": CodecIDMsgpack, "protobuf": CodecIDProtobuf} {
+		codec, err := ParseCodec(name)
+		require.NoError(t, err)
+		assert.Equal(t, id, codec.ID(), name)
+	}
+
+	_, err := ParseCodec("gob")
+	assert.Error(t, err)
+}
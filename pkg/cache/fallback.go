@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Pinger is a Backend whose availability can be probed.
+type Pinger interface {
+	Backend
+	Ping(ctx context.Context) error
+}
+
+type FallbackOptions struct {
+	probeInterval time.Duration
+	logger        *zap.Logger
+}
+
+type FallbackOption func(*FallbackOptions)
+
+// WithProbeInterval sets how often an unreachable primary is pinged to detect recovery.
+func WithProbeInterval(d time.Duration) FallbackOption {
+	return func(o *FallbackOptions) {
+		o.probeInterval = d
+	}
+}
+
+func WithFallbackLogger(logger *zap.Logger) FallbackOption {
+	return func(o *FallbackOptions) {
+		o.logger = logger
+	}
+}
+
+// Fallback serves from primary and switches to secondary as soon as primary reports a
+// connection error, so an outage degrades caching instead of failing requests. While
+// degraded, primary is pinged in the background and used again once it answers.
+type Fallback struct {
+	primary   Pinger
+	secondary Backend
+	options   *FallbackOptions
+	logger    *zap.Logger
+
+	degraded atomic.Bool
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewFallback(primary Pinger, secondary Backend, opts ...FallbackOption) *Fallback {
+	options := &FallbackOptions{
+		probeInterval: 5 * time.Second,
+		logger:        zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	logger := options.logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &Fallback{
+		primary:   primary,
+		secondary: secondary,
+		options:   options,
+		logger:    logger.Named("cache-fallback"),
+		stop:      make(chan struct{}),
+	}
+}
+
+// Degraded reports whether requests are currently served by the secondary backend.
+func (f *Fallback) Degraded() bool {
+	return f.degraded.Load()
+}
+
+func (f *Fallback) Get(ctx context.Context, key string, dest any) error {
+	if !f.degraded.Load() {
+		err := f.primary.Get(ctx, key, dest)
+		if !isUnavailable(err) {
+			return err
+		}
+		f.degrade(err)
+	}
+	return f.secondary.Get(ctx, key, dest)
+}
+
+// GetWithTTL is Get along with the key's remaining TTL in the backend that served it,
+// negative if that backend does not report one.
+func (f *Fallback) GetWithTTL(ctx context.Context, key string, dest any) (time.Duration, error) {
+	if !f.degraded.Load() {
+		ttl, err := getWithTTL(ctx, f.primary, key, dest)
+		if !isUnavailable(err) {
+			return ttl, err
+		}
+		f.degrade(err)
+	}
+	return getWithTTL(ctx, f.secondary, key, dest)
+}
+
+func (f *Fallback) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	if !f.degraded.Load() {
+		err := f.primary.Set(ctx, key, value, expiration)
+		if !isUnavailable(err) {
+			return err
+		}
+		f.degrade(err)
+	}
+	return f.secondary.Set(ctx, key, value, expiration)
+}
+
+// Invalidate always clears both backends. If primary cannot be reached the secondary is
+// still cleared and an error is returned, since primary may serve stale entries once it
+// recovers.
+func (f *Fallback) Invalidate(ctx context.Context, prefixes []string, start, end time.Time) (int64, error) {
+	secondaryDeleted, err := f.secondary.Invalidate(ctx, prefixes, start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := f.primary.Invalidate(ctx, prefixes, start, end)
+	if err != nil {
+		if isUnavailable(err) {
+			f.degrade(err)
+		}
+		return secondaryDeleted, fmt.Errorf("primary cache not invalidated, only the fallback was: %w", err)
+	}
+	return deleted + secondaryDeleted, nil
+}
+
+// Close stops probing and closes both backends.
+func (f *Fallback) Close() error {
+	f.stopOnce.Do(func() {
+		close(f.stop)
+	})
+	err := f.primary.Close()
+	if serr := f.secondary.Close(); err == nil {
+		err = serr
+	}
+	return err
+}
+
+// MarkUnavailable switches to the secondary and probes the primary until it answers, as
+// when the primary could not be reached at startup.
+func (f *Fallback) MarkUnavailable(err error) {
+	f.degrade(err)
+}
+
+func (f *Fallback) degrade(err error) {
+	if !f.degraded.CompareAndSwap(false, true) {
+		return
+	}
+	f.logger.Warn("primary cache unreachable, serving from fallback", zap.Error(err))
+	go f.probe()
+}
+
+func (f *Fallback) probe() {
+	ticker := time.NewTicker(f.options.probeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), f.options.probeInterval)
+			err := f.primary.Ping(ctx)
+			cancel()
+			if err == nil {
+				f.degraded.Store(false)
+				f.logger.Info("primary cache reachable again")
+				return
+			}
+		}
+	}
+}
+
+// isUnavailable reports whether err means the backend could not be reached, as opposed to
+// a miss, a decoding problem or the caller's context ending.
+func isUnavailable(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return false
+	}
+	// context.DeadlineExceeded is a net.Error too, but it only says the caller ran out of
+	// time, not that Redis is down.
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, redis.ErrClosed) ||
+		errors.Is(err, redis.ErrPoolTimeout)
+}
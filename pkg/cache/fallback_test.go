@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallbackDegradesAndRecovers(t *testing.T) {
+	ctx := context.Background()
+	redisCache, mr := newTestCache(t)
+	memory, err := NewMemory()
+	require.NoError(t, err)
+
+	f := NewFallback(redisCache, memory, WithProbeInterval(10*time.Millisecond))
+	t.Cleanup(func() {
+		_ = f.Close()
+	})
+
+	require.NoError(t, f.Set(ctx, "k", 1, time.Minute))
+	assert.True(t, mr.Exists("k"), "healthy writes go to the primary")
+	assert.False(t, f.Degraded())
+
+	mr.Close()
+
+	var v int
+	assert.ErrorIs(t, f.Get(ctx, "k", &v), redis.Nil, "an outage reads as a miss from the fallback")
+	assert.True(t, f.Degraded())
+
+	require.NoError(t, f.Set(ctx, "k", 2, time.Minute))
+	require.NoError(t, f.Get(ctx, "k", &v))
+	assert.Equal(t, 2, v)
+
+	_, err = f.Invalidate(ctx, []string{"k"}, time.Time{}, time.Time{})
+	assert.Error(t, err, "invalidation reports that the primary was not reached")
+
+	require.NoError(t, mr.Restart())
+	require.Eventually(t, func() bool {
+		return !f.Degraded()
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, f.Get(ctx, "k", &v))
+	assert.Equal(t, 1, v, "reads go back to the primary")
+}
+
+func TestIsUnavailable(t *testing.T) {
+	assert.False(t, isUnavailable(nil))
+	assert.False(t, isUnavailable(redis.Nil))
+	assert.False(t, isUnavailable(context.Canceled))
+	assert.False(t, isUnavailable(context.DeadlineExceeded))
+	assert.False(t, isUnavailable(fmt.Errorf("redis get: %w", context.DeadlineExceeded)))
+	assert.True(t, isUnavailable(redis.ErrClosed))
+}
+
+func TestFallbackIgnoresCallerDeadline(t *testing.T) {
+	redisCache, _ := newTestCache(t)
+	memory, err := NewMemory()
+	require.NoError(t, err)
+	f := NewFallback(redisCache, memory)
+	t.Cleanup(func() {
+		_ = f.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	var v int
+	assert.ErrorIs(t, f.Get(ctx, "k", &v), context.DeadlineExceeded)
+	assert.False(t, f.Degraded(), "a caller running out of time says nothing about Redis")
+}
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidateBatchSize bounds both the SCAN/SSCAN page size and the number of keys per
+// UNLINK, so invalidation never blocks Redis on a single large command.
+const invalidateBatchSize = 500
+
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// Invalidate deletes the keys selected by InvalidationMatches and returns how many were
+// removed. A bounded date range is resolved through the month tag sets written by Set, so
+// it misses keys written without them, such as by an older release; otherwise keys are
+// found with an incremental SCAN per prefix, on every master in cluster mode. Keys are
+// removed with UNLINK, which frees memory off the main Redis thread.
+func (c *Cache) Invalidate(ctx context.Context, prefixes []string, start, end time.Time) (int64, error) {
+	if len(prefixes) == 0 && start.IsZero() && end.IsZero() {
+		return 0, errors.New("invalidate: a key prefix or date is required")
+	}
+
+	d := &batchDeleter{client: c.client, cluster: c.cluster}
+
+	if !start.IsZero() && !end.IsZero() && monthsBetween(start.UTC(), end.UTC()) <= maxTaggedMonths {
+		tags := append(windowTags(start.UTC(), end.UTC()), wideWindowTag)
+		seen := make(map[string]struct{})
+		for _, tag := range tags {
+			iter := c.client.SScan(ctx, tag, 0, "", invalidateBatchSize).Iterator()
+			for iter.Next(ctx) {
+				key := iter.Val()
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+				if InvalidationMatches(key, prefixes, start, end) {
+					if err := d.add(ctx, key); err != nil {
+						return d.deleted, err
+					}
+				}
+			}
+			if err := iter.Err(); err != nil {
+				return d.deleted, fmt.Errorf("scan tag %s: %w", tag, err)
+			}
+		}
+		return d.deleted, d.flush(ctx)
+	}
+
+	patterns := []string{"*"}
+	if len(prefixes) > 0 {
+		patterns = patterns[:0]
+		for _, p := range prefixes {
+			patterns = append(patterns, globEscaper.Replace(p)+"*")
+		}
+	}
+
+	for _, pattern := range patterns {
+		err := c.scan(ctx, pattern, func(key string) error {
+			if InvalidationMatches(key, prefixes, start, end) {
+				return d.add(ctx, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return d.deleted, err
+		}
+	}
+	return d.deleted, d.flush(ctx)
+}
+
+// scan calls fn for every key matching pattern. A cluster is scanned on all masters
+// concurrently, so fn must be safe for concurrent use.
+func (c *Cache) scan(ctx context.Context, pattern string, fn func(key string) error) error {
+	scanNode := func(ctx context.Context, client redis.Cmdable) error {
+		iter := client.Scan(ctx, 0, pattern, invalidateBatchSize).Iterator()
+		for iter.Next(ctx) {
+			if err := fn(iter.Val()); err != nil {
+				return err
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("scan %s: %w", pattern, err)
+		}
+		return nil
+	}
+
+	if cc, ok := c.client.(*redis.ClusterClient); ok {
+		return cc.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return scanNode(ctx, node)
+		})
+	}
+	return scanNode(ctx, c.client)
+}
+
+// batchDeleter UNLINKs keys in batches of invalidateBatchSize. Deleted keys are left in
+// their tag sets; the stale members are harmless and go away when the set expires. In
+// cluster mode keys of a batch may hash to different slots, so each is unlinked on its own
+// within a pipeline.
+type batchDeleter struct {
+	client  redis.UniversalClient
+	cluster bool
+
+	mu      sync.Mutex
+	pending []string
+	deleted int64
+}
+
+func (d *batchDeleter) add(ctx context.Context, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pending = append(d.pending, key)
+	if len(d.pending) >= invalidateBatchSize {
+		return d.flush(ctx)
+	}
+	return nil
+}
+
+func (d *batchDeleter) flush(ctx context.Context) error {
+	if len(d.pending) == 0 {
+		return nil
+	}
+
+	if !d.cluster {
+		n, err := d.client.Unlink(ctx, d.pending...).Result()
+		if err != nil {
+			return fmt.Errorf("unlink: %w", err)
+		}
+		d.deleted += n
+		d.pending = d.pending[:0]
+		return nil
+	}
+
+	cmds, err := d.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range d.pending {
+			pipe.Unlink(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unlink: %w", err)
+	}
+	for _, cmd := range cmds {
+		d.deleted += cmd.(*redis.IntCmd).Val()
+	}
+	d.pending = d.pending[:0]
+	return nil
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"strings"
+	"time"
+)
+
+// Report values are stored under window keys of the form <prefix>:<YYYY-MM-DD>:<YYYY-MM-DD>,
+// where the dates are the first and last UTC day of the window the value was computed for.
+// Set tags window keys by month so Invalidate can find them by date without a full SCAN.
+
+const windowKeyDateLayout = "2006-01-02"
+
+const (
+	windowTagPrefix = "cache:tag:window:"
+	// wideWindowTag collects keys whose window spans more than maxTaggedMonths months,
+	// instead of adding them to every month set.
+	wideWindowTag   = windowTagPrefix + "wide"
+	maxTaggedMonths = 24
+)
+
+// ParseWindowKey splits a window key into its prefix and UTC start and end days.
+func ParseWindowKey(key string) (prefix string, start, end time.Time, ok bool) {
+	i := strings.LastIndexByte(key, ':')
+	if i < 0 {
+		return "", time.Time{}, time.Time{}, false
+	}
+	j := strings.LastIndexByte(key[:i], ':')
+	if j < 0 {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	start, err := time.Parse(windowKeyDateLayout, key[j+1:i])
+	if err != nil {
+		return "", time.Time{}, time.Time{}, false
+	}
+	end, err = time.Parse(windowKeyDateLayout, key[i+1:])
+	if err != nil {
+		return "", time.Time{}, time.Time{}, false
+	}
+	return key[:j], start, end, true
+}
+
+// InvalidationMatches reports whether Invalidate(prefixes, start, end) selects key: the key
+// must begin with one of prefixes (any prefix when empty) and, when start or end is set, be
+// a window key whose window overlaps the days from start to end. A zero start or end leaves
+// that side of the range open.
+func InvalidationMatches(key string, prefixes []string, start, end time.Time) bool {
+	if len(prefixes) > 0 {
+		matched := false
+		for _, p := range prefixes {
+			if strings.HasPrefix(key, p) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if start.IsZero() && end.IsZero() {
+		return true
+	}
+
+	_, keyStart, keyEnd, ok := ParseWindowKey(key)
+	if !ok {
+		return false
+	}
+	if !end.IsZero() && keyStart.After(startOfDay(end)) {
+		return false
+	}
+	if !start.IsZero() && keyEnd.Before(startOfDay(start)) {
+		return false
+	}
+	return true
+}
+
+// windowTags returns the tag sets a key for the window from start to end belongs to.
+func windowTags(start, end time.Time) []string {
+	months := monthsBetween(start, end)
+	if months > maxTaggedMonths {
+		return []string{wideWindowTag}
+	}
+
+	tags := make([]string, 0, months)
+	for m := firstOfMonth(start); !m.After(end); m = m.AddDate(0, 1, 0) {
+		tags = append(tags, windowTagPrefix+m.Format("2006-01"))
+	}
+	return tags
+}
+
+// monthsBetween counts the calendar months touched by the range, inclusive.
+func monthsBetween(start, end time.Time) int {
+	return (end.Year()-start.Year())*12 + int(end.Month()-start.Month()) + 1
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseWindowKey(t *testing.T) {
+	prefix, start, end, ok := ParseWindowKey("grpc:scores_by_ticket:2025-01-01:2025-02-15")
+
+	assert.True(t, ok)
+	assert.Equal(t, "grpc:scores_by_ticket", prefix)
+	assert.Equal(t, day(2025, 1, 1), start)
+	assert.Equal(t, day(2025, 2, 15), end)
+
+	for _, key := range []string{"plain", "a:b", "grpc:x:2025-01-01", "grpc:x:2025-01-01:tomorrow", "grpc:x:01-01-2025:2025-01-02"} {
+		_, _, _, ok := ParseWindowKey(key)
+		assert.False(t, ok, key)
+	}
+}
+
+func TestInvalidationMatches(t *testing.T) {
+	key := "grpc:overall_quality_score:2025-01-10:2025-01-20"
+
+	tests := []struct {
+		name     string
+		prefixes []string
+		start    time.Time
+		end      time.Time
+		expected bool
+	}{
+		{"prefix only", []string{"grpc:overall"}, time.Time{}, time.Time{}, true},
+		{"other prefix", []string{"grpc:scores_by_ticket"}, time.Time{}, time.Time{}, false},
+		{"any of prefixes", []string{"grpc:scores_by_ticket", "grpc:overall_quality_score"}, time.Time{}, time.Time{}, true},
+		{"day inside window", nil, day(2025, 1, 15), day(2025, 1, 15), true},
+		{"first day", nil, day(2025, 1, 10), day(2025, 1, 10), true},
+		{"last day with time of day", nil, time.Date(2025, 1, 20, 18, 0, 0, 0, time.UTC), time.Date(2025, 1, 20, 18, 0, 0, 0, time.UTC), true},
+		{"day before", nil, day(2025, 1, 9), day(2025, 1, 9), false},
+		{"day after", nil, day(2025, 1, 21), day(2025, 1, 21), false},
+		{"range covering window", nil, day(2025, 1, 1), day(2025, 2, 1), true},
+		{"open end", nil, day(2025, 1, 20), time.Time{}, true},
+		{"open end after window", nil, day(2025, 1, 21), time.Time{}, false},
+		{"open start", nil, time.Time{}, day(2025, 1, 10), true},
+		{"prefix and date", []string{"grpc:overall"}, day(2025, 1, 15), day(2025, 1, 15), true},
+		{"prefix mismatch with date", []string{"grpc:scores"}, day(2025, 1, 15), day(2025, 1, 15), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, InvalidationMatches(key, tt.prefixes, tt.start, tt.end))
+		})
+	}
+
+	assert.False(t, InvalidationMatches("session:abc", nil, day(2025, 1, 15), day(2025, 1, 15)),
+		"non-window keys never match a date range")
+}
+
+func TestWindowTags(t *testing.T) {
+	assert.Equal(t, []string{"cache:tag:window:2025-01"}, windowTags(day(2025, 1, 1), day(2025, 1, 31)))
+	assert.Equal(t,
+		[]string{"cache:tag:window:2024-12", "cache:tag:window:2025-01", "cache:tag:window:2025-02"},
+		windowTags(day(2024, 12, 31), day(2025, 2, 1)))
+	assert.Len(t, windowTags(day(2023, 1, 1), day(2024, 12, 31)), maxTaggedMonths)
+	assert.Equal(t, []string{wideWindowTag}, windowTags(day(2020, 1, 1), day(2025, 1, 1)))
+}
@@ -0,0 +1,282 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type LRUOptions struct {
+	maxEntries int
+	ttl        time.Duration
+	bus        InvalidationBus
+	logger     *zap.Logger
+}
+
+type LRUOption func(*LRUOptions)
+
+// WithMaxEntries bounds the number of entries held in memory.
+func WithMaxEntries(n int) LRUOption {
+	return func(o *LRUOptions) {
+		o.maxEntries = n
+	}
+}
+
+// WithLocalTTL caps how long an entry is served from memory before the next tier is
+// consulted again. Entries never outlive the expiration they were Set with, nor, when the
+// next tier is a TTLGetter, the remaining TTL they were read with.
+func WithLocalTTL(ttl time.Duration) LRUOption {
+	return func(o *LRUOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithInvalidationBus keeps replicas coherent: Set and Invalidate are published on the bus,
+// and invalidations from other replicas evict the affected local entries.
+func WithInvalidationBus(bus InvalidationBus) LRUOption {
+	return func(o *LRUOptions) {
+		o.bus = bus
+	}
+}
+
+func WithLRULogger(logger *zap.Logger) LRUOption {
+	return func(o *LRUOptions) {
+		o.logger = logger
+	}
+}
+
+// LRU is an in-process, size-bounded tier in front of another Backend. It keeps decoded
+// values, so hits skip both the network round trip and decoding; values are shared between
+// callers and must not be modified.
+type LRU struct {
+	next    Backend
+	options *LRUOptions
+	logger  *zap.Logger
+
+	mu    sync.Mutex
+	order *list.List // front is most recently used
+	items map[string]*list.Element
+
+	unsubscribe func() error
+}
+
+type lruEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// NewLRU wraps next, which may be nil for a purely in-process cache.
+func NewLRU(ctx context.Context, next Backend, opts ...LRUOption) (*LRU, error) {
+	options := &LRUOptions{
+		maxEntries: 10_000,
+		ttl:        30 * time.Second,
+		logger:     zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.maxEntries <= 0 {
+		return nil, fmt.Errorf("invalid max entries %d: must be positive", options.maxEntries)
+	}
+	if options.ttl <= 0 {
+		return nil, fmt.Errorf("invalid local TTL %v: must be positive", options.ttl)
+	}
+
+	logger := options.logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	l := &LRU{
+		next:    next,
+		options: options,
+		logger:  logger.Named("lru-cache"),
+		order:   list.New(),
+		items:   make(map[string]*list.Element),
+	}
+
+	if options.bus != nil {
+		unsubscribe, err := options.bus.Subscribe(ctx, func(inv Invalidation) {
+			l.applyInvalidation(inv)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("subscribe to invalidations: %w", err)
+		}
+		l.unsubscribe = unsubscribe
+	}
+
+	return l, nil
+}
+
+// Get serves key from memory when present, otherwise from the next tier, keeping the
+// decoded result. Misses are reported with redis.Nil.
+func (l *LRU) Get(ctx context.Context, key string, dest any) error {
+	if e, ok := l.lookup(key); ok {
+		return assign(dest, e.value)
+	}
+
+	if l.next == nil {
+		return redis.Nil
+	}
+	remaining, err := getWithTTL(ctx, l.next, key, dest)
+	if err != nil {
+		return err
+	}
+
+	ttl := l.options.ttl
+	if remaining >= 0 {
+		ttl = min(ttl, remaining)
+	}
+	if ttl > 0 {
+		l.store(key, reflect.ValueOf(dest).Elem().Interface(), ttl)
+	}
+	return nil
+}
+
+// Set writes value to the next tier and then to memory, and tells other replicas to drop
+// their copy. If the next tier fails, the local copy is dropped too, so this replica does
+// not serve a value no other tier has.
+func (l *LRU) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	if l.next != nil {
+		if err := l.next.Set(ctx, key, value, expiration); err != nil {
+			l.applyInvalidation(Invalidation{Keys: []string{key}})
+			return err
+		}
+	}
+	ttl := l.options.ttl
+	if expiration > 0 && expiration < ttl {
+		ttl = expiration
+	}
+	l.store(key, value, ttl)
+	l.publish(ctx, Invalidation{Keys: []string{key}})
+	return nil
+}
+
+// Invalidate drops matching entries locally, on other replicas and in the next tier. The
+// count is the next tier's when there is one, otherwise the number of local entries dropped.
+func (l *LRU) Invalidate(ctx context.Context, prefixes []string, start, end time.Time) (int64, error) {
+	inv := Invalidation{Prefixes: prefixes, Start: start, End: end}
+	local := l.applyInvalidation(inv)
+	l.publish(ctx, inv)
+
+	if l.next == nil {
+		return local, nil
+	}
+	return l.next.Invalidate(ctx, prefixes, start, end)
+}
+
+// Close stops listening for invalidations and closes the next tier.
+func (l *LRU) Close() error {
+	var err error
+	if l.unsubscribe != nil {
+		err = l.unsubscribe()
+	}
+	if l.next != nil {
+		if cerr := l.next.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Len returns the number of entries held in memory, including expired ones not yet evicted.
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *LRU) lookup(key string) (*lruEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if time.Now().After(e.expiresAt) {
+		l.removeElement(el)
+		return nil, false
+	}
+	l.order.MoveToFront(el)
+	return e, true
+}
+
+func (l *LRU) store(key string, value any, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := &lruEntry{key: key, value: value, expiresAt: time.Now().Add(ttl)}
+	if el, ok := l.items[key]; ok {
+		el.Value = e
+		l.order.MoveToFront(el)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(e)
+	for l.order.Len() > l.options.maxEntries {
+		l.removeElement(l.order.Back())
+	}
+}
+
+func (l *LRU) removeElement(el *list.Element) {
+	l.order.Remove(el)
+	delete(l.items, el.Value.(*lruEntry).key)
+}
+
+func (l *LRU) applyInvalidation(inv Invalidation) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var removed int64
+	for _, key := range inv.Keys {
+		if el, ok := l.items[key]; ok {
+			l.removeElement(el)
+			removed++
+		}
+	}
+
+	if len(inv.Prefixes) == 0 && inv.Start.IsZero() && inv.End.IsZero() {
+		return removed
+	}
+	for key, el := range l.items {
+		if InvalidationMatches(key, inv.Prefixes, inv.Start, inv.End) {
+			l.removeElement(el)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (l *LRU) publish(ctx context.Context, inv Invalidation) {
+	if l.options.bus == nil {
+		return
+	}
+	if err := l.options.bus.Publish(ctx, inv); err != nil {
+		l.logger.Warn("failed to publish cache invalidation", zap.Error(err))
+	}
+}
+
+// assign copies value into the variable dest points to.
+func assign(dest any, value any) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("cache: destination must be a non-nil pointer, got %T", dest)
+	}
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || !v.Type().AssignableTo(dv.Elem().Type()) {
+		return fmt.Errorf("cache: cannot assign %T to %s", value, dv.Elem().Type())
+	}
+	dv.Elem().Set(v)
+	return nil
+}
@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBackend wraps a Backend and counts Gets that reach it.
+type countingBackend struct {
+	Backend
+	gets int
+}
+
+func (c *countingBackend) Get(ctx context.Context, key string, dest any) error {
+	c.gets++
+	return c.Backend.Get(ctx, key, dest)
+}
+
+type scores struct {
+	Values []float64
+}
+
+func TestLRUServesDecodedValuesFromMemory(t *testing.T) {
+	ctx := context.Background()
+	redisCache, _ := newTestCache(t)
+	next := &countingBackend{Backend: redisCache}
+	require.NoError(t, redisCache.Set(ctx, "k", scores{Values: []float64{1, 2}}, time.Minute))
+
+	l, err := NewLRU(ctx, next)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		var got scores
+		require.NoError(t, l.Get(ctx, "k", &got))
+		assert.Equal(t, []float64{1, 2}, got.Values)
+	}
+	assert.Equal(t, 1, next.gets, "only the first Get reaches the next tier")
+}
+
+func TestLRUSetWritesThrough(t *testing.T) {
+	ctx := context.Background()
+	redisCache, mr := newTestCache(t)
+	l, err := NewLRU(ctx, redisCache)
+	require.NoError(t, err)
+
+	require.NoError(t, l.Set(ctx, "k", 1.5, time.Minute))
+
+	assert.True(t, mr.Exists("k"))
+	var got float64
+	require.NoError(t, l.Get(ctx, "k", &got))
+	assert.Equal(t, 1.5, got)
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	l, err := NewLRU(ctx, nil, WithMaxEntries(2))
+	require.NoError(t, err)
+
+	require.NoError(t, l.Set(ctx, "a", 1, time.Minute))
+	require.NoError(t, l.Set(ctx, "b", 2, time.Minute))
+	var v int
+	require.NoError(t, l.Get(ctx, "a", &v)) // a is now more recent than b
+	require.NoError(t, l.Set(ctx, "c", 3, time.Minute))
+
+	assert.Equal(t, 2, l.Len())
+	assert.NoError(t, l.Get(ctx, "a", &v))
+	assert.ErrorIs(t, l.Get(ctx, "b", &v), redis.Nil)
+	assert.NoError(t, l.Get(ctx, "c", &v))
+}
+
+func TestLRUEntriesExpire(t *testing.T) {
+	ctx := context.Background()
+	l, err := NewLRU(ctx, nil, WithLocalTTL(time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, l.Set(ctx, "short", 1, 20*time.Millisecond))
+	require.NoError(t, l.Set(ctx, "long", 1, time.Hour))
+
+	var v int
+	assert.Eventually(t, func() bool {
+		return l.Get(ctx, "short", &v) == redis.Nil
+	}, time.Second, 5*time.Millisecond)
+	assert.NoError(t, l.Get(ctx, "long", &v))
+}
+
+func TestLRUEntriesReadFromNextTierKeepTheirExpiry(t *testing.T) {
+	ctx := context.Background()
+	memory, err := NewMemory()
+	require.NoError(t, err)
+	l, err := NewLRU(ctx, memory, WithLocalTTL(time.Hour))
+	require.NoError(t, err)
+	defer l.Close()
+
+	require.NoError(t, memory.Set(ctx, "k", 1, 50*time.Millisecond))
+	var v int
+	require.NoError(t, l.Get(ctx, "k", &v))
+
+	assert.Eventually(t, func() bool {
+		return l.Get(ctx, "k", &v) == redis.Nil
+	}, time.Second, 5*time.Millisecond, "the local copy expires with the next tier's")
+}
+
+// failingBackend rejects every write and has nothing to read.
+type failingBackend struct {
+	Noop
+}
+
+func (failingBackend) Set(context.Context, string, any, time.Duration) error {
+	return errors.New("write failed")
+}
+
+func TestLRUSetFailureKeepsNoLocalCopy(t *testing.T) {
+	ctx := context.Background()
+	l, err := NewLRU(ctx, failingBackend{})
+	require.NoError(t, err)
+
+	assert.Error(t, l.Set(ctx, "k", 1, time.Minute))
+	assert.Zero(t, l.Len())
+	var v int
+	assert.ErrorIs(t, l.Get(ctx, "k", &v), redis.Nil)
+}
+
+func TestLRUTypeMismatch(t *testing.T) {
+	ctx := context.Background()
+	l, err := NewLRU(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, l.Set(ctx, "k", 1.5, time.Minute))
+
+	var s string
+	assert.Error(t, l.Get(ctx, "k", &s))
+}
+
+func TestLRUInvalidate(t *testing.T) {
+	ctx := context.Background()
+	redisCache, mr := newTestCache(t)
+	l, err := NewLRU(ctx, redisCache)
+	require.NoError(t, err)
+
+	require.NoError(t, l.Set(ctx, "grpc:scores_by_ticket:2025-01-01:2025-01-31", 1, time.Minute))
+	require.NoError(t, l.Set(ctx, "grpc:overall_quality_score:2025-01-01:2025-01-31", 1, time.Minute))
+
+	deleted, err := l.Invalidate(ctx, []string{"grpc:scores_by_ticket"}, time.Time{}, time.Time{})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.Equal(t, 1, l.Len())
+	assert.False(t, mr.Exists("grpc:scores_by_ticket:2025-01-01:2025-01-31"))
+}
+
+func TestLRUInvalidationBusKeepsReplicasCoherent(t *testing.T) {
+	ctx := context.Background()
+	redisCache, _ := newTestCache(t)
+
+	replicaA, err := NewLRU(ctx, redisCache, WithInvalidationBus(NewRedisBus(redisCache, "test:invalidate")))
+	require.NoError(t, err)
+	replicaB, err := NewLRU(ctx, redisCache, WithInvalidationBus(NewRedisBus(redisCache, "test:invalidate")))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = replicaA.unsubscribe()
+		_ = replicaB.unsubscribe()
+	})
+
+	const key = "grpc:overall_quality_score:2025-01-01:2025-01-31"
+	require.NoError(t, replicaA.Set(ctx, key, 1.0, time.Minute))
+
+	var v float64
+	require.Eventually(t, func() bool {
+		// B may still be processing A's Set message, so re-read until it holds the value.
+		return replicaB.Get(ctx, key, &v) == nil && v == 1.0 && replicaB.Len() == 1
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, replicaA.Set(ctx, key, 2.0, time.Minute))
+	assert.Eventually(t, func() bool {
+		return replicaB.Len() == 0
+	}, time.Second, 5*time.Millisecond, "a Set on A evicts B's copy")
+
+	require.NoError(t, replicaB.Get(ctx, key, &v))
+	assert.Equal(t, 2.0, v)
+
+	_, err = replicaA.Invalidate(ctx, nil, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return replicaB.Len() == 0
+	}, time.Second, 5*time.Millisecond, "an Invalidate on A evicts B's matching entries")
+}
@@ -0,0 +1,224 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// memoryEntryOverhead approximates the bookkeeping bytes of an entry beyond its key and
+// encoded value, so many small entries still count against the bound.
+const memoryEntryOverhead = 96
+
+type MemoryOptions struct {
+	maxBytes      int64
+	sweepInterval time.Duration
+}
+
+type MemoryOption func(*MemoryOptions)
+
+// WithMaxBytes bounds the approximate memory used by keys and encoded values. When a Set
+// would exceed it, the least recently used entries are evicted.
+func WithMaxBytes(n int64) MemoryOption {
+	return func(o *MemoryOptions) {
+		o.maxBytes = n
+	}
+}
+
+// WithSweepInterval sets how often expired entries are removed in the background.
+// Expired entries are never returned, even before they are swept.
+func WithSweepInterval(d time.Duration) MemoryOption {
+	return func(o *MemoryOptions) {
+		o.sweepInterval = d
+	}
+}
+
+// Memory is an in-process Backend with the same semantics as Cache: values are JSON-encoded,
+// so callers get their own copy, keys expire after their TTL, and misses return redis.Nil.
+type Memory struct {
+	options *MemoryOptions
+
+	mu    sync.Mutex
+	order *list.List // front is most recently used
+	items map[string]*list.Element
+	bytes int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type memoryEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time // zero: no expiry
+}
+
+func (e *memoryEntry) size() int64 {
+	return int64(len(e.key) + len(e.data) + memoryEntryOverhead)
+}
+
+// NewMemory creates an in-memory cache and starts its expiry sweeper; Close stops it.
+func NewMemory(opts ...MemoryOption) (*Memory, error) {
+	options := &MemoryOptions{
+		maxBytes:      64 << 20,
+		sweepInterval: time.Minute,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.maxBytes <= 0 {
+		return nil, fmt.Errorf("invalid max bytes %d: must be positive", options.maxBytes)
+	}
+	if options.sweepInterval <= 0 {
+		return nil, fmt.Errorf("invalid sweep interval %v: must be positive", options.sweepInterval)
+	}
+
+	m := &Memory{
+		options: options,
+		order:   list.New(),
+		items:   make(map[string]*list.Element),
+		stop:    make(chan struct{}),
+	}
+	go m.sweep()
+	return m, nil
+}
+
+func (m *Memory) Get(ctx context.Context, key string, dest any) error {
+	_, err := m.GetWithTTL(ctx, key, dest)
+	return err
+}
+
+// GetWithTTL reads key along with its remaining TTL, which is negative when it does not
+// expire.
+func (m *Memory) GetWithTTL(_ context.Context, key string, dest any) (time.Duration, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	el, ok := m.items[key]
+	if !ok {
+		m.mu.Unlock()
+		return 0, redis.Nil
+	}
+	e := el.Value.(*memoryEntry)
+	if e.expired(now) {
+		m.removeElement(el)
+		m.mu.Unlock()
+		return 0, redis.Nil
+	}
+	m.order.MoveToFront(el)
+	data, expiresAt := e.data, e.expiresAt
+	m.mu.Unlock()
+
+	ttl := time.Duration(-1)
+	if !expiresAt.IsZero() {
+		ttl = expiresAt.Sub(now)
+	}
+	return ttl, json.Unmarshal(data, dest)
+}
+
+// Set stores value under key. A value larger than the whole bound is not stored.
+func (m *Memory) Set(_ context.Context, key string, value any, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	e := &memoryEntry{key: key, data: data}
+	if expiration > 0 {
+		e.expiresAt = time.Now().Add(expiration)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		m.removeElement(el)
+	}
+	if e.size() > m.options.maxBytes {
+		return nil
+	}
+
+	m.items[key] = m.order.PushFront(e)
+	m.bytes += e.size()
+	for m.bytes > m.options.maxBytes {
+		m.removeElement(m.order.Back())
+	}
+	return nil
+}
+
+func (m *Memory) Invalidate(_ context.Context, prefixes []string, start, end time.Time) (int64, error) {
+	if len(prefixes) == 0 && start.IsZero() && end.IsZero() {
+		return 0, fmt.Errorf("invalidate: a key prefix or date is required")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	for key, el := range m.items {
+		if InvalidationMatches(key, prefixes, start, end) {
+			m.removeElement(el)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Close stops the sweeper and drops all entries.
+func (m *Memory) Close() error {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.order.Init()
+	m.items = make(map[string]*list.Element)
+	m.bytes = 0
+	return nil
+}
+
+// Bytes returns the approximate memory used by live and not yet swept entries.
+func (m *Memory) Bytes() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.bytes
+}
+
+func (m *Memory) sweep() {
+	ticker := time.NewTicker(m.options.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case now := <-ticker.C:
+			m.mu.Lock()
+			for _, el := range m.items {
+				if el.Value.(*memoryEntry).expired(now) {
+					m.removeElement(el)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
+
+func (m *Memory) removeElement(el *list.Element) {
+	e := el.Value.(*memoryEntry)
+	m.order.Remove(el)
+	delete(m.items, e.key)
+	m.bytes -= e.size()
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemory(t *testing.T, opts ...MemoryOption) *Memory {
+	t.Helper()
+
+	m, err := NewMemory(opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = m.Close()
+	})
+	return m
+}
+
+func TestMemorySetGet(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemory(t)
+
+	require.NoError(t, m.Set(ctx, "k", scores{Values: []float64{1, 2}}, time.Minute))
+
+	var got scores
+	require.NoError(t, m.Get(ctx, "k", &got))
+	assert.Equal(t, []float64{1, 2}, got.Values)
+
+	got.Values[0] = 99
+	var again scores
+	require.NoError(t, m.Get(ctx, "k", &again))
+	assert.Equal(t, 1.0, again.Values[0], "callers get their own copy")
+
+	assert.ErrorIs(t, m.Get(ctx, "missing", &got), redis.Nil)
+}
+
+func TestMemoryExpiry(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemory(t, WithSweepInterval(10*time.Millisecond))
+
+	require.NoError(t, m.Set(ctx, "short", 1, 20*time.Millisecond))
+	require.NoError(t, m.Set(ctx, "forever", 1, 0))
+
+	var v int
+	assert.Eventually(t, func() bool {
+		return m.Get(ctx, "short", &v) == redis.Nil
+	}, time.Second, 5*time.Millisecond)
+	assert.NoError(t, m.Get(ctx, "forever", &v))
+
+	// The sweeper also reclaims expired entries nobody reads.
+	require.NoError(t, m.Set(ctx, "unread", 1, 20*time.Millisecond))
+	assert.Eventually(t, func() bool {
+		return m.Bytes() == (&memoryEntry{key: "forever", data: []byte("1")}).size()
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestMemoryEvictsToStayWithinBound(t *testing.T) {
+	ctx := context.Background()
+	value := strings.Repeat("x", 100)
+	entrySize := (&memoryEntry{key: "k0", data: []byte(`"` + value + `"`)}).size()
+	m := newTestMemory(t, WithMaxBytes(3*entrySize))
+
+	for _, k := range []string{"k0", "k1", "k2"} {
+		require.NoError(t, m.Set(ctx, k, value, time.Minute))
+	}
+	var v string
+	require.NoError(t, m.Get(ctx, "k0", &v)) // k1 is now least recently used
+	require.NoError(t, m.Set(ctx, "k3", value, time.Minute))
+
+	assert.LessOrEqual(t, m.Bytes(), 3*entrySize)
+	assert.ErrorIs(t, m.Get(ctx, "k1", &v), redis.Nil)
+	for _, k := range []string{"k0", "k2", "k3"} {
+		assert.NoError(t, m.Get(ctx, k, &v), k)
+	}
+
+	require.NoError(t, m.Set(ctx, "huge", strings.Repeat("x", int(4*entrySize)), time.Minute))
+	assert.ErrorIs(t, m.Get(ctx, "huge", &v), redis.Nil, "values larger than the bound are not stored")
+	assert.NoError(t, m.Get(ctx, "k3", &v))
+}
+
+func TestMemoryOverwriteUpdatesSize(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemory(t)
+
+	require.NoError(t, m.Set(ctx, "k", strings.Repeat("x", 1000), time.Minute))
+	require.NoError(t, m.Set(ctx, "k", "x", time.Minute))
+
+	assert.Equal(t, (&memoryEntry{key: "k", data: []byte(`"x"`)}).size(), m.Bytes())
+}
+
+func TestMemoryInvalidate(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemory(t)
+
+	require.NoError(t, m.Set(ctx, "grpc:scores_by_ticket:2025-01-01:2025-01-31", 1, time.Minute))
+	require.NoError(t, m.Set(ctx, "grpc:scores_by_ticket:2025-03-01:2025-03-31", 1, time.Minute))
+
+	deleted, err := m.Invalidate(ctx, nil, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = m.Invalidate(ctx, nil, time.Time{}, time.Time{})
+	assert.Error(t, err)
+}
+
+func TestNewMemoryValidation(t *testing.T) {
+	_, err := NewMemory(WithMaxBytes(0))
+	assert.Error(t, err)
+
+	_, err = NewMemory(WithSweepInterval(0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Noop is a cache that stores nothing: every Get is a miss and every Set is discarded.
+// It lets cache-aware callers run without Redis.
+type Noop struct{}
+
+func NewNoop() *Noop {
+	return &Noop{}
+}
+
+// Get always reports a miss with redis.Nil, the same error a Redis miss returns.
+func (Noop) Get(context.Context, string, any) error {
+	return redis.Nil
+}
+
+func (Noop) Set(context.Context, string, any, time.Duration) error {
+	return nil
+}
+
+func (Noop) Close() error {
+	return nil
+}
+
+func (Noop) Invalidate(context.Context, []string, time.Time, time.Time) (int64, error) {
+	return 0, nil
+}
@@ -2,21 +2,57 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// extendTTLScript sets the TTL of KEYS[1] to ARGV[1] milliseconds unless the key already
+// lives longer, which EXPIRE NX and EXPIRE GT would do but only from Redis 7.
+const extendTTLScript = `
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < tonumber(ARGV[1]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return ttl
+`
+
 type Cache struct {
-	client *redis.Client
+	client   redis.UniversalClient
+	cluster  bool
+	encoding encoding
 }
 
 type Options struct {
 	Address  string
+	Username string
 	Password string
 	DB       int
+	// Addrs are the cluster seed nodes or Sentinel addresses; they replace Address.
+	Addrs []string
+	// Cluster connects to a Redis Cluster. Only DB 0 is available.
+	Cluster bool
+	// SentinelMaster is the name of the Sentinel-managed master to connect to.
+	SentinelMaster   string
+	SentinelUsername string
+	SentinelPassword string
+	TLSConfig        *tls.Config
+	// PoolSize, MinIdleConns and PoolTimeout size the connection pool, per node in
+	// cluster mode. Zero values keep the go-redis defaults.
+	PoolSize     int
+	MinIdleConns int
+	PoolTimeout  time.Duration
+	// Codec encodes new values; values written by any codec can be read. Defaults to JSON.
+	Codec Codec
+	// CompressionThreshold is the encoded size in bytes above which values are
+	// zstd-compressed. Zero disables compression.
+	CompressionThreshold int
+	// SkipPing creates the client without checking that Redis answers, for callers that
+	// handle an unreachable Redis themselves.
+	SkipPing bool
 }
 
 type Option func(*Options)
@@ -27,6 +63,13 @@ func WithAddress(addr string) Option {
 	}
 }
 
+// WithUsername sets the ACL user to authenticate as.
+func WithUsername(user string) Option {
+	return func(o *Options) {
+		o.Username = user
+	}
+}
+
 func WithPassword(pass string) Option {
 	return func(o *Options) {
 		o.Password = pass
@@ -39,44 +82,199 @@ func WithDB(db int) Option {
 	}
 }
 
+// WithCluster connects to a Redis Cluster through the given seed nodes.
+func WithCluster(addrs ...string) Option {
+	return func(o *Options) {
+		o.Cluster = true
+		o.Addrs = addrs
+	}
+}
+
+// WithSentinel connects to the master named master, discovered through the given Sentinels.
+func WithSentinel(master string, addrs ...string) Option {
+	return func(o *Options) {
+		o.SentinelMaster = master
+		o.Addrs = addrs
+	}
+}
+
+// WithSentinelAuth sets the credentials for the Sentinels themselves, when they differ
+// from the master's.
+func WithSentinelAuth(user, pass string) Option {
+	return func(o *Options) {
+		o.SentinelUsername = user
+		o.SentinelPassword = pass
+	}
+}
+
+// WithTLS enables TLS with the given configuration.
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *Options) {
+		o.TLSConfig = cfg
+	}
+}
+
+// WithPool sizes the connection pool: at most size connections, keeping minIdle open,
+// and waiting up to timeout for a free one.
+func WithPool(size, minIdle int, timeout time.Duration) Option {
+	return func(o *Options) {
+		o.PoolSize = size
+		o.MinIdleConns = minIdle
+		o.PoolTimeout = timeout
+	}
+}
+
+// WithCodec sets the codec new values are written with.
+func WithCodec(codec Codec) Option {
+	return func(o *Options) {
+		o.Codec = codec
+	}
+}
+
+// WithCompressionThreshold zstd-compresses values whose encoding is larger than n bytes.
+func WithCompressionThreshold(n int) Option {
+	return func(o *Options) {
+		o.CompressionThreshold = n
+	}
+}
+
+// WithoutPing makes New return the client even if Redis cannot be reached yet.
+func WithoutPing() Option {
+	return func(o *Options) {
+		o.SkipPing = true
+	}
+}
+
 func New(ctx context.Context, opts ...Option) (*Cache, error) {
 	options := &Options{
 		Address:  "localhost:6379",
 		Password: "",
 		DB:       0,
+		Codec:    JSONCodec{},
 	}
 
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	client := redis.NewClient(&redis.Options{
-		Addr:     options.Address,
-		Password: options.Password,
-		DB:       options.DB,
+	if options.Codec == nil {
+		return nil, fmt.Errorf("nil cache codec")
+	}
+	if options.CompressionThreshold < 0 {
+		return nil, fmt.Errorf("invalid compression threshold %d: must not be negative", options.CompressionThreshold)
+	}
+	if options.Cluster && options.SentinelMaster != "" {
+		return nil, fmt.Errorf("cluster and sentinel modes are mutually exclusive")
+	}
+	if options.Cluster && options.DB != 0 {
+		return nil, fmt.Errorf("invalid DB %d: Redis Cluster only supports DB 0", options.DB)
+	}
+	if options.PoolSize < 0 || options.MinIdleConns < 0 || options.PoolTimeout < 0 {
+		return nil, fmt.Errorf("invalid pool settings: size, min idle and timeout must not be negative")
+	}
+
+	addrs := options.Addrs
+	if len(addrs) == 0 {
+		if options.Cluster || options.SentinelMaster != "" {
+			return nil, fmt.Errorf("at least one address is required in cluster and sentinel modes")
+		}
+		addrs = []string{options.Address}
+	}
+
+	client := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs:            addrs,
+		IsClusterMode:    options.Cluster,
+		MasterName:       options.SentinelMaster,
+		Username:         options.Username,
+		Password:         options.Password,
+		SentinelUsername: options.SentinelUsername,
+		SentinelPassword: options.SentinelPassword,
+		DB:               options.DB,
+		TLSConfig:        options.TLSConfig,
+		PoolSize:         options.PoolSize,
+		MinIdleConns:     options.MinIdleConns,
+		PoolTimeout:      options.PoolTimeout,
 	})
 
-	if _, err := client.Ping(ctx).Result(); err != nil {
-		return nil, err
+	if !options.SkipPing {
+		if _, err := client.Ping(ctx).Result(); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
 	}
 
-	return &Cache{client: client}, nil
+	_, cluster := client.(*redis.ClusterClient)
+	return &Cache{
+		client:   client,
+		cluster:  cluster,
+		encoding: encoding{codec: options.Codec, threshold: options.CompressionThreshold},
+	}, nil
 }
 
 func (c *Cache) Get(ctx context.Context, key string, dest any) error {
-	val, err := c.client.Get(ctx, key).Result()
+	val, err := c.client.Get(ctx, key).Bytes()
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal([]byte(val), dest)
+	return c.encoding.decode(val, dest)
 }
 
+// GetWithTTL reads key and its remaining TTL in one round trip.
+func (c *Cache) GetWithTTL(ctx context.Context, key string, dest any) (time.Duration, error) {
+	var (
+		get  *redis.StringCmd
+		pttl *redis.DurationCmd
+	)
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	val, _ := get.Bytes()
+	if err := c.encoding.decode(val, dest); err != nil {
+		return 0, err
+	}
+	if pttl.Val() == -2 {
+		// The key expired between the two commands.
+		return 0, nil
+	}
+	return pttl.Val(), nil
+}
+
+// Set stores value under key. Window keys are also added to their month tag sets in the
+// same transaction; a tag set lives as long as the longest-lived key added to it. The TTL is
+// extended with a script rather than EXPIRE NX/GT, so Redis 6 is supported. In cluster mode
+// the key and its tag sets live in different slots, so they are written in a plain pipeline
+// instead.
 func (c *Cache) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
-	data, err := json.Marshal(value)
+	data, err := c.encoding.encode(value)
 	if err != nil {
 		return err
 	}
-	return c.client.Set(ctx, key, data, expiration).Err()
+
+	_, start, end, ok := ParseWindowKey(key)
+	if !ok {
+		return c.client.Set(ctx, key, data, expiration).Err()
+	}
+
+	pipelined := c.client.TxPipelined
+	if c.cluster {
+		pipelined = c.client.Pipelined
+	}
+	_, err = pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, expiration)
+		for _, tag := range windowTags(start, end) {
+			pipe.SAdd(ctx, tag, key)
+			if expiration > 0 {
+				pipe.Eval(ctx, extendTTLScript, []string{tag}, expiration.Milliseconds())
+			}
+		}
+		return nil
+	})
+	return err
 }
 
 func (c *Cache) Close() error {
@@ -117,3 +315,8 @@ func FindAndCache[T any](ctx context.Context, cache *Cache, key string, cacheDur
 		return result, nil
 	}
 }
+
+// Ping checks that Redis is reachable.
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
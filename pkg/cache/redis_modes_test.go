@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alicebob/miniredis/v2/server"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCacheCluster runs against miniredis posing as a single-node cluster that owns every
+// slot. The client still routes by slot, so cross-slot commands fail as on a real cluster.
+func TestCacheCluster(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	c, err := New(ctx, WithCluster(mr.Addr()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+	assert.IsType(t, &redis.ClusterClient{}, c.client)
+
+	key := "grpc:overall_quality_score:2025-01-15:2025-02-15"
+	require.NoError(t, c.Set(ctx, key, 1.5, time.Minute), "window keys and their tag sets span slots")
+	require.NoError(t, c.Set(ctx, "grpc:other:2025-01-01:2025-01-02", 2.0, time.Minute))
+	assert.True(t, mr.Exists("cache:tag:window:2025-02"))
+
+	var v float64
+	require.NoError(t, c.Get(ctx, key, &v))
+	assert.Equal(t, 1.5, v)
+
+	deleted, err := c.Invalidate(ctx, nil,
+		time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	deleted, err = c.Invalidate(ctx, []string{"grpc:other"}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.False(t, mr.Exists(key))
+}
+
+func TestCacheSentinel(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	sentinel := newFakeSentinel(t, "mymaster", mr.Addr())
+
+	c, err := New(ctx, WithSentinel("mymaster", sentinel))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+
+	require.NoError(t, c.Set(ctx, "key", 3.0, time.Minute))
+	assert.True(t, mr.Exists("key"), "writes go to the master the sentinel reported")
+
+	_, err = New(ctx, WithSentinel("unknown", sentinel))
+	assert.Error(t, err)
+}
+
+func TestCacheTLSAndACL(t *testing.T) {
+	ctx := context.Background()
+	serverTLS, clientTLS := testTLSConfigs(t)
+
+	mr := miniredis.NewMiniRedis()
+	require.NoError(t, mr.StartTLS(serverTLS))
+	t.Cleanup(mr.Close)
+	mr.RequireUserAuth("cache", "secret")
+
+	c, err := New(ctx,
+		WithAddress(mr.Addr()),
+		WithTLS(clientTLS),
+		WithUsername("cache"),
+		WithPassword("secret"),
+		WithPool(4, 1, time.Second),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+	require.NoError(t, c.Set(ctx, "key", 1.0, time.Minute))
+
+	_, err = New(ctx, WithAddress(mr.Addr()), WithTLS(clientTLS), WithUsername("cache"), WithPassword("wrong"))
+	assert.Error(t, err)
+
+	_, err = New(ctx, WithAddress(mr.Addr()), WithUsername("cache"), WithPassword("secret"))
+	assert.Error(t, err, "plaintext connections to a TLS server fail")
+}
+
+func TestNewInvalidOptions(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[string][]Option{
+		"cluster and sentinel":  {WithCluster("a:1"), WithSentinel("m", "b:1")},
+		"cluster with DB":       {WithCluster("a:1"), WithDB(1)},
+		"cluster without addr":  {WithCluster()},
+		"sentinel without addr": {WithSentinel("m")},
+		"negative pool size":    {WithPool(-1, 0, 0)},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(ctx, opts...)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// newFakeSentinel serves the subset of the Sentinel protocol go-redis uses to find a
+// master, reporting masterAddr for masterName.
+func newFakeSentinel(t *testing.T, masterName, masterAddr string) string {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(masterAddr)
+	require.NoError(t, err)
+
+	srv, err := server.NewServer("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(srv.Close)
+
+	require.NoError(t, srv.Register("PING", func(c *server.Peer, cmd string, args []string) {
+		c.WriteInline("PONG")
+	}))
+	require.NoError(t, srv.Register("SENTINEL", func(c *server.Peer, cmd string, args []string) {
+		if len(args) == 0 {
+			c.WriteError("ERR wrong number of arguments")
+			return
+		}
+		switch strings.ToLower(args[0]) {
+		case "get-master-addr-by-name":
+			if len(args) < 2 || args[1] != masterName {
+				c.WriteNull()
+				return
+			}
+			c.WriteLen(2)
+			c.WriteBulk(host)
+			c.WriteBulk(port)
+		case "sentinels", "replicas", "slaves":
+			c.WriteLen(0)
+		default:
+			c.WriteError("ERR unknown sentinel subcommand")
+		}
+	}))
+	require.NoError(t, srv.Register("SUBSCRIBE", func(c *server.Peer, cmd string, args []string) {
+		for i, ch := range args {
+			c.WriteLen(3)
+			c.WriteBulk("subscribe")
+			c.WriteBulk(ch)
+			c.WriteInt(i + 1)
+		}
+	}))
+
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(srv.Addr().Port))
+}
+
+// testTLSConfigs returns a server config with a self-signed certificate for 127.0.0.1 and
+// a client config that trusts it.
+func testTLSConfigs(t *testing.T) (*tls.Config, *tls.Config) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "miniredis"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	serverCfg := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	}
+	clientCfg := &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return serverCfg, clientCfg
+}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCache(t *testing.T, opts ...Option) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	c, err := New(context.Background(), append([]Option{WithAddress(mr.Addr())}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+	return c, mr
+}
+
+func TestCacheSetGet(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "plain", map[string]int{"a": 1}, time.Minute))
+
+	var got map[string]int
+	require.NoError(t, c.Get(ctx, "plain", &got))
+	assert.Equal(t, map[string]int{"a": 1}, got)
+}
+
+func TestCacheGetWithTTL(t *testing.T) {
+	c, _ := newTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "expiring", 1.5, time.Minute))
+	require.NoError(t, c.Set(ctx, "forever", 2.5, 0))
+
+	var got float64
+	ttl, err := c.GetWithTTL(ctx, "expiring", &got)
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, got)
+	assert.Equal(t, time.Minute, ttl)
+
+	ttl, err = c.GetWithTTL(ctx, "forever", &got)
+	require.NoError(t, err)
+	assert.Negative(t, ttl)
+
+	_, err = c.GetWithTTL(ctx, "missing", &got)
+	assert.ErrorIs(t, err, redis.Nil)
+}
+
+func TestCacheSetTagsWindowKeys(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "grpc:overall_quality_score:2025-01-30:2025-02-02", 1.5, time.Minute))
+	require.NoError(t, c.Set(ctx, "grpc:overall_quality_score:2025-02-01:2025-02-02", 2.5, 5*time.Minute))
+	require.NoError(t, c.Set(ctx, "plain", 1, time.Minute))
+
+	members, err := mr.Members("cache:tag:window:2025-01")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grpc:overall_quality_score:2025-01-30:2025-02-02"}, members)
+
+	members, err = mr.Members("cache:tag:window:2025-02")
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	assert.Equal(t, time.Minute, mr.TTL("cache:tag:window:2025-01"))
+	assert.Equal(t, 5*time.Minute, mr.TTL("cache:tag:window:2025-02"), "tag set lives as long as its longest-lived key")
+
+	require.NoError(t, c.Set(ctx, "grpc:overall_quality_score:2025-02-03:2025-02-04", 3.5, 2*time.Minute))
+	assert.Equal(t, 5*time.Minute, mr.TTL("cache:tag:window:2025-02"), "a shorter-lived key does not shorten the tag set")
+
+	mr.SAdd("cache:tag:window:2025-03", "stale")
+	require.NoError(t, c.Set(ctx, "grpc:overall_quality_score:2025-03-01:2025-03-02", 4.5, 3*time.Minute))
+	assert.Equal(t, 3*time.Minute, mr.TTL("cache:tag:window:2025-03"), "a tag set without a TTL gets one")
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	ctx := context.Background()
+	keys := []string{
+		"grpc:overall_quality_score:2025-01-01:2025-01-31",
+		"grpc:overall_quality_score:2025-02-01:2025-02-28",
+		"grpc:scores_by_ticket:2025-01-15:2025-01-15",
+		"grpc:scores_by_ticket:2019-01-01:2025-12-31",
+	}
+
+	tests := []struct {
+		name      string
+		prefixes  []string
+		start     time.Time
+		end       time.Time
+		remaining []string
+	}{
+		{
+			name:      "by prefix",
+			prefixes:  []string{"grpc:scores_by_ticket"},
+			remaining: keys[:2],
+		},
+		{
+			name:      "by day",
+			start:     day(2025, 1, 15),
+			end:       day(2025, 1, 15),
+			remaining: []string{keys[1]},
+		},
+		{
+			name:      "by prefix and day",
+			prefixes:  []string{"grpc:overall_quality_score"},
+			start:     day(2025, 2, 10),
+			end:       day(2025, 2, 10),
+			remaining: []string{keys[0], keys[2], keys[3]},
+		},
+		{
+			name:      "open-ended range falls back to scan",
+			start:     day(2025, 2, 1),
+			remaining: []string{keys[0], keys[2]},
+		},
+		{
+			name:      "range longer than the tagged months falls back to scan",
+			start:     day(2010, 1, 1),
+			end:       day(2025, 1, 1),
+			remaining: []string{keys[1], keys[2]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, mr := newTestCache(t)
+			for _, k := range keys {
+				require.NoError(t, c.Set(ctx, k, 1, time.Minute))
+			}
+			require.NoError(t, c.Set(ctx, "other:key", 1, time.Minute))
+
+			deleted, err := c.Invalidate(ctx, tt.prefixes, tt.start, tt.end)
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(keys)-len(tt.remaining)), deleted)
+			for _, k := range keys {
+				assert.Equal(t, contains(tt.remaining, k), mr.Exists(k), k)
+			}
+			assert.True(t, mr.Exists("other:key"))
+		})
+	}
+}
+
+func TestCacheInvalidateEscapesGlob(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a*b:1", 1, time.Minute))
+	require.NoError(t, c.Set(ctx, "axb:1", 1, time.Minute))
+
+	deleted, err := c.Invalidate(ctx, []string{"a*b"}, time.Time{}, time.Time{})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.True(t, mr.Exists("axb:1"))
+}
+
+func TestCacheInvalidateRequiresSelector(t *testing.T) {
+	c, _ := newTestCache(t)
+
+	_, err := c.Invalidate(context.Background(), nil, time.Time{}, time.Time{})
+
+	assert.Error(t, err)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
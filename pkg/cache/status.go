@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TierStatus describes one layer of a cache stack.
+type TierStatus struct {
+	Tier    string
+	Healthy bool
+	Detail  string
+}
+
+// StatusReporter is implemented by backends that can describe their state. Tiers that wrap
+// another backend append its status after their own.
+type StatusReporter interface {
+	Status(ctx context.Context) []TierStatus
+}
+
+// Status returns the status of every tier of b, outermost first.
+func Status(ctx context.Context, b Backend) []TierStatus {
+	if r, ok := b.(StatusReporter); ok {
+		return r.Status(ctx)
+	}
+	return []TierStatus{{Tier: fmt.Sprintf("%T", b), Healthy: true, Detail: "status not reported"}}
+}
+
+// Status pings Redis.
+func (c *Cache) Status(ctx context.Context) []TierStatus {
+	mode := "standalone"
+	if c.cluster {
+		mode = "cluster"
+	}
+	start := time.Now()
+	if err := c.Ping(ctx); err != nil {
+		return []TierStatus{{Tier: "redis", Detail: fmt.Sprintf("%s: %v", mode, err)}}
+	}
+	return []TierStatus{{
+		Tier:    "redis",
+		Healthy: true,
+		Detail:  fmt.Sprintf("%s: ping %s", mode, time.Since(start).Round(time.Microsecond)),
+	}}
+}
+
+// Status reports the memory in use.
+func (m *Memory) Status(context.Context) []TierStatus {
+	return []TierStatus{{
+		Tier:    "memory",
+		Healthy: true,
+		Detail:  fmt.Sprintf("%d of %d bytes", m.Bytes(), m.options.maxBytes),
+	}}
+}
+
+// Status reports that caching is disabled.
+func (Noop) Status(context.Context) []TierStatus {
+	return []TierStatus{{Tier: "none", Healthy: true, Detail: "caching disabled"}}
+}
+
+// Status reports the number of local entries, followed by the next tier.
+func (l *LRU) Status(ctx context.Context) []TierStatus {
+	out := []TierStatus{{
+		Tier:    "lru",
+		Healthy: true,
+		Detail:  fmt.Sprintf("%d of %d entries", l.Len(), l.options.maxEntries),
+	}}
+	if l.next != nil {
+		out = append(out, Status(ctx, l.next)...)
+	}
+	return out
+}
+
+// Status reports which backend serves requests, followed by both backends.
+func (f *Fallback) Status(ctx context.Context) []TierStatus {
+	own := TierStatus{Tier: "fallback", Healthy: true, Detail: "serving from primary"}
+	if f.Degraded() {
+		own = TierStatus{Tier: "fallback", Detail: "degraded: serving from secondary"}
+	}
+	out := []TierStatus{own}
+	out = append(out, Status(ctx, f.primary)...)
+	return append(out, Status(ctx, f.secondary)...)
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusDescribesEveryTier(t *testing.T) {
+	ctx := context.Background()
+	redisCache, mr := newTestCache(t)
+	memory, err := NewMemory(WithMaxBytes(1024))
+	require.NoError(t, err)
+	fallback := NewFallback(redisCache, memory, WithProbeInterval(time.Hour))
+	lru, err := NewLRU(ctx, fallback, WithMaxEntries(10))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = lru.Close()
+	})
+
+	tiers := Status(ctx, lru)
+
+	require.Len(t, tiers, 4)
+	assert.Equal(t, []string{"lru", "fallback", "redis", "memory"},
+		[]string{tiers[0].Tier, tiers[1].Tier, tiers[2].Tier, tiers[3].Tier})
+	for _, tier := range tiers {
+		assert.True(t, tier.Healthy, tier.Tier)
+	}
+	assert.Equal(t, "0 of 10 entries", tiers[0].Detail)
+	assert.Contains(t, tiers[2].Detail, "standalone: ping")
+	assert.Equal(t, "0 of 1024 bytes", tiers[3].Detail)
+
+	mr.Close()
+	var v int
+	_ = fallback.Get(ctx, "k", &v)
+
+	tiers = Status(ctx, lru)
+	assert.False(t, tiers[1].Healthy)
+	assert.Equal(t, "degraded: serving from secondary", tiers[1].Detail)
+	assert.False(t, tiers[2].Healthy)
+}
+
+func TestStatusOfNoop(t *testing.T) {
+	assert.Equal(t, []TierStatus{{Tier: "none", Healthy: true, Detail: "caching disabled"}},
+		Status(context.Background(), NewNoop()))
+}
@@ -0,0 +1,17 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// RegisterMetrics exposes the pool statistics of db (open, in-use and idle connections,
+// wait counts and durations) on the given registerer under the db_name label.
+func RegisterMetrics(reg prometheus.Registerer, db *sql.DB, dbName string) error {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	return reg.Register(collectors.NewDBStatsCollector(db, dbName))
+}
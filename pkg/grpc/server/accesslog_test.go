@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/godilite/qa-server/api/v1"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestLoggingInterceptorAccessLog(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	interceptor := LoggingInterceptor(zap.New(core))
+	info := &grpc.UnaryServerInfo{FullMethod: "/ticketscoring.v1.TicketScoring/GetOverallQualityScore"}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 7), Port: 51234}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("user-agent", "grpcurl/1.9"))
+	ctx = context.WithValue(ctx, requestIDKey{}, "req-7")
+	req := &pb.TimePeriodRequest{
+		StartDate: timestamppb.New(time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2019, 3, 31, 0, 0, 0, 0, time.UTC)),
+	}
+	resp := &pb.OverallQualityScoreResponse{Score: 87.5}
+
+	_, err := interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+		SetPrincipal(ctx, "ops")
+		AddAccessLogFields(ctx, zap.String("cache", "hit"))
+		return resp, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if logs.Len() != 1 {
+		t.Fatalf("Expected one access log entry, got %d", logs.Len())
+	}
+	entry := logs.All()[0]
+	if entry.Level != zapcore.InfoLevel {
+		t.Errorf("Expected info level, got %v", entry.Level)
+	}
+	fields := entry.ContextMap()
+	for key, want := range map[string]any{
+		"method":         info.FullMethod,
+		"status_code":    "OK",
+		"peer":           "10.0.0.7:51234",
+		"user_agent":     "grpcurl/1.9",
+		"request_id":     "req-7",
+		"principal":      "ops",
+		"cache":          "hit",
+		"response_bytes": int64(proto.Size(resp)),
+	} {
+		if fields[key] != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, fields[key])
+		}
+	}
+	request, _ := fields["request"].(map[string]any)
+	if request["start_date"] != "2019-03-01T00:00:00Z" || request["end_date"] != "2019-03-31T00:00:00Z" {
+		t.Errorf("Expected the requested window to be logged, got %v", request)
+	}
+}
+
+// bufconnAddr stands in for the address of a bufconn listener.
+type bufconnAddr struct{}
+
+func (bufconnAddr) Network() string { return "bufconn" }
+func (bufconnAddr) String() string  { return "bufconn" }
+
+func TestAccessLogClientFields(t *testing.T) {
+	md := metadata.Pairs(
+		RemoteAddrHeader, "203.0.113.9:40000",
+		"x-forwarded-for", "198.51.100.1, 203.0.113.9",
+		"user-agent", "grpc-go/1.75",
+		"grpcgateway-user-agent", "curl/8.5",
+	)
+
+	for _, tc := range []struct {
+		name string
+		addr net.Addr
+		want map[string]any
+	}{
+		{
+			name: "gateway over bufconn",
+			addr: bufconnAddr{},
+			want: map[string]any{
+				"peer":          "203.0.113.9:40000",
+				"forwarded_for": "198.51.100.1, 203.0.113.9",
+				"user_agent":    "curl/8.5",
+			},
+		},
+		{
+			name: "metadata is not trusted over tcp",
+			addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 7), Port: 51234},
+			want: map[string]any{
+				"peer":          "10.0.0.7:51234",
+				"forwarded_for": nil,
+				"user_agent":    "grpc-go/1.75",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			core, logs := observer.New(zap.DebugLevel)
+			interceptor := LoggingInterceptor(zap.New(core))
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tc.addr})
+			ctx = metadata.NewIncomingContext(ctx, md)
+
+			_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, func(ctx context.Context, req any) (any, error) {
+				return nil, nil
+			})
+
+			if logs.Len() != 1 {
+				t.Fatalf("Expected one access log entry, got %d", logs.Len())
+			}
+			fields := logs.All()[0].ContextMap()
+			for key, want := range tc.want {
+				if fields[key] != want {
+					t.Errorf("Expected %s=%v, got %v", key, want, fields[key])
+				}
+			}
+		})
+	}
+}
+
+func TestLoggingInterceptorLevels(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	interceptor := LoggingInterceptor(zap.New(core))
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	for _, code := range []codes.Code{codes.NotFound, codes.Internal} {
+		_, _ = interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+			return nil, status.Error(code, "failed")
+		})
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 || entries[0].Level != zapcore.WarnLevel || entries[1].Level != zapcore.ErrorLevel {
+		t.Fatalf("Expected a warning for NotFound and an error for Internal, got %v", entries)
+	}
+	if entries[1].ContextMap()["status_message"] != "failed" {
+		t.Errorf("Expected the status message to be logged, got %v", entries[1].ContextMap())
+	}
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	o := newAccessLogOptions([]AccessLogOption{
+		WithSampleRate(0.5),
+		WithMethodSampleRate("Hot", 0),
+		WithSlowThreshold(time.Second),
+	})
+	o.random = func() float64 { return 0.7 }
+
+	for _, tc := range []struct {
+		method   string
+		code     codes.Code
+		duration time.Duration
+		want     bool
+	}{
+		{"/svc/Hot", codes.OK, time.Millisecond, false},
+		{"/svc/Hot", codes.Internal, time.Millisecond, true},
+		{"/svc/Hot", codes.OK, 2 * time.Second, true},
+		{"/svc/Other", codes.OK, time.Millisecond, false},
+	} {
+		if got := o.sampled(tc.method, tc.code, tc.duration); got != tc.want {
+			t.Errorf("sampled(%s, %s, %s) = %v, want %v", tc.method, tc.code, tc.duration, got, tc.want)
+		}
+	}
+
+	o.random = func() float64 { return 0.2 }
+	if !o.sampled("/svc/Other", codes.OK, time.Millisecond) {
+		t.Error("Expected the default rate to keep some requests")
+	}
+}
+
+func TestAccessLogSampleRatesChange(t *testing.T) {
+	rates := NewSampleRates(1, map[string]float64{"Hot": 0})
+	o := newAccessLogOptions([]AccessLogOption{WithSampleRate(0), WithSampleRates(rates)})
+	o.random = func() float64 { return 0.7 }
+
+	if o.sampled("/svc/Hot", codes.OK, time.Millisecond) {
+		t.Error("Expected the method rate to drop Hot")
+	}
+	if !o.sampled("/svc/Other", codes.OK, time.Millisecond) {
+		t.Error("Expected the shared rates to override WithSampleRate")
+	}
+
+	rates.Set(0, nil)
+	if o.sampled("/svc/Other", codes.OK, time.Millisecond) {
+		t.Error("Expected the new default rate to drop Other")
+	}
+	if !o.sampled("/svc/Hot", codes.Internal, time.Millisecond) {
+		t.Error("Expected failures to be logged at any rate")
+	}
+	rates.Set(0, map[string]float64{"Hot": 1})
+	if !o.sampled("/svc/Hot", codes.OK, time.Millisecond) {
+		t.Error("Expected the new method rate to keep Hot")
+	}
+}
+
+// sendRecvStream is a ServerStream that hands out one request and accepts any response.
+type sendRecvStream struct {
+	fakeStream
+	req proto.Message
+}
+
+func (s *sendRecvStream) RecvMsg(m any) error {
+	proto.Merge(m.(proto.Message), s.req)
+	return nil
+}
+
+func (s *sendRecvStream) SendMsg(any) error { return nil }
+
+func TestStreamLoggingInterceptor(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	interceptor := StreamLoggingInterceptor(zap.New(core))
+	stream := &sendRecvStream{
+		fakeStream: fakeStream{ctx: context.Background()},
+		req:        &pb.ExportScoresRequest{ReportType: pb.ReportType_REPORT_TYPE_SCORES_BY_TICKET},
+	}
+	chunk := &pb.ExportChunk{Data: make([]byte, 100)}
+
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/svc/Export"}, func(srv any, ss grpc.ServerStream) error {
+		var req pb.ExportScoresRequest
+		if err := ss.RecvMsg(&req); err != nil {
+			return err
+		}
+		_ = ss.SendMsg(chunk)
+		return ss.SendMsg(chunk)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fields := logs.All()[0].ContextMap()
+	if fields["responses"] != int64(2) || fields["response_bytes"] != int64(2*proto.Size(chunk)) {
+		t.Errorf("Expected two responses to be counted, got %v", fields)
+	}
+	if request, _ := fields["request"].(map[string]any); request["report_type"] != "REPORT_TYPE_SCORES_BY_TICKET" {
+		t.Errorf("Expected the filters to be logged, got %v", fields["request"])
+	}
+}
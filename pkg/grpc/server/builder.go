@@ -4,22 +4,43 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	health "google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 type Option func(*Options)
 
 type Options struct {
-	port              int
-	logger            *zap.Logger
-	reflection        bool
-	unaryInterceptors []grpc.UnaryServerInterceptor
-	enableLogging     bool
+	port               int
+	addresses          []string
+	listeners          []net.Listener
+	bufconnSize        int
+	logger             *zap.Logger
+	reflection         bool
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+	enableLogging      bool
+	accessLogger       *zap.Logger
+	accessLogOptions   []AccessLogOption
+	enableMetrics      bool
+	enableTracing      bool
+	enableRecovery     bool
+	enableRequestID    bool
+	enableCompression  bool
+	keepalive          keepalive.ServerParameters
+	keepalivePolicy    keepalive.EnforcementPolicy
+	maxRecvMsgSize     int
+	maxSendMsgSize     int
+	maxStreams         uint32
+	limiter            *ConcurrencyLimiter
 }
 
 func WithPort(port int) Option {
@@ -28,6 +49,32 @@ func WithPort(port int) Option {
 	}
 }
 
+// WithAddresses listens on each of addrs instead of :port. An address is host:port, where
+// an empty host means all interfaces, or a Unix domain socket written as unix:/path or
+// unix:///path.
+func WithAddresses(addrs ...string) Option {
+	return func(o *Options) {
+		o.addresses = append(o.addresses, addrs...)
+	}
+}
+
+// WithListener serves on an already open listener, such as one inherited from a process
+// manager, instead of :port.
+func WithListener(lis net.Listener) Option {
+	return func(o *Options) {
+		o.listeners = append(o.listeners, lis)
+	}
+}
+
+// WithBufconn serves on an in-memory listener buffering size bytes, for tests that need no
+// free port or in-process clients. It replaces :port unless addresses are also given.
+// Clients connect through BufconnDialer.
+func WithBufconn(size int) Option {
+	return func(o *Options) {
+		o.bufconnSize = size
+	}
+}
+
 func WithLogger(logger *zap.Logger) Option {
 	return func(o *Options) {
 		o.logger = logger
@@ -46,15 +93,129 @@ func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
 	}
 }
 
+// WithStreamInterceptors appends interceptors for streaming RPCs. Like unary interceptors,
+// they run after the built-in ones, in the order given.
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) Option {
+	return func(o *Options) {
+		o.streamInterceptors = append(o.streamInterceptors, interceptors...)
+	}
+}
+
+// WithLogging writes an access log entry per RPC to the server logger.
 func WithLogging(enabled bool) Option {
 	return func(o *Options) {
 		o.enableLogging = enabled
 	}
 }
 
+// WithAccessLog enables the access log, writing it to logger (the server logger when nil)
+// with the given sampling options.
+func WithAccessLog(logger *zap.Logger, opts ...AccessLogOption) Option {
+	return func(o *Options) {
+		o.enableLogging = true
+		o.accessLogger = logger
+		o.accessLogOptions = append(o.accessLogOptions, opts...)
+	}
+}
+
+func WithMetrics(enabled bool) Option {
+	return func(o *Options) {
+		o.enableMetrics = enabled
+	}
+}
+
+// WithRecovery converts handler panics into codes.Internal errors instead of crashing the
+// process. Enabled by default.
+func WithRecovery(enabled bool) Option {
+	return func(o *Options) {
+		o.enableRecovery = enabled
+	}
+}
+
+// WithRequestID assigns every RPC a request ID, taken from the x-request-id metadata or
+// generated, echoes it in the response headers and adds it to the interceptor logs.
+// Enabled by default.
+func WithRequestID(enabled bool) Option {
+	return func(o *Options) {
+		o.enableRequestID = enabled
+	}
+}
+
+// WithTracing extracts incoming trace context and records a server span per RPC
+// using the globally registered OpenTelemetry tracer provider.
+func WithTracing(enabled bool) Option {
+	return func(o *Options) {
+		o.enableTracing = enabled
+	}
+}
+
+// WithKeepalive pings clients after interval without activity and closes the connection
+// when a ping goes unanswered for timeout, so dead peers behind a load balancer are
+// noticed. Zero keeps gRPC's defaults of 2h and 20s.
+func WithKeepalive(interval, timeout time.Duration) Option {
+	return func(o *Options) {
+		o.keepalive.Time = interval
+		o.keepalive.Timeout = timeout
+	}
+}
+
+// WithKeepaliveEnforcement closes connections of clients that ping more often than
+// minInterval, or at all without an active RPC unless permitWithoutStream is set. Zero
+// keeps gRPC's default of 5m.
+func WithKeepaliveEnforcement(minInterval time.Duration, permitWithoutStream bool) Option {
+	return func(o *Options) {
+		o.keepalivePolicy.MinTime = minInterval
+		o.keepalivePolicy.PermitWithoutStream = permitWithoutStream
+	}
+}
+
+// WithMaxConnectionAge asks clients to reconnect after age, allowing grace for RPCs in
+// flight, so they spread over new replicas after a scale-up. It also closes connections
+// idle for longer than idle. Zero means no limit.
+func WithMaxConnectionAge(age, grace, idle time.Duration) Option {
+	return func(o *Options) {
+		o.keepalive.MaxConnectionAge = age
+		o.keepalive.MaxConnectionAgeGrace = grace
+		o.keepalive.MaxConnectionIdle = idle
+	}
+}
+
+// WithMaxMessageSize limits the size in bytes of messages received and sent. Zero keeps
+// gRPC's defaults of 4 MiB received and 2 GiB sent.
+func WithMaxMessageSize(recv, send int) Option {
+	return func(o *Options) {
+		o.maxRecvMsgSize = recv
+		o.maxSendMsgSize = send
+	}
+}
+
+// WithMaxConcurrentStreams limits the RPCs in flight on one connection. Zero means no limit.
+func WithMaxConcurrentStreams(n uint32) Option {
+	return func(o *Options) {
+		o.maxStreams = n
+	}
+}
+
+// WithCompression gzips responses to clients that accept gzip. Compressed requests are
+// accepted either way.
+func WithCompression(enabled bool) Option {
+	return func(o *Options) {
+		o.enableCompression = enabled
+	}
+}
+
+// WithConcurrencyLimit sheds load with a ConcurrencyLimiter, which runs after the built-in
+// interceptors so rejected RPCs are logged and counted like any other.
+func WithConcurrencyLimit(limiter *ConcurrencyLimiter) Option {
+	return func(o *Options) {
+		o.limiter = limiter
+	}
+}
+
 type Server struct {
 	grpcServer   *grpc.Server
-	lis          net.Listener
+	listeners    []net.Listener
+	bufconn      *bufconn.Listener
 	logger       *zap.Logger
 	healthServer *health.Server
 }
@@ -62,23 +223,25 @@ type Server struct {
 // New creates a new gRPC server using the builder options.
 func New(opts ...Option) (*Server, error) {
 	options := &Options{
-		port:       50051,
-		logger:     zap.NewNop(),
-		reflection: false,
+		port:            50051,
+		logger:          zap.NewNop(),
+		reflection:      false,
+		enableRecovery:  true,
+		enableRequestID: true,
 	}
 
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	// Validate port range
-	if options.port < 1 || options.port > 65535 {
-		return nil, fmt.Errorf("invalid port %d: must be between 1 and 65535", options.port)
+	serverOpts, err := transportOptions(options)
+	if err != nil {
+		return nil, err
 	}
 
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", options.port))
+	listeners, bufListener, err := openListeners(options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to listen on port %d: %w", options.port, err)
+		return nil, err
 	}
 
 	logger := options.logger
@@ -86,17 +249,53 @@ func New(opts ...Option) (*Server, error) {
 		logger = zap.NewNop()
 	}
 
-	serverOpts := []grpc.ServerOption{}
+	if options.enableTracing {
+		serverOpts = append(serverOpts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
 
-	var interceptors []grpc.UnaryServerInterceptor
+	// The request ID comes first so every other interceptor can log it, and recovery comes
+	// after metrics and logging so they record a panic as the Internal error it becomes.
+	var (
+		interceptors       []grpc.UnaryServerInterceptor
+		streamInterceptors []grpc.StreamServerInterceptor
+	)
+	if options.enableRequestID {
+		interceptors = append(interceptors, RequestIDInterceptor())
+		streamInterceptors = append(streamInterceptors, StreamRequestIDInterceptor())
+	}
+	if options.enableCompression {
+		interceptors = append(interceptors, CompressionInterceptor())
+		streamInterceptors = append(streamInterceptors, StreamCompressionInterceptor())
+	}
+	if options.enableMetrics {
+		interceptors = append(interceptors, MetricsInterceptor())
+		streamInterceptors = append(streamInterceptors, StreamMetricsInterceptor())
+	}
 	if options.enableLogging {
-		interceptors = append(interceptors, LoggingInterceptor(logger))
+		accessLogger := options.accessLogger
+		if accessLogger == nil {
+			accessLogger = logger
+		}
+		interceptors = append(interceptors, LoggingInterceptor(accessLogger, options.accessLogOptions...))
+		streamInterceptors = append(streamInterceptors, StreamLoggingInterceptor(accessLogger, options.accessLogOptions...))
+	}
+	if options.enableRecovery {
+		interceptors = append(interceptors, RecoveryInterceptor(logger))
+		streamInterceptors = append(streamInterceptors, StreamRecoveryInterceptor(logger))
+	}
+	if options.limiter != nil {
+		interceptors = append(interceptors, options.limiter.UnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, options.limiter.StreamInterceptor())
 	}
 	interceptors = append(interceptors, options.unaryInterceptors...)
+	streamInterceptors = append(streamInterceptors, options.streamInterceptors...)
 
 	if len(interceptors) > 0 {
 		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(interceptors...))
 	}
+	if len(streamInterceptors) > 0 {
+		serverOpts = append(serverOpts, grpc.ChainStreamInterceptor(streamInterceptors...))
+	}
 
 	grpcServer := grpc.NewServer(serverOpts...)
 
@@ -110,12 +309,50 @@ func New(opts ...Option) (*Server, error) {
 
 	return &Server{
 		grpcServer:   grpcServer,
-		lis:          lis,
+		listeners:    listeners,
+		bufconn:      bufListener,
 		logger:       logger.Named("grpc-server"),
 		healthServer: healthServer,
 	}, nil
 }
 
+// transportOptions validates the connection settings and converts them to server options.
+func transportOptions(o *Options) ([]grpc.ServerOption, error) {
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"keepalive interval", o.keepalive.Time},
+		{"keepalive timeout", o.keepalive.Timeout},
+		{"keepalive enforcement interval", o.keepalivePolicy.MinTime},
+		{"max connection age", o.keepalive.MaxConnectionAge},
+		{"max connection age grace", o.keepalive.MaxConnectionAgeGrace},
+		{"max connection idle", o.keepalive.MaxConnectionIdle},
+	} {
+		if d.value < 0 {
+			return nil, fmt.Errorf("invalid %s %s: must not be negative", d.name, d.value)
+		}
+	}
+	if o.maxRecvMsgSize < 0 || o.maxSendMsgSize < 0 {
+		return nil, fmt.Errorf("invalid max message size %d/%d: must not be negative", o.maxRecvMsgSize, o.maxSendMsgSize)
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveParams(o.keepalive),
+		grpc.KeepaliveEnforcementPolicy(o.keepalivePolicy),
+	}
+	if o.maxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(o.maxRecvMsgSize))
+	}
+	if o.maxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(o.maxSendMsgSize))
+	}
+	if o.maxStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(o.maxStreams))
+	}
+	return opts, nil
+}
+
 // RegisterService allows the main application to register its specific service.
 func (s *Server) RegisterService(registerFunc func(s *grpc.Server)) {
 	registerFunc(s.grpcServer)
@@ -141,17 +378,17 @@ func (s *Server) SetServiceHealth(serviceName string, status healthpb.HealthChec
 	}
 }
 
-// Start runs the server in a goroutine and returns immediately.
+// Start serves every listener in its own goroutine and returns immediately.
 func (s *Server) Start() {
-	s.logger.Info("gRPC server starting", zap.String("addr", s.lis.Addr().String()))
-
-	go func() {
-		if err := s.grpcServer.Serve(s.lis); err != nil {
-			s.logger.Error("gRPC server failed", zap.Error(err))
-		}
-	}()
-
-	s.logger.Info("gRPC server started", zap.String("addr", s.lis.Addr().String()))
+	for _, lis := range s.listeners {
+		s.logger.Info("gRPC server starting", zap.String("addr", listenerAddr(lis)))
+
+		go func() {
+			if err := s.grpcServer.Serve(lis); err != nil {
+				s.logger.Error("gRPC server failed", zap.String("addr", listenerAddr(lis)), zap.Error(err))
+			}
+		}()
+	}
 }
 
 // Shutdown gracefully shuts down the server with a timeout context.
@@ -169,6 +406,14 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		close(done)
 	}()
 
+	// Listeners of a server that was never started are still open; this also removes
+	// their Unix socket files.
+	defer func() {
+		for _, lis := range s.listeners {
+			_ = lis.Close()
+		}
+	}()
+
 	select {
 	case <-done:
 		s.logger.Info("gRPC server stopped")
@@ -180,7 +425,27 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	}
 }
 
-// Addr returns the server's listening address.
+// Addr returns the address of the first listener.
 func (s *Server) Addr() net.Addr {
-	return s.lis.Addr()
+	return s.listeners[0].Addr()
+}
+
+// Addrs returns the address of every listener.
+func (s *Server) Addrs() []net.Addr {
+	addrs := make([]net.Addr, len(s.listeners))
+	for i, lis := range s.listeners {
+		addrs[i] = lis.Addr()
+	}
+	return addrs
+}
+
+// BufconnDialer returns the dialer reaching a server built WithBufconn, for use with
+// grpc.WithContextDialer and a passthrough:///bufnet target, or nil for other servers.
+func (s *Server) BufconnDialer() func(context.Context, string) (net.Conn, error) {
+	if s.bufconn == nil {
+		return nil
+	}
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		return s.bufconn.DialContext(ctx)
+	}
 }
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// encodingRecorder records the compression of the responses a client receives.
+type encodingRecorder struct {
+	mu       sync.Mutex
+	encoding string
+}
+
+func (r *encodingRecorder) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (r *encodingRecorder) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if h, ok := s.(*stats.InHeader); ok {
+		r.mu.Lock()
+		r.encoding = h.Compression
+		r.mu.Unlock()
+	}
+}
+
+func (r *encodingRecorder) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (r *encodingRecorder) HandleConn(context.Context, stats.ConnStats) {}
+
+func bufconnHealthClient(t *testing.T, opts ...Option) (healthpb.HealthClient, *encodingRecorder) {
+	t.Helper()
+	server, err := New(append(opts, WithBufconn(1<<20))...)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server.Start()
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	recorder := &encodingRecorder{}
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(server.BufconnDialer()),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(recorder),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn), recorder
+}
+
+func TestServerCompression(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		client, recorder := bufconnHealthClient(t, WithCompression(enabled))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		cancel()
+		if err != nil {
+			t.Fatalf("Health check failed: %v", err)
+		}
+
+		want := ""
+		if enabled {
+			want = "gzip"
+		}
+		if recorder.encoding != want {
+			t.Errorf("WithCompression(%v): expected response encoding %q, got %q", enabled, want, recorder.encoding)
+		}
+	}
+}
+
+func TestServerMaxMessageSize(t *testing.T) {
+	client, _ := bufconnHealthClient(t, WithMaxMessageSize(1024, 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: strings.Repeat("x", 2048)})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted for an oversized request, got %v", err)
+	}
+}
+
+func TestServerRejectsNegativeTuning(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"keepalive":    WithKeepalive(-time.Second, 0),
+		"enforcement":  WithKeepaliveEnforcement(-time.Second, false),
+		"age":          WithMaxConnectionAge(0, -time.Second, 0),
+		"message size": WithMaxMessageSize(0, -1),
+	} {
+		if _, err := New(opt, WithBufconn(1<<20)); err == nil {
+			t.Errorf("%s: expected an error for a negative setting", name)
+		}
+	}
+}
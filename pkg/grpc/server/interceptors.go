@@ -2,41 +2,348 @@ package server
 
 import (
 	"context"
+	"maps"
+	"math/rand"
+	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
-// LoggingInterceptor creates a gRPC unary interceptor for request/response logging.
-func LoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		start := time.Now()
+// RemoteAddrHeader is the metadata key under which an in-process HTTP gateway passes the
+// address of its client. The access log trusts it, and the x-forwarded-for and
+// grpcgateway-user-agent metadata grpc-gateway adds, only on bufconn connections, whose
+// peer is the in-memory listener rather than the client.
+const RemoteAddrHeader = "x-remote-addr"
 
-		logger.Info("gRPC request started",
-			zap.String("method", info.FullMethod),
-			zap.String("client_addr", "client"))
+// AccessLogOption configures the access log written by LoggingInterceptor.
+type AccessLogOption func(*accessLogOptions)
 
-		resp, err := handler(ctx, req)
-		duration := time.Since(start)
-
-		if err != nil {
-			st, _ := status.FromError(err)
-			logger.Error("gRPC request failed",
-				zap.String("method", info.FullMethod),
-				zap.Duration("duration", duration),
-				zap.String("status_code", st.Code().String()),
-				zap.String("status_message", st.Message()),
-				zap.Error(err))
-		} else {
-			logger.Info("gRPC request completed",
-				zap.String("method", info.FullMethod),
-				zap.Duration("duration", duration),
-				zap.String("status_code", codes.OK.String()))
+type accessLogOptions struct {
+	sampleRate    float64
+	methodRates   map[string]float64
+	rates         *SampleRates
+	slowThreshold time.Duration
+	random        func() float64
+}
+
+// SampleRates holds access log sample rates that can be changed while the server runs.
+type SampleRates struct {
+	mu      sync.RWMutex
+	rate    float64
+	methods map[string]float64
+}
+
+// NewSampleRates creates sample rates logging rate of successful, fast RPCs, overridden
+// per method (named without its service) by methods.
+func NewSampleRates(rate float64, methods map[string]float64) *SampleRates {
+	r := &SampleRates{}
+	r.Set(rate, methods)
+	return r
+}
+
+// Set replaces the sample rates; RPCs ending afterwards use the new ones.
+func (r *SampleRates) Set(rate float64, methods map[string]float64) {
+	methods = maps.Clone(methods)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rate = rate
+	r.methods = methods
+}
+
+func (r *SampleRates) get(method string) float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if rate, ok := r.methods[method]; ok {
+		return rate
+	}
+	return r.rate
+}
+
+// WithSampleRate logs only this fraction of successful, fast RPCs. Failed and slow RPCs are
+// always logged. Defaults to 1.
+func WithSampleRate(rate float64) AccessLogOption {
+	return func(o *accessLogOptions) {
+		o.sampleRate = rate
+	}
+}
+
+// WithMethodSampleRate overrides the sample rate of one method, named without its service
+// (e.g. GetOverallQualityScore), for high-volume RPCs.
+func WithMethodSampleRate(method string, rate float64) AccessLogOption {
+	return func(o *accessLogOptions) {
+		o.methodRates[method] = rate
+	}
+}
+
+// WithSampleRates takes the sample rates from rates, so they can be changed with
+// SampleRates.Set while the server runs. It overrides WithSampleRate and
+// WithMethodSampleRate.
+func WithSampleRates(rates *SampleRates) AccessLogOption {
+	return func(o *accessLogOptions) {
+		o.rates = rates
+	}
+}
+
+// WithSlowThreshold sets the duration from which RPCs are logged regardless of sampling.
+// Zero disables the exception. Defaults to 1s.
+func WithSlowThreshold(d time.Duration) AccessLogOption {
+	return func(o *accessLogOptions) {
+		o.slowThreshold = d
+	}
+}
+
+func newAccessLogOptions(opts []AccessLogOption) *accessLogOptions {
+	o := &accessLogOptions{
+		sampleRate:    1,
+		methodRates:   make(map[string]float64),
+		slowThreshold: time.Second,
+		random:        rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// sampled reports whether an RPC of fullMethod that ended with code after duration is logged.
+func (o *accessLogOptions) sampled(fullMethod string, code codes.Code, duration time.Duration) bool {
+	if code != codes.OK || (o.slowThreshold > 0 && duration >= o.slowThreshold) {
+		return true
+	}
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	var rate float64
+	if o.rates != nil {
+		rate = o.rates.get(method)
+	} else if r, ok := o.methodRates[method]; ok {
+		rate = r
+	} else {
+		rate = o.sampleRate
+	}
+	return rate >= 1 || (rate > 0 && o.random() < rate)
+}
+
+// accessRecord collects what handlers and inner interceptors learn about a request for its
+// access log entry. It is shared through the context, so it can be filled in by code that
+// runs inside the logging interceptor.
+type accessRecord struct {
+	mu        sync.Mutex
+	principal string
+	fields    []zap.Field
+}
+
+type accessRecordKey struct{}
+
+func withAccessRecord(ctx context.Context) (context.Context, *accessRecord) {
+	rec := &accessRecord{}
+	return context.WithValue(ctx, accessRecordKey{}, rec), rec
+}
+
+// SetPrincipal records the identity the request authenticated as in its access log entry.
+func SetPrincipal(ctx context.Context, principal string) {
+	if rec, ok := ctx.Value(accessRecordKey{}).(*accessRecord); ok {
+		rec.mu.Lock()
+		rec.principal = principal
+		rec.mu.Unlock()
+	}
+}
+
+// AddAccessLogFields adds fields, such as whether the response came from the cache, to the
+// access log entry of the request. It does nothing outside an RPC.
+func AddAccessLogFields(ctx context.Context, fields ...zap.Field) {
+	if rec, ok := ctx.Value(accessRecordKey{}).(*accessRecord); ok {
+		rec.mu.Lock()
+		rec.fields = append(rec.fields, fields...)
+		rec.mu.Unlock()
+	}
+}
+
+// accessEntry is one RPC as seen by the logging interceptor.
+type accessEntry struct {
+	method    string
+	start     time.Time
+	request   any
+	err       error
+	respBytes int
+	responses int
+	stream    bool
+}
+
+func (o *accessLogOptions) log(ctx context.Context, logger *zap.Logger, rec *accessRecord, e accessEntry) {
+	duration := time.Since(e.start)
+	st, _ := status.FromError(e.err)
+	if !o.sampled(e.method, st.Code(), duration) {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("method", e.method),
+		zap.String("status_code", st.Code().String()),
+		zap.Duration("duration", duration),
+	}
+	fields = append(fields, clientFields(ctx)...)
+	if id, ok := RequestIDFromContext(ctx); ok {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if msg, ok := e.request.(proto.Message); ok {
+		fields = append(fields, zap.Object("request", protoSummary{msg.ProtoReflect()}))
+	}
+	fields = append(fields, zap.Int("response_bytes", e.respBytes))
+	if e.stream {
+		fields = append(fields, zap.Int("responses", e.responses))
+	}
+
+	rec.mu.Lock()
+	if rec.principal != "" {
+		fields = append(fields, zap.String("principal", rec.principal))
+	}
+	fields = append(fields, rec.fields...)
+	rec.mu.Unlock()
+
+	if e.err != nil {
+		fields = append(fields, zap.String("status_message", st.Message()))
+	}
+	logger.Log(accessLevel(st.Code()), "gRPC access", fields...)
+}
+
+// clientFields describes the caller by its address and user agent. For calls relayed by the
+// gateway over bufconn they are those of the HTTP client, along with the X-Forwarded-For chain.
+func clientFields(ctx context.Context) []zap.Field {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var addr string
+	relayed := false
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr = p.Addr.String()
+		relayed = p.Addr.Network() == "bufconn"
+	}
+
+	ua := lastValue(md, "user-agent")
+	if relayed {
+		if a := lastValue(md, RemoteAddrHeader); a != "" {
+			addr = a
 		}
+		if gua := lastValue(md, "grpcgateway-user-agent"); gua != "" {
+			ua = gua
+		}
+	}
+	var fields []zap.Field
+	if addr != "" {
+		fields = append(fields, zap.String("peer", addr))
+	}
+	if relayed {
+		if xff := md.Get("x-forwarded-for"); len(xff) > 0 {
+			fields = append(fields, zap.String("forwarded_for", strings.Join(xff, ", ")))
+		}
+	}
+	if ua != "" {
+		fields = append(fields, zap.String("user_agent", ua))
+	}
+	return fields
+}
+
+// lastValue returns the last value of key in md, which for metadata set by the gateway is
+// the one it added after any forwarded headers.
+func lastValue(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[len(v)-1]
+	}
+	return ""
+}
 
+// accessLevel logs server faults as errors and client mistakes as warnings.
+func accessLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK:
+		return zapcore.InfoLevel
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.Unimplemented:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.WarnLevel
+	}
+}
+
+// LoggingInterceptor creates a gRPC unary interceptor that writes one access log entry per
+// RPC: method, status, duration, peer, user agent, request ID, authenticated principal, a
+// summary of the request fields (the requested window and filters), response size and any
+// fields added with AddAccessLogFields.
+func LoggingInterceptor(logger *zap.Logger, opts ...AccessLogOption) grpc.UnaryServerInterceptor {
+	o := newAccessLogOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, rec := withAccessRecord(ctx)
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		e := accessEntry{method: info.FullMethod, start: start, request: req, err: err}
+		if msg, ok := resp.(proto.Message); ok && err == nil {
+			e.respBytes = proto.Size(msg)
+		}
+		o.log(ctx, logger, rec, e)
 		return resp, err
 	}
 }
+
+// StreamLoggingInterceptor is the streaming counterpart of LoggingInterceptor. The request
+// summary is taken from the first message received and the response size is the total of
+// all messages sent.
+func StreamLoggingInterceptor(logger *zap.Logger, opts ...AccessLogOption) grpc.StreamServerInterceptor {
+	o := newAccessLogOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, rec := withAccessRecord(ss.Context())
+		counted := &countingStream{ServerStream: ss, ctx: ctx}
+		start := time.Now()
+
+		err := handler(srv, counted)
+
+		o.log(ctx, logger, rec, accessEntry{
+			method:    info.FullMethod,
+			start:     start,
+			request:   counted.first,
+			err:       err,
+			respBytes: counted.bytes,
+			responses: counted.sent,
+			stream:    true,
+		})
+		return err
+	}
+}
+
+// countingStream records the first message received and the messages sent on a stream.
+type countingStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	first any
+	sent  int
+	bytes int
+}
+
+func (s *countingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *countingStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.first == nil {
+		s.first = m
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+		if msg, ok := m.(proto.Message); ok {
+			s.bytes += proto.Size(msg)
+		}
+	}
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.uber.org/zap/zaptest"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -96,7 +97,7 @@ func TestServerBuilderWithLogging(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	conn, err := grpc.NewClient(server.lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		t.Fatalf("Failed to dial server: %v", err)
 	}
@@ -118,3 +119,30 @@ func TestServerBuilderWithLogging(t *testing.T) {
 		t.Errorf("Expected SERVING status, got %v", resp.Status)
 	}
 }
+
+func TestMetricsInterceptor(t *testing.T) {
+	interceptor := MetricsInterceptor()
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/test.Service/MetricsMethod",
+	}
+
+	okBefore := testutil.ToFloat64(rpcHandled.WithLabelValues(info.FullMethod, codes.OK.String()))
+	notFoundBefore := testutil.ToFloat64(rpcHandled.WithLabelValues(info.FullMethod, codes.NotFound.String()))
+
+	_, _ = interceptor(context.Background(), "req", info, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	_, err := interceptor(context.Background(), "req", info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound to pass through, got %v", err)
+	}
+
+	if got := testutil.ToFloat64(rpcHandled.WithLabelValues(info.FullMethod, codes.OK.String())); got != okBefore+1 {
+		t.Errorf("Expected OK counter %v, got %v", okBefore+1, got)
+	}
+	if got := testutil.ToFloat64(rpcHandled.WithLabelValues(info.FullMethod, codes.NotFound.String())); got != notFoundBefore+1 {
+		t.Errorf("Expected NotFound counter %v, got %v", notFoundBefore+1, got)
+	}
+}
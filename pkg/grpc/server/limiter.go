@@ -0,0 +1,322 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LimiterOption configures a ConcurrencyLimiter.
+type LimiterOption func(*limiterOptions)
+
+type limiterOptions struct {
+	initial   int
+	min       int
+	max       int
+	latency   time.Duration
+	queue     int
+	queueWait time.Duration
+	backoff   float64
+	exempt    map[string]bool
+}
+
+// WithLimit sets the concurrency limit each method starts at and the bounds it adapts
+// within. Defaults to 20, between 1 and 200.
+func WithLimit(initial, minLimit, maxLimit int) LimiterOption {
+	return func(o *limiterOptions) {
+		o.initial = initial
+		o.min = minLimit
+		o.max = maxLimit
+	}
+}
+
+// WithLatencyThreshold sets the duration above which a unary RPC counts as a sign of
+// overload and lowers the limit. Defaults to 1s.
+func WithLatencyThreshold(d time.Duration) LimiterOption {
+	return func(o *limiterOptions) {
+		o.latency = d
+	}
+}
+
+// WithQueue lets up to size RPCs per method wait up to wait for a slot when the limit is
+// reached; the rest are rejected at once. Defaults to 50 and 200ms.
+func WithQueue(size int, wait time.Duration) LimiterOption {
+	return func(o *limiterOptions) {
+		o.queue = size
+		o.queueWait = wait
+	}
+}
+
+// WithExemptServices never limits the RPCs of the named services, e.g. the Admin service
+// operators need most when the server is overloaded. The health service is always exempt.
+func WithExemptServices(services ...string) LimiterOption {
+	return func(o *limiterOptions) {
+		for _, s := range services {
+			o.exempt[s] = true
+		}
+	}
+}
+
+// ConcurrencyLimiter bounds the RPCs in flight per method with an AIMD limit: it grows by
+// one for every fast success while the method is busy and shrinks by a tenth for every RPC
+// that is slow, times out or finds the server unavailable. RPCs over the limit queue
+// briefly and are rejected with codes.Unavailable once the queue is full or their wait
+// runs out, so clients retry elsewhere instead of piling up behind the database pool.
+type ConcurrencyLimiter struct {
+	// options is replaced as a whole by SetOptions, under mu.
+	options atomic.Pointer[limiterOptions]
+
+	mu      sync.Mutex
+	methods map[string]*methodLimit
+}
+
+// methodLimit is the limit and queue of one method.
+type methodLimit struct {
+	limit    float64
+	inflight int
+	waiters  []*limitWaiter
+}
+
+type limitWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// NewConcurrencyLimiter creates a limiter using the options. Invalid values fall back to
+// the defaults.
+func NewConcurrencyLimiter(opts ...LimiterOption) *ConcurrencyLimiter {
+	o := &limiterOptions{
+		initial:   20,
+		min:       1,
+		max:       200,
+		latency:   time.Second,
+		queue:     50,
+		queueWait: 200 * time.Millisecond,
+		backoff:   0.9,
+		exempt:    map[string]bool{"grpc.health.v1.Health": true},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.normalize()
+
+	l := &ConcurrencyLimiter{methods: make(map[string]*methodLimit)}
+	l.options.Store(o)
+	return l
+}
+
+// SetOptions applies opts on top of the current settings while the limiter is in use, e.g.
+// on a config reload. Method limits outside new bounds are moved within them; a new
+// initial limit only applies to methods not called yet.
+func (l *ConcurrencyLimiter) SetOptions(opts ...LimiterOption) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	o := *l.options.Load()
+	o.exempt = maps.Clone(o.exempt)
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.normalize()
+	l.options.Store(&o)
+
+	for method, m := range l.methods {
+		m.limit = min(max(m.limit, float64(o.min)), float64(o.max))
+		l.admit(m)
+		l.observe(method, m)
+	}
+}
+
+// normalize replaces invalid values with the nearest valid ones.
+func (o *limiterOptions) normalize() {
+	o.min = max(o.min, 1)
+	o.max = max(o.max, o.min)
+	o.initial = min(max(o.initial, o.min), o.max)
+	o.queue = max(o.queue, 0)
+}
+
+// UnaryInterceptor limits unary RPCs as a whole. Services that can answer some RPCs
+// without the constrained resource, e.g. from a cache, should call Acquire around the part
+// that needs it instead.
+func (l *ConcurrencyLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		release, err := l.Acquire(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		// Deferred so a panic, recovered by an outer interceptor, still frees the slot.
+		defer func() {
+			release(err)
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// Acquire takes a slot for fullMethod, queueing like an intercepted RPC, for callers that
+// only need one around part of an RPC, such as the database query behind a cache miss.
+// The returned release must be called with the outcome of that work to free the slot and
+// adapt the limit.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context, fullMethod string) (release func(err error), err error) {
+	if l.exempt(fullMethod) {
+		return func(error) {}, nil
+	}
+	m, err := l.acquire(ctx, fullMethod)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	return func(err error) {
+		l.release(fullMethod, m, overloaded(err) || time.Since(start) > l.options.Load().latency)
+	}, nil
+}
+
+// StreamInterceptor limits streaming RPCs. A stream holds its slot until it ends, but
+// only its outcome adapts the limit, since long exports are slow by nature.
+func (l *ConcurrencyLimiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		if l.exempt(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		m, err := l.acquire(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			l.release(info.FullMethod, m, overloaded(err))
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// Limits returns the current limit of every method that has been called.
+func (l *ConcurrencyLimiter) Limits() map[string]int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limits := make(map[string]int, len(l.methods))
+	for method, m := range l.methods {
+		limits[method] = int(m.limit)
+	}
+	return limits
+}
+
+func (l *ConcurrencyLimiter) exempt(fullMethod string) bool {
+	service := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndexByte(service, '/'); i >= 0 {
+		service = service[:i]
+	}
+	return l.options.Load().exempt[service]
+}
+
+// acquire takes a slot for fullMethod, queueing for one when the method is at its limit.
+func (l *ConcurrencyLimiter) acquire(ctx context.Context, fullMethod string) (*methodLimit, error) {
+	l.mu.Lock()
+	o := l.options.Load()
+	m, ok := l.methods[fullMethod]
+	if !ok {
+		m = &methodLimit{limit: float64(o.initial)}
+		l.methods[fullMethod] = m
+	}
+	if m.inflight < int(m.limit) && len(m.waiters) == 0 {
+		m.inflight++
+		l.observe(fullMethod, m)
+		l.mu.Unlock()
+		return m, nil
+	}
+	if len(m.waiters) >= o.queue {
+		l.mu.Unlock()
+		rpcShed.WithLabelValues(fullMethod, shedReasonQueueFull).Inc()
+		return nil, status.Error(codes.Unavailable, "server overloaded, retry later")
+	}
+	w := &limitWaiter{ready: make(chan struct{})}
+	m.waiters = append(m.waiters, w)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(o.queueWait)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-w.ready:
+		return m, nil
+	case <-timer.C:
+		err = status.Error(codes.Unavailable, "server overloaded, retry later")
+	case <-ctx.Done():
+		err = status.FromContextError(ctx.Err()).Err()
+	}
+
+	l.mu.Lock()
+	if w.granted {
+		// The slot was handed over as the wait ended; take it rather than leak it.
+		l.mu.Unlock()
+		return m, nil
+	}
+	for i, other := range m.waiters {
+		if other == w {
+			m.waiters = append(m.waiters[:i], m.waiters[i+1:]...)
+			break
+		}
+	}
+	l.mu.Unlock()
+
+	rpcShed.WithLabelValues(fullMethod, shedReasonQueueTimeout).Inc()
+	return nil, err
+}
+
+// release frees the slot of an RPC, adapts the limit to its outcome and hands the slot to
+// the longest waiting RPC if the new limit allows.
+func (l *ConcurrencyLimiter) release(fullMethod string, m *methodLimit, congested bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	o := l.options.Load()
+	if congested {
+		m.limit = max(m.limit*o.backoff, float64(o.min))
+	} else if m.inflight*2 >= int(m.limit) {
+		m.limit = min(m.limit+1, float64(o.max))
+	}
+	m.inflight--
+
+	l.admit(m)
+	l.observe(fullMethod, m)
+}
+
+// admit hands free slots of m to its longest waiting RPCs. l.mu must be held.
+func (l *ConcurrencyLimiter) admit(m *methodLimit) {
+	for len(m.waiters) > 0 && m.inflight < int(m.limit) {
+		w := m.waiters[0]
+		m.waiters = m.waiters[1:]
+		w.granted = true
+		m.inflight++
+		close(w.ready)
+	}
+}
+
+func (l *ConcurrencyLimiter) observe(fullMethod string, m *methodLimit) {
+	rpcConcurrencyLimit.WithLabelValues(fullMethod).Set(float64(int(m.limit)))
+	rpcInflight.WithLabelValues(fullMethod).Set(float64(m.inflight))
+}
+
+// overloaded reports whether err shows the server could not keep up, as opposed to a
+// client mistake or a missing result.
+func overloaded(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.DeadlineExceeded, codes.Unavailable, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const limitedMethod = "/test.Service/Limited"
+
+// blockingCall starts an RPC through interceptor that holds its slot until release is
+// closed, and returns once the RPC is running.
+func blockingCall(t *testing.T, interceptor grpc.UnaryServerInterceptor, release <-chan struct{}) <-chan error {
+	t.Helper()
+	running := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: limitedMethod}, func(context.Context, any) (any, error) {
+			close(running)
+			<-release
+			return "resp", nil
+		})
+		done <- err
+	}()
+	select {
+	case <-running:
+	case err := <-done:
+		t.Fatalf("Expected the call to run, got %v", err)
+	}
+	return done
+}
+
+func call(interceptor grpc.UnaryServerInterceptor, method string, err error) error {
+	_, got := interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) {
+		return "resp", err
+	})
+	return got
+}
+
+func TestConcurrencyLimiterRejectsWhenQueueFull(t *testing.T) {
+	interceptor := NewConcurrencyLimiter(WithLimit(1, 1, 1), WithQueue(0, time.Second)).UnaryInterceptor()
+	release := make(chan struct{})
+	done := blockingCall(t, interceptor, release)
+
+	start := time.Now()
+	err := call(interceptor, limitedMethod, nil)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected an immediate rejection, took %s", elapsed)
+	}
+	if err := call(interceptor, "/test.Service/Other", nil); err != nil {
+		t.Errorf("Expected other methods to have their own limit, got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("Expected the running call to succeed, got %v", err)
+	}
+}
+
+func TestConcurrencyLimiterQueues(t *testing.T) {
+	interceptor := NewConcurrencyLimiter(WithLimit(1, 1, 1), WithQueue(1, 5*time.Second)).UnaryInterceptor()
+	release := make(chan struct{})
+	done := blockingCall(t, interceptor, release)
+
+	queued := make(chan error, 1)
+	go func() { queued <- call(interceptor, limitedMethod, nil) }()
+
+	select {
+	case err := <-queued:
+		t.Fatalf("Expected the call to wait for a slot, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-queued; err != nil {
+		t.Errorf("Expected the queued call to run once a slot freed, got %v", err)
+	}
+	<-done
+}
+
+func TestConcurrencyLimiterQueueTimeout(t *testing.T) {
+	interceptor := NewConcurrencyLimiter(WithLimit(1, 1, 1), WithQueue(1, 20*time.Millisecond)).UnaryInterceptor()
+	release := make(chan struct{})
+	done := blockingCall(t, interceptor, release)
+
+	if err := call(interceptor, limitedMethod, nil); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable after the queue wait, got %v", err)
+	}
+
+	close(release)
+	<-done
+	if err := call(interceptor, limitedMethod, nil); err != nil {
+		t.Errorf("Expected the slot to be free again, got %v", err)
+	}
+}
+
+func TestConcurrencyLimiterAdapts(t *testing.T) {
+	limiter := NewConcurrencyLimiter(WithLimit(10, 2, 11))
+	interceptor := limiter.UnaryInterceptor()
+
+	for range 3 {
+		_ = call(interceptor, limitedMethod, status.Error(codes.DeadlineExceeded, "timed out"))
+	}
+	if got := limiter.Limits()[limitedMethod]; got != 7 {
+		t.Errorf("Expected timeouts to lower the limit to 7, got %d", got)
+	}
+
+	for range 20 {
+		_ = call(interceptor, limitedMethod, status.Error(codes.Unavailable, "database overloaded"))
+	}
+	if got := limiter.Limits()[limitedMethod]; got != 2 {
+		t.Errorf("Expected the limit to stop at its minimum of 2, got %d", got)
+	}
+
+	// A lone fast call keeps half of a limit of 2 busy, so the limit grows.
+	for range 20 {
+		_ = call(interceptor, limitedMethod, nil)
+	}
+	if got := limiter.Limits()[limitedMethod]; got != 3 {
+		t.Errorf("Expected an idle method to grow only while busy, got %d", got)
+	}
+}
+
+func TestConcurrencyLimiterSetOptions(t *testing.T) {
+	limiter := NewConcurrencyLimiter(WithLimit(1, 1, 1), WithQueue(1, 5*time.Second))
+	interceptor := limiter.UnaryInterceptor()
+	release := make(chan struct{})
+	done := blockingCall(t, interceptor, release)
+
+	queued := make(chan error, 1)
+	go func() { queued <- call(interceptor, limitedMethod, nil) }()
+	select {
+	case err := <-queued:
+		t.Fatalf("Expected the call to wait for a slot, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	limiter.SetOptions(WithLimit(2, 2, 4))
+	select {
+	case err := <-queued:
+		if err != nil {
+			t.Errorf("Expected the queued call to run under the raised limit, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the raised limit to admit the queued call")
+	}
+	if got := limiter.Limits()[limitedMethod]; got < 2 || got > 4 {
+		t.Errorf("Expected the limit to move within the new bounds of 2 to 4, got %d", got)
+	}
+
+	limiter.SetOptions(WithLimit(1, 1, 1), WithQueue(0, 0))
+	if got := limiter.Limits()[limitedMethod]; got != 1 {
+		t.Errorf("Expected the limit to move down to the new maximum of 1, got %d", got)
+	}
+	if err := call(interceptor, limitedMethod, nil); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected the emptied queue to reject at once, got %v", err)
+	}
+
+	close(release)
+	<-done
+}
+
+func TestConcurrencyLimiterExemptions(t *testing.T) {
+	limiter := NewConcurrencyLimiter(WithLimit(1, 1, 1), WithQueue(0, 0), WithExemptServices("test.Admin"))
+	interceptor := limiter.UnaryInterceptor()
+	release := make(chan struct{})
+	defer close(release)
+	blockingCall(t, interceptor, release)
+
+	for _, method := range []string{"/grpc.health.v1.Health/Check", "/test.Admin/GetStatus"} {
+		if err := call(interceptor, method, nil); err != nil {
+			t.Errorf("Expected %s to be exempt, got %v", method, err)
+		}
+	}
+	if _, ok := limiter.Limits()["/test.Admin/GetStatus"]; ok {
+		t.Error("Expected exempt methods to have no limit")
+	}
+}
+
+func TestConcurrencyLimiterReleasesOnPanic(t *testing.T) {
+	limiter := NewConcurrencyLimiter(WithLimit(1, 1, 1), WithQueue(0, 0))
+	recovery := RecoveryInterceptor(zap.NewNop())
+	limit := limiter.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: limitedMethod}
+
+	for range 3 {
+		_, err := recovery(context.Background(), "req", info, func(ctx context.Context, req any) (any, error) {
+			return limit(ctx, req, info, func(context.Context, any) (any, error) {
+				panic("boom")
+			})
+		})
+		if status.Code(err) != codes.Internal {
+			t.Fatalf("Expected the panic to become Internal, got %v", err)
+		}
+	}
+
+	limiter.mu.Lock()
+	inflight := limiter.methods[limitedMethod].inflight
+	limiter.mu.Unlock()
+	if inflight != 0 {
+		t.Errorf("Expected panicking calls to free their slots, %d still held", inflight)
+	}
+	if err := call(limit, limitedMethod, nil); err != nil {
+		t.Errorf("Expected the method to accept calls after panics, got %v", err)
+	}
+}
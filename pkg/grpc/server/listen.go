@@ -0,0 +1,105 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// listen opens a listener for addr, which is either host:port (host may be empty to listen
+// on all interfaces) or a Unix domain socket written as unix:/path or unix:///path.
+func listen(addr string) (net.Listener, error) {
+	if path, ok := unixSocketPath(addr); ok {
+		if err := removeStaleSocket(path); err != nil {
+			return nil, err
+		}
+		lis, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		return lis, nil
+	}
+
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("invalid listen address %q: want host:port or unix:/path", addr)
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return lis, nil
+}
+
+func unixSocketPath(addr string) (string, bool) {
+	if path, ok := strings.CutPrefix(addr, "unix://"); ok {
+		return path, true
+	}
+	return strings.CutPrefix(addr, "unix:")
+}
+
+// removeStaleSocket deletes a socket file left behind by a server that did not shut down
+// cleanly, so listening on it does not fail. A socket something still accepts on is kept.
+func removeStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect socket %s: %w", path, err)
+	}
+	if info.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("failed to listen on %s: file exists and is not a socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to listen on %s: socket is in use", path)
+	}
+	return os.Remove(path)
+}
+
+// openListeners opens the listeners configured in options, defaulting to :port. Nothing is
+// left open when it fails.
+func openListeners(options *Options) ([]net.Listener, *bufconn.Listener, error) {
+	listeners := append([]net.Listener(nil), options.listeners...)
+	var bufListener *bufconn.Listener
+	if options.bufconnSize > 0 {
+		bufListener = bufconn.Listen(options.bufconnSize)
+		listeners = append(listeners, bufListener)
+	}
+
+	addresses := options.addresses
+	if len(addresses) == 0 && len(listeners) == 0 {
+		if options.port < 1 || options.port > 65535 {
+			return nil, nil, fmt.Errorf("invalid port %d: must be between 1 and 65535", options.port)
+		}
+		addresses = []string{fmt.Sprintf(":%d", options.port)}
+	}
+
+	for _, addr := range addresses {
+		lis, err := listen(addr)
+		if err != nil {
+			for _, opened := range listeners {
+				_ = opened.Close()
+			}
+			return nil, nil, err
+		}
+		listeners = append(listeners, lis)
+	}
+	return listeners, bufListener, nil
+}
+
+// listenerAddr describes lis for logs; Unix socket addresses are prefixed with unix: as
+// they are configured.
+func listenerAddr(lis net.Listener) string {
+	addr := lis.Addr()
+	if addr.Network() == "unix" {
+		return "unix:" + addr.String()
+	}
+	return addr.String()
+}
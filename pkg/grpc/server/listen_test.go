@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func checkHealth(t *testing.T, target string, opts ...grpc.DialOption) {
+	t.Helper()
+	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		t.Fatalf("Failed to dial %s: %v", target, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Health check through %s failed: %v", target, err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING through %s, got %v", target, resp.Status)
+	}
+}
+
+func TestServerBufconn(t *testing.T) {
+	server, err := New(WithBufconn(1 << 20))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server.Start()
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	checkHealth(t, "passthrough:///bufnet", grpc.WithContextDialer(server.BufconnDialer()))
+}
+
+func TestServerMultipleAddresses(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "grpc.sock")
+	server, err := New(WithAddresses("127.0.0.1:0", "unix://"+socket))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server.Start()
+
+	addrs := server.Addrs()
+	if len(addrs) != 2 || addrs[0].Network() != "tcp" || addrs[1].Network() != "unix" {
+		t.Fatalf("Expected a TCP and a Unix listener, got %v", addrs)
+	}
+	checkHealth(t, addrs[0].String())
+	checkHealth(t, "unix:"+socket)
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("Expected the socket file to be removed on shutdown, got %v", err)
+	}
+}
+
+func TestServerReplacesStaleSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "grpc.sock")
+	stale, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to create socket: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	server, err := New(WithAddresses("unix:" + socket))
+	if err != nil {
+		t.Fatalf("Expected the stale socket to be replaced, got %v", err)
+	}
+	_ = server.Shutdown(context.Background())
+}
+
+func TestServerRejectsUnusableAddresses(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "not-a-socket")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	live, err := net.Listen("unix", filepath.Join(dir, "live.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer live.Close()
+
+	for _, addr := range []string{"50051", "unix:" + file, "unix:" + filepath.Join(dir, "live.sock")} {
+		if _, err := New(WithAddresses("127.0.0.1:0", addr)); err == nil {
+			t.Errorf("Expected %q to be rejected", addr)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	shedReasonQueueFull    = "queue_full"
+	shedReasonQueueTimeout = "queue_timeout"
+)
+
+var (
+	rpcHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Total number of RPCs completed on the server, by method and status code.",
+	}, []string{"grpc_method", "grpc_code"})
+
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Latency of RPCs handled by the server, by method and status code.",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"grpc_method", "grpc_code"})
+
+	rpcConcurrencyLimit = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "grpc_server_concurrency_limit",
+		Help: "Current adaptive concurrency limit of each method.",
+	}, []string{"grpc_method"})
+
+	rpcInflight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "grpc_server_inflight",
+		Help: "RPCs of each method holding a concurrency limiter slot.",
+	}, []string{"grpc_method"})
+
+	rpcShed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_shed_total",
+		Help: "RPCs rejected by the concurrency limiter, by method and reason (queue_full, queue_timeout).",
+	}, []string{"grpc_method", "reason"})
+)
+
+// MetricsInterceptor creates a gRPC unary interceptor that records per-RPC latency and status codes.
+func MetricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err).String()
+		rpcHandled.WithLabelValues(info.FullMethod, code).Inc()
+		rpcDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+
+		return resp, err
+	}
+}
+
+// StreamMetricsInterceptor is the streaming counterpart of MetricsInterceptor.
+func StreamMetricsInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		err := handler(srv, ss)
+
+		code := status.Code(err).String()
+		rpcHandled.WithLabelValues(info.FullMethod, code).Inc()
+		rpcDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
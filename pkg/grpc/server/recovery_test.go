@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStream is a ServerStream that records the headers it is sent.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func (s *fakeStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// fakeTransportStream lets grpc.SetHeader work outside a real server.
+type fakeTransportStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *fakeTransportStream) Method() string { return "/test.Service/Method" }
+
+func (s *fakeTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	interceptor := RecoveryInterceptor(zap.New(core))
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Panics"}
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+
+	_, err := interceptor(ctx, "req", info, func(ctx context.Context, req any) (any, error) {
+		var m map[string]int
+		m["boom"]++
+		return nil, nil
+	})
+
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("Expected Internal, got %v", err)
+	}
+	if logs.Len() != 1 {
+		t.Fatalf("Expected one log entry, got %d", logs.Len())
+	}
+	fields := logs.All()[0].ContextMap()
+	if fields["method"] != info.FullMethod || fields["request_id"] != "req-1" {
+		t.Errorf("Unexpected log fields %v", fields)
+	}
+	if stack, _ := fields["stack"].(string); !strings.Contains(stack, "recovery_test.go") {
+		t.Errorf("Expected the stack trace to point at the panic, got %q", stack)
+	}
+
+	resp, err := interceptor(ctx, "req", info, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	if err != nil || resp != "ok" {
+		t.Errorf("Expected handlers that do not panic to pass through, got %v, %v", resp, err)
+	}
+}
+
+func TestStreamRecoveryInterceptor(t *testing.T) {
+	interceptor := StreamRecoveryInterceptor(zap.NewNop())
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/StreamPanics"}
+
+	err := interceptor(nil, &fakeStream{ctx: context.Background()}, info, func(srv any, ss grpc.ServerStream) error {
+		panic("boom")
+	})
+
+	if status.Code(err) != codes.Internal {
+		t.Errorf("Expected Internal, got %v", err)
+	}
+}
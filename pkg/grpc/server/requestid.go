@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDHeader is the metadata key carrying the request ID in both directions.
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLen bounds client-supplied IDs, which end up in every log line of the request.
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the ID assigned to the request by the request ID interceptor.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// RequestLogger returns logger annotated with the request ID of ctx, if it has one.
+func RequestLogger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return logger.With(zap.String("request_id", id))
+	}
+	return logger
+}
+
+// RequestIDInterceptor creates a gRPC unary interceptor that takes the request ID from the
+// x-request-id metadata, or generates one when it is missing or unusable, makes it
+// available through RequestIDFromContext and echoes it in the response headers.
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, id := withRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+		return handler(ctx, req)
+	}
+}
+
+// StreamRequestIDInterceptor is the streaming counterpart of RequestIDInterceptor.
+func StreamRequestIDInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, id := withRequestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(RequestIDHeader, id))
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func withRequestID(ctx context.Context) (context.Context, string) {
+	id := incomingRequestID(ctx)
+	if id == "" {
+		id = uuid.NewString()
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", id))
+	return context.WithValue(ctx, requestIDKey{}, id), id
+}
+
+// incomingRequestID returns the client's request ID if it is short and printable ASCII.
+func incomingRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ids := md.Get(RequestIDHeader)
+	if len(ids) == 0 || len(ids[0]) > maxRequestIDLen {
+		return ""
+	}
+	for _, r := range ids[0] {
+		if r < 0x21 || r > 0x7e {
+			return ""
+		}
+	}
+	return ids[0]
+}
+
+// contextStream overrides the context of a ServerStream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestIDInterceptor(t *testing.T) {
+	interceptor := RequestIDInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	call := func(md metadata.MD) (seen, echoed string) {
+		stream := &fakeTransportStream{}
+		ctx := grpc.NewContextWithServerTransportStream(metadata.NewIncomingContext(context.Background(), md), stream)
+		_, _ = interceptor(ctx, "req", info, func(ctx context.Context, req any) (any, error) {
+			seen, _ = RequestIDFromContext(ctx)
+			return nil, nil
+		})
+		if ids := stream.header.Get(RequestIDHeader); len(ids) == 1 {
+			echoed = ids[0]
+		}
+		return seen, echoed
+	}
+
+	seen, echoed := call(metadata.Pairs(RequestIDHeader, "abc-123"))
+	if seen != "abc-123" || echoed != "abc-123" {
+		t.Errorf("Expected the client's ID to be used and echoed, got %q and %q", seen, echoed)
+	}
+
+	for name, md := range map[string]metadata.MD{
+		"missing":   metadata.MD{},
+		"too long":  metadata.Pairs(RequestIDHeader, strings.Repeat("a", maxRequestIDLen+1)),
+		"has space": metadata.Pairs(RequestIDHeader, "abc 123"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			seen, echoed := call(md)
+			if _, err := uuid.Parse(seen); err != nil {
+				t.Errorf("Expected a generated UUID, got %q", seen)
+			}
+			if echoed != seen {
+				t.Errorf("Expected %q to be echoed, got %q", seen, echoed)
+			}
+		})
+	}
+}
+
+func TestStreamRequestIDInterceptor(t *testing.T) {
+	interceptor := StreamRequestIDInterceptor()
+	stream := &fakeStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "stream-1"))}
+
+	var seen string
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}, func(srv any, ss grpc.ServerStream) error {
+		seen, _ = RequestIDFromContext(ss.Context())
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if seen != "stream-1" {
+		t.Errorf("Expected the handler to see stream-1, got %q", seen)
+	}
+	if ids := stream.header.Get(RequestIDHeader); len(ids) != 1 || ids[0] != "stream-1" {
+		t.Errorf("Expected stream-1 to be echoed, got %v", ids)
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxSummaryListLen is the longest repeated field listed in full; longer ones are counted.
+const maxSummaryListLen = 10
+
+// protoSummary logs the populated fields of a request message compactly: timestamps as
+// RFC 3339, enums by name, bytes and maps by size, short scalar lists in full.
+type protoSummary struct {
+	m protoreflect.Message
+}
+
+func (s protoSummary) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	s.m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+		switch {
+		case fd.IsMap():
+			enc.AddInt(name+"_len", v.Map().Len())
+		case fd.IsList():
+			list := v.List()
+			if list.Len() > maxSummaryListLen || fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.BytesKind {
+				enc.AddInt(name+"_len", list.Len())
+				break
+			}
+			items := make([]string, list.Len())
+			for i := range items {
+				items[i] = scalarString(fd, list.Get(i))
+			}
+			enc.AddString(name, strings.Join(items, ","))
+		case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+			if ts, ok := v.Message().Interface().(*timestamppb.Timestamp); ok {
+				enc.AddString(name, ts.AsTime().UTC().Format(time.RFC3339))
+				break
+			}
+			_ = enc.AddObject(name, protoSummary{v.Message()})
+		case fd.Kind() == protoreflect.BytesKind:
+			enc.AddInt(name+"_len", len(v.Bytes()))
+		default:
+			enc.AddString(name, scalarString(fd, v))
+		}
+		return true
+	})
+	return nil
+}
+
+func scalarString(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	if fd.Kind() == protoreflect.EnumKind {
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+	}
+	return v.String()
+}
@@ -0,0 +1,301 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// CheckFunc probes one dependency and returns an error when it is unusable.
+type CheckFunc func(ctx context.Context) error
+
+// StatusSetter receives gRPC serving status changes, as implemented by the gRPC server.
+type StatusSetter interface {
+	SetServiceHealth(service string, status healthpb.HealthCheckResponse_ServingStatus)
+}
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	critical bool
+}
+
+type Option func(*Options)
+
+type Options struct {
+	interval time.Duration
+	timeout  time.Duration
+	logger   *zap.Logger
+	checks   []check
+	setter   StatusSetter
+	services []string
+}
+
+// WithInterval sets how often the checks run. Defaults to 10s.
+func WithInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.interval = d
+	}
+}
+
+// WithTimeout bounds each check. Defaults to 2s.
+func WithTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.timeout = d
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *Options) {
+		o.logger = logger
+	}
+}
+
+// WithCheck adds a dependency the server cannot work without: while it fails the server
+// is not ready.
+func WithCheck(name string, fn CheckFunc) Option {
+	return func(o *Options) {
+		o.checks = append(o.checks, check{name: name, fn: fn, critical: true})
+	}
+}
+
+// WithOptionalCheck adds a dependency the server can work without, such as a cache. Its
+// failures are reported but do not affect readiness.
+func WithOptionalCheck(name string, fn CheckFunc) Option {
+	return func(o *Options) {
+		o.checks = append(o.checks, check{name: name, fn: fn})
+	}
+}
+
+// WithServingStatus reports readiness to setter as the status of each of services, where
+// "" is the overall server status.
+func WithServingStatus(setter StatusSetter, services ...string) Option {
+	return func(o *Options) {
+		o.setter = setter
+		o.services = append(o.services, services...)
+	}
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Name      string
+	Critical  bool
+	Err       error
+	CheckedAt time.Time
+}
+
+// Checker periodically probes dependencies. Readiness means every critical check passed
+// in the latest round; liveness means the rounds keep completing, since restarting the
+// process cannot fix a dependency but can fix a wedged one.
+type Checker struct {
+	options *Options
+	logger  *zap.Logger
+
+	mu      sync.RWMutex
+	results map[string]Result
+	ready   *bool
+	lastRun time.Time
+	running bool
+	// draining is set by Drain and keeps the checker not ready for good.
+	draining bool
+	now      func() time.Time
+}
+
+// New creates a Checker using the builder options. Call Refresh or Run to start checking.
+func New(opts ...Option) (*Checker, error) {
+	options := &Options{
+		interval: 10 * time.Second,
+		timeout:  2 * time.Second,
+		logger:   zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.interval <= 0 {
+		return nil, fmt.Errorf("invalid health check interval %s: must be positive", options.interval)
+	}
+	if options.timeout <= 0 {
+		return nil, fmt.Errorf("invalid health check timeout %s: must be positive", options.timeout)
+	}
+	seen := make(map[string]bool, len(options.checks))
+	for _, c := range options.checks {
+		if seen[c.name] {
+			return nil, fmt.Errorf("duplicate health check %q", c.name)
+		}
+		seen[c.name] = true
+	}
+
+	logger := options.logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &Checker{
+		options: options,
+		logger:  logger.Named("health"),
+		results: make(map[string]Result, len(options.checks)),
+		now:     time.Now,
+	}, nil
+}
+
+// Run refreshes the checks every interval until ctx is done.
+func (c *Checker) Run(ctx context.Context) {
+	c.mu.Lock()
+	c.running = true
+	if c.lastRun.IsZero() {
+		c.lastRun = c.now()
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.running = false
+		c.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(c.options.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh runs every check concurrently, records the results and publishes the
+// readiness that follows from them.
+func (c *Checker) Refresh(ctx context.Context) {
+	results := make([]Result, len(c.options.checks))
+	var wg sync.WaitGroup
+	for i, chk := range c.options.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.options.timeout)
+			defer cancel()
+			results[i] = Result{Name: chk.name, Critical: chk.critical, Err: chk.fn(checkCtx), CheckedAt: c.now()}
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		// Checks cut short by shutdown say nothing about the dependencies.
+		return
+	}
+
+	ready := true
+	c.mu.Lock()
+	for _, r := range results {
+		if prev, ok := c.results[r.Name]; !ok || (prev.Err == nil) != (r.Err == nil) {
+			c.logTransition(r)
+		}
+		c.results[r.Name] = r
+		if r.Critical && r.Err != nil {
+			ready = false
+		}
+	}
+	changed := c.ready == nil || *c.ready != ready
+	c.ready = &ready
+	c.lastRun = c.now()
+	draining := c.draining
+	c.mu.Unlock()
+
+	if changed && !draining {
+		c.publish(ready)
+	}
+}
+
+func (c *Checker) logTransition(r Result) {
+	switch {
+	case r.Err == nil:
+		c.logger.Info("dependency healthy", zap.String("check", r.Name))
+	case r.Critical:
+		c.logger.Error("dependency unhealthy", zap.String("check", r.Name), zap.Error(r.Err))
+	default:
+		c.logger.Warn("optional dependency unhealthy", zap.String("check", r.Name), zap.Error(r.Err))
+	}
+}
+
+func (c *Checker) publish(ready bool) {
+	if c.options.setter == nil {
+		return
+	}
+	status := healthpb.HealthCheckResponse_SERVING
+	if !ready {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	for _, service := range c.options.services {
+		c.options.setter.SetServiceHealth(service, status)
+	}
+}
+
+// Drain marks the server as shutting down: from now on it is not ready, whatever the
+// checks say, so load balancers stop sending it new requests.
+func (c *Checker) Drain() {
+	c.mu.Lock()
+	c.draining = true
+	c.mu.Unlock()
+
+	c.publish(false)
+}
+
+// Ready returns nil when every critical check passed in the latest round, and otherwise
+// an error naming the failures. It fails until the first round completes and after Drain.
+func (c *Checker) Ready() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.draining {
+		return errors.New("shutting down")
+	}
+	if c.ready == nil {
+		return errors.New("health checks have not run yet")
+	}
+	var errs []error
+	for _, r := range c.sortedResults() {
+		if r.Critical && r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Name, r.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Live returns an error when Run has stopped completing rounds, which happens when a
+// check ignores its context and hangs.
+func (c *Checker) Live() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.running {
+		return nil
+	}
+	stalled := c.now().Sub(c.lastRun)
+	if limit := 3*c.options.interval + c.options.timeout; stalled > limit {
+		return fmt.Errorf("health checks stalled for %s", stalled.Round(time.Second))
+	}
+	return nil
+}
+
+// Results returns the latest result of each check, sorted by name.
+func (c *Checker) Results() []Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sortedResults()
+}
+
+func (c *Checker) sortedResults() []Result {
+	out := make([]Result, 0, len(c.results))
+	for _, r := range c.results {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
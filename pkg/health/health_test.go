@@ -0,0 +1,193 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type recordingSetter struct {
+	mu       sync.Mutex
+	statuses map[string][]healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (r *recordingSetter) SetServiceHealth(service string, status healthpb.HealthCheckResponse_ServingStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.statuses == nil {
+		r.statuses = make(map[string][]healthpb.HealthCheckResponse_ServingStatus)
+	}
+	r.statuses[service] = append(r.statuses[service], status)
+}
+
+// switchable is a check whose outcome the test controls.
+type switchable struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (s *switchable) set(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *switchable) check(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func TestCheckerReadiness(t *testing.T) {
+	db, cache := &switchable{}, &switchable{}
+	setter := &recordingSetter{}
+	c, err := New(
+		WithCheck("database", db.check),
+		WithOptionalCheck("cache", cache.check),
+		WithServingStatus(setter, "", "svc"),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.Error(t, c.Ready(), "not ready before the first round")
+
+	c.Refresh(ctx)
+	assert.NoError(t, c.Ready())
+
+	cache.set(errors.New("connection refused"))
+	c.Refresh(ctx)
+	assert.NoError(t, c.Ready(), "optional checks do not affect readiness")
+
+	db.set(errors.New("database is locked"))
+	c.Refresh(ctx)
+	assert.EqualError(t, c.Ready(), "database: database is locked")
+
+	db.set(nil)
+	c.Refresh(ctx)
+	assert.NoError(t, c.Ready())
+
+	serving, notServing := healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_NOT_SERVING
+	assert.Equal(t, map[string][]healthpb.HealthCheckResponse_ServingStatus{
+		"":    {serving, notServing, serving},
+		"svc": {serving, notServing, serving},
+	}, setter.statuses, "only changes are published")
+}
+
+func TestCheckerTimesOutChecks(t *testing.T) {
+	c, err := New(
+		WithTimeout(10*time.Millisecond),
+		WithCheck("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+	require.NoError(t, err)
+
+	c.Refresh(context.Background())
+
+	assert.ErrorIs(t, c.Ready(), context.DeadlineExceeded)
+}
+
+func TestCheckerLiveness(t *testing.T) {
+	c, err := New(WithInterval(time.Second), WithTimeout(time.Second))
+	require.NoError(t, err)
+	now := time.Date(2025, 5, 14, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	assert.NoError(t, c.Live(), "live before Run")
+
+	c.running = true
+	c.lastRun = now.Add(-4 * time.Second)
+	assert.NoError(t, c.Live())
+
+	c.lastRun = now.Add(-5 * time.Second)
+	assert.EqualError(t, c.Live(), "health checks stalled for 5s")
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	nop := func(context.Context) error { return nil }
+
+	_, err := New(WithInterval(0))
+	assert.Error(t, err)
+	_, err = New(WithCheck("db", nop), WithOptionalCheck("db", nop))
+	assert.EqualError(t, err, `duplicate health check "db"`)
+}
+
+func TestHandler(t *testing.T) {
+	db := &switchable{}
+	c, err := New(WithCheck("database", db.check))
+	require.NoError(t, err)
+	handler := Handler(c)
+
+	get := func(path string) (int, reportJSON) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var report reportJSON
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		return rec.Code, report
+	}
+
+	code, _ := get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	c.Refresh(context.Background())
+	code, report := get("/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, reportJSON{Status: "ok", Checks: map[string]checkJSON{
+		"database": {Status: "ok", Critical: true},
+	}}, report)
+
+	db.set(errors.New("disk I/O error"))
+	c.Refresh(context.Background())
+	code, report = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "failing", report.Checks["database"].Status)
+	assert.Equal(t, "disk I/O error", report.Checks["database"].Error)
+
+	code, report = get("/healthz")
+	assert.Equal(t, http.StatusOK, code, "failing dependencies do not fail liveness")
+	assert.Equal(t, "ok", report.Status)
+}
+
+func TestServer(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+	c.Refresh(context.Background())
+
+	srv, err := NewServer(c, WithPort(0))
+	require.NoError(t, err)
+	srv.Start()
+	t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+
+	resp, err := http.Get("http://" + srv.Addr().String() + "/readyz")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+}
+
+func TestCheckerDrain(t *testing.T) {
+	setter := &recordingSetter{}
+	c, err := New(WithServingStatus(setter, ""))
+	require.NoError(t, err)
+	ctx := context.Background()
+	c.Refresh(ctx)
+
+	c.Drain()
+	c.Refresh(ctx)
+
+	assert.EqualError(t, c.Ready(), "shutting down")
+	assert.Equal(t, []healthpb.HealthCheckResponse_ServingStatus{
+		healthpb.HealthCheckResponse_SERVING,
+		healthpb.HealthCheckResponse_NOT_SERVING,
+	}, setter.statuses[""], "passing checks do not undo the drain")
+}
@@ -0,0 +1,147 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type checkJSON struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+}
+
+type reportJSON struct {
+	Status string               `json:"status"`
+	Error  string               `json:"error,omitempty"`
+	Checks map[string]checkJSON `json:"checks,omitempty"`
+}
+
+// Handler serves /healthz (liveness) and /readyz (readiness) for Kubernetes probes. Both
+// answer 200 or 503 with a JSON report; /readyz includes the result of every check.
+func Handler(c *Checker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeReport(w, reportJSON{}, c.Live())
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, _ *http.Request) {
+		report := reportJSON{Checks: make(map[string]checkJSON)}
+		for _, r := range c.Results() {
+			check := checkJSON{Status: "ok", Critical: r.Critical}
+			if r.Err != nil {
+				check.Status = "failing"
+				check.Error = r.Err.Error()
+			}
+			report.Checks[r.Name] = check
+		}
+		writeReport(w, report, c.Ready())
+	})
+	return mux
+}
+
+func writeReport(w http.ResponseWriter, report reportJSON, err error) {
+	code := http.StatusOK
+	report.Status = "ok"
+	if err != nil {
+		code = http.StatusServiceUnavailable
+		report.Status = "unavailable"
+		report.Error = err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+type ServerOption func(*ServerOptions)
+
+type ServerOptions struct {
+	port   int
+	logger *zap.Logger
+}
+
+func WithPort(port int) ServerOption {
+	return func(o *ServerOptions) {
+		o.port = port
+	}
+}
+
+func WithServerLogger(logger *zap.Logger) ServerOption {
+	return func(o *ServerOptions) {
+		o.logger = logger
+	}
+}
+
+// Server serves the probe endpoints of a Checker over HTTP.
+type Server struct {
+	httpServer *http.Server
+	lis        net.Listener
+	logger     *zap.Logger
+}
+
+// NewServer creates a probe HTTP server using the builder options.
+func NewServer(c *Checker, opts ...ServerOption) (*Server, error) {
+	options := &ServerOptions{
+		port:   8081,
+		logger: zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.port < 0 || options.port > 65535 {
+		return nil, fmt.Errorf("invalid port %d: must be between 0 and 65535", options.port)
+	}
+
+	logger := options.logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", options.port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on port %d: %w", options.port, err)
+	}
+
+	return &Server{
+		httpServer: &http.Server{
+			Handler:           Handler(c),
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		lis:    lis,
+		logger: logger.Named("health-server"),
+	}, nil
+}
+
+// Start runs the server in a goroutine and returns immediately.
+func (s *Server) Start() {
+	s.logger.Info("health server starting", zap.String("addr", s.lis.Addr().String()))
+
+	go func() {
+		if err := s.httpServer.Serve(s.lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("health server failed", zap.Error(err))
+		}
+	}()
+}
+
+// Shutdown gracefully shuts down the server with a timeout context.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("health server shutting down")
+	// The listener of a server that was never started is still open.
+	defer func() {
+		_ = s.lis.Close()
+	}()
+	return s.httpServer.Shutdown(ctx)
+}
+
+// Addr returns the server's listening address.
+func (s *Server) Addr() net.Addr {
+	return s.lis.Addr()
+}
@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+type Option func(*Options)
+
+type Options struct {
+	port     int
+	path     string
+	logger   *zap.Logger
+	gatherer prometheus.Gatherer
+}
+
+func WithPort(port int) Option {
+	return func(o *Options) {
+		o.port = port
+	}
+}
+
+func WithPath(path string) Option {
+	return func(o *Options) {
+		o.path = path
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *Options) {
+		o.logger = logger
+	}
+}
+
+// WithGatherer overrides the registry that is exposed. Defaults to the global Prometheus registry.
+func WithGatherer(g prometheus.Gatherer) Option {
+	return func(o *Options) {
+		o.gatherer = g
+	}
+}
+
+// Server serves Prometheus metrics over HTTP.
+type Server struct {
+	httpServer *http.Server
+	lis        net.Listener
+	logger     *zap.Logger
+}
+
+// New creates a metrics HTTP server using the builder options.
+func New(opts ...Option) (*Server, error) {
+	options := &Options{
+		port:     9090,
+		path:     "/metrics",
+		logger:   zap.NewNop(),
+		gatherer: prometheus.DefaultGatherer,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.port < 0 || options.port > 65535 {
+		return nil, fmt.Errorf("invalid port %d: must be between 0 and 65535", options.port)
+	}
+	if options.path == "" || options.path[0] != '/' {
+		return nil, fmt.Errorf("invalid metrics path %q: must start with /", options.path)
+	}
+
+	logger := options.logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", options.port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on port %d: %w", options.port, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(options.path, promhttp.HandlerFor(options.gatherer, promhttp.HandlerOpts{}))
+
+	return &Server{
+		httpServer: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		lis:    lis,
+		logger: logger.Named("metrics-server"),
+	}, nil
+}
+
+// Start runs the server in a goroutine and returns immediately.
+func (s *Server) Start() {
+	s.logger.Info("metrics server starting", zap.String("addr", s.lis.Addr().String()))
+
+	go func() {
+		if err := s.httpServer.Serve(s.lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("metrics server failed", zap.Error(err))
+		}
+	}()
+}
+
+// Shutdown gracefully shuts down the server with a timeout context.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("metrics server shutting down")
+	// The listener of a server that was never started is still open.
+	defer func() {
+		_ = s.lis.Close()
+	}()
+	return s.httpServer.Shutdown(ctx)
+}
+
+// Addr returns the server's listening address.
+func (s *Server) Addr() net.Addr {
+	return s.lis.Addr()
+}
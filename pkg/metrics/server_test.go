@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestServerExposesRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "test_requests_total",
+		Help: "Test counter.",
+	})
+	reg.MustRegister(counter)
+	counter.Add(3)
+
+	server, err := New(
+		WithPort(0),
+		WithLogger(zaptest.NewLogger(t)),
+		WithGatherer(reg),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server.Start()
+	defer func() {
+		if err := server.Shutdown(context.Background()); err != nil {
+			t.Logf("Server shutdown error: %v", err)
+		}
+	}()
+
+	resp, err := http.Get("http://" + server.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatalf("Failed to scrape metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200, got %d", resp.StatusCode)
+	}
+	if !strings.Contains(string(body), "test_requests_total 3") {
+		t.Errorf("Expected counter in output, got:\n%s", body)
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	if _, err := New(WithPort(70000)); err == nil {
+		t.Error("Expected error for out of range port")
+	}
+	if _, err := New(WithPort(0), WithPath("metrics")); err == nil {
+		t.Error("Expected error for relative path")
+	}
+}
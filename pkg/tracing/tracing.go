@@ -0,0 +1,197 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.uber.org/zap"
+)
+
+// Supported exporter names.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+type Option func(*Options)
+
+type Options struct {
+	exporter     string
+	serviceName  string
+	otlpEndpoint string
+	otlpInsecure bool
+	filePath     string
+	sampleRatio  float64
+	logger       *zap.Logger
+}
+
+// WithExporter selects where spans are sent: none, stdout, file or otlp.
+func WithExporter(name string) Option {
+	return func(o *Options) {
+		o.exporter = name
+	}
+}
+
+func WithServiceName(name string) Option {
+	return func(o *Options) {
+		o.serviceName = name
+	}
+}
+
+// WithOTLPEndpoint sets the OTLP/gRPC collector address. When empty the exporter
+// falls back to the standard OTEL_EXPORTER_OTLP_* environment variables.
+func WithOTLPEndpoint(endpoint string, insecure bool) Option {
+	return func(o *Options) {
+		o.otlpEndpoint = endpoint
+		o.otlpInsecure = insecure
+	}
+}
+
+// WithFile sets the path spans are appended to by the file exporter.
+func WithFile(path string) Option {
+	return func(o *Options) {
+		o.filePath = path
+	}
+}
+
+// WithSampleRatio sets the fraction of new traces that are sampled. Traces started
+// by a sampled remote parent are always kept.
+func WithSampleRatio(ratio float64) Option {
+	return func(o *Options) {
+		o.sampleRatio = ratio
+	}
+}
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *Options) {
+		o.logger = logger
+	}
+}
+
+// Provider owns the tracer provider installed as the OpenTelemetry global.
+type Provider struct {
+	tp     *sdktrace.TracerProvider
+	closer io.Closer
+	logger *zap.Logger
+}
+
+// New builds a tracer provider for the configured exporter and installs it, together
+// with the W3C trace-context propagator, as the OpenTelemetry global.
+func New(ctx context.Context, opts ...Option) (*Provider, error) {
+	options := &Options{
+		exporter:    ExporterNone,
+		serviceName: "ticket-quality-service",
+		filePath:    "traces.jsonl",
+		sampleRatio: 1.0,
+		logger:      zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.sampleRatio < 0 || options.sampleRatio > 1 {
+		return nil, fmt.Errorf("invalid sample ratio %v: must be between 0 and 1", options.sampleRatio)
+	}
+
+	logger := options.logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	p := &Provider{logger: logger.Named("tracing")}
+
+	var exporter sdktrace.SpanExporter
+	switch options.exporter {
+	case ExporterNone, "":
+		return p, nil
+
+	case ExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("create stdout exporter: %w", err)
+		}
+		exporter = exp
+
+	case ExporterFile:
+		f, err := os.OpenFile(options.filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open trace file %q: %w", options.filePath, err)
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("create file exporter: %w", err)
+		}
+		exporter = exp
+		p.closer = f
+
+	case ExporterOTLP:
+		var clientOpts []otlptracegrpc.Option
+		if options.otlpEndpoint != "" {
+			clientOpts = append(clientOpts, otlptracegrpc.WithEndpoint(options.otlpEndpoint))
+		}
+		if options.otlpInsecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		exp, err := otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp exporter: %w", err)
+		}
+		exporter = exp
+
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", options.exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(options.serviceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	p.tp = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.sampleRatio))),
+	)
+	otel.SetTracerProvider(p.tp)
+
+	p.logger.Info("tracing enabled",
+		zap.String("exporter", options.exporter),
+		zap.Float64("sample_ratio", options.sampleRatio))
+
+	return p, nil
+}
+
+// Shutdown flushes buffered spans and releases the exporter.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p.tp == nil {
+		return nil
+	}
+	err := p.tp.Shutdown(ctx)
+	if p.closer != nil {
+		if cerr := p.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
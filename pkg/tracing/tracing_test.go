@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestFileExporterWritesSpans(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	provider, err := New(context.Background(),
+		WithExporter(ExporterFile),
+		WithFile(path),
+		WithServiceName("tracing-test"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test-span")
+	span.End()
+
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read trace file: %v", err)
+	}
+	if !strings.Contains(string(data), `"Name":"test-span"`) {
+		t.Errorf("Expected span in trace file, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "tracing-test") {
+		t.Errorf("Expected service name in trace file, got:\n%s", data)
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	t.Run("unknown exporter", func(t *testing.T) {
+		if _, err := New(context.Background(), WithExporter("zipkin")); err == nil {
+			t.Error("Expected error for unknown exporter")
+		}
+	})
+
+	t.Run("invalid sample ratio", func(t *testing.T) {
+		if _, err := New(context.Background(), WithSampleRatio(1.5)); err == nil {
+			t.Error("Expected error for sample ratio above 1")
+		}
+	})
+
+	t.Run("none exporter shuts down cleanly", func(t *testing.T) {
+		provider, err := New(context.Background(), WithExporter(ExporterNone))
+		if err != nil {
+			t.Fatalf("Failed to create provider: %v", err)
+		}
+		if err := provider.Shutdown(context.Background()); err != nil {
+			t.Errorf("Expected clean shutdown, got %v", err)
+		}
+	})
+}
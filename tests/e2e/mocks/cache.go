@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/godilite/qa-server/pkg/cache"
 )
 
 type InMemoryCache struct{}
@@ -20,6 +22,10 @@ func (c *InMemoryCache) Close() error {
 	return nil
 }
 
+func (c *InMemoryCache) Invalidate(ctx context.Context, prefixes []string, start, end time.Time) (int64, error) {
+	return 0, nil
+}
+
 type TrackingCache struct {
 	GetCalls int
 	SetCalls int
@@ -57,3 +63,14 @@ func (c *TrackingCache) Set(ctx context.Context, key string, value any, exp time
 func (c *TrackingCache) Close() error {
 	return nil
 }
+
+func (c *TrackingCache) Invalidate(ctx context.Context, prefixes []string, start, end time.Time) (int64, error) {
+	var deleted int64
+	for key := range c.data {
+		if cache.InvalidationMatches(key, prefixes, start, end) {
+			delete(c.data, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}